@@ -0,0 +1,44 @@
+package slog
+
+import "errors"
+
+// ErrNoAuditReporter is the error from Audit when the root logger
+// has no audit Reporter.
+var ErrNoAuditReporter = errors.New("slog: no audit reporter")
+
+// Syncer is a Reporter that can make sure the logs it has
+// reported are stored, such as by syncing a file to disk.
+type Syncer interface {
+	Reporter
+	// Sync stores the logs reported so far.
+	Sync() error
+}
+
+func (l *logger) SetAuditReporter(r Reporter) {
+	l.root.am.Lock()
+	l.root.audit = r
+	l.root.am.Unlock()
+}
+
+func (l *logger) Audit(a ...interface{}) error {
+	item := l.makeLog(LevelInfo, callerPC(1), a)
+	root := l.root
+	root.rm.Lock()
+	hooks := root.hooks
+	root.rm.Unlock()
+	if item = runHooks(hooks, resolveLazy(item)); item == nil {
+		return nil
+	}
+	root.am.Lock()
+	defer root.am.Unlock()
+	if root.audit == nil {
+		return ErrNoAuditReporter
+	}
+	if err := report(root.audit, item); err != nil {
+		return err
+	}
+	if s, ok := root.audit.(Syncer); ok {
+		return s.Sync()
+	}
+	return nil
+}
@@ -0,0 +1,40 @@
+package slog_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/slog"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAudit(t *testing.T) {
+
+	l := slog.New("parent", slog.LevelNothing)
+	require.Equal(t, slog.ErrNoAuditReporter, l.Audit("no reporter"))
+
+	path := filepath.Join(t.TempDir(), "audit.log")
+	f, err := slog.NewFileReporter(path, slog.FileOptions{})
+	require.NoError(t, err)
+	l.SetAuditReporter(f)
+	l.SetBuffer(1, slog.DropNewest)
+
+	require.NoError(t, l.New("users").Audit("deleted user", slog.Fields{"id": 1}))
+	b, err := os.ReadFile(path)
+	require.NoError(t, err)
+	require.True(t, strings.Contains(string(b), "parent>users: ( audit_test.go:"), string(b))
+	require.True(t, strings.Contains(string(b), "deleted user id=1"), string(b))
+
+	require.NoError(t, l.StopContext(context.Background()))
+	require.NoError(t, l.Audit("after stopping"))
+	require.NoError(t, f.Close())
+	require.Equal(t, os.ErrClosed, l.Audit("closed"))
+
+	l.SetAuditReporter(slog.ReporterFunc(func(*slog.Log) {}))
+	l.AddHook(func(*slog.Log) *slog.Log { return nil })
+	require.NoError(t, l.Audit("dropped by the hook"))
+
+}
@@ -0,0 +1,99 @@
+package slog
+
+import (
+	"io"
+	"sync"
+	"time"
+)
+
+// BatchReporter represents types capable of doing something with
+// many logs at once.
+type BatchReporter interface {
+	LogBatch([]*Log)
+}
+
+// BatchReporterFunc is a function type capable of acting as a
+// BatchReporter.
+type BatchReporterFunc func([]*Log)
+
+// LogBatch calls the BatchReporterFunc.
+func (f BatchReporterFunc) LogBatch(logs []*Log) {
+	f(logs)
+}
+
+// Batcher is a Reporter that groups logs into batches for a
+// BatchReporter, which is far more efficient for network sinks.
+type Batcher struct {
+	m      sync.Mutex // protects batch, timer and closed
+	send   sync.Mutex // held while a batch is sent, keeping batches in order
+	r      BatchReporter
+	size   int
+	delay  time.Duration
+	batch  []*Log
+	timer  *time.Timer
+	closed bool
+}
+
+var _ Reporter = (*Batcher)(nil)
+var _ io.Closer = (*Batcher)(nil)
+
+// Batch makes a Batcher that reports batches of logs to r once
+// there are maxSize of them, or maxDelay after the first log of
+// the batch, whichever comes first.
+func Batch(r BatchReporter, maxSize int, maxDelay time.Duration) *Batcher {
+	return &Batcher{r: r, size: maxSize, delay: maxDelay}
+}
+
+// Log adds the log to the batch, reporting the batch if it is
+// full.
+func (b *Batcher) Log(l *Log) {
+	b.m.Lock()
+	if b.closed {
+		b.m.Unlock()
+		return
+	}
+	b.batch = append(b.batch, l)
+	if len(b.batch) >= b.size {
+		b.flush()
+		return
+	}
+	if len(b.batch) == 1 && b.delay > 0 {
+		b.timer = time.AfterFunc(b.delay, b.Flush)
+	}
+	b.m.Unlock()
+}
+
+// Flush reports the logs in the batch now.
+func (b *Batcher) Flush() {
+	b.m.Lock()
+	b.flush()
+}
+
+// flush reports the batch. It must be called with b.m locked,
+// which it unlocks.
+func (b *Batcher) flush() {
+	batch := b.batch
+	b.batch = nil
+	if b.timer != nil {
+		b.timer.Stop()
+		b.timer = nil
+	}
+	b.send.Lock()
+	b.m.Unlock()
+	defer b.send.Unlock()
+	if len(batch) > 0 {
+		b.r.LogBatch(batch)
+	}
+}
+
+// Close reports the logs in the batch, and closes the
+// BatchReporter if it is an io.Closer.
+func (b *Batcher) Close() error {
+	b.m.Lock()
+	b.closed = true
+	b.flush()
+	if c, ok := b.r.(io.Closer); ok {
+		return c.Close()
+	}
+	return nil
+}
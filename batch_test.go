@@ -0,0 +1,62 @@
+package slog_test
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/slog"
+	"github.com/stretchr/testify/require"
+)
+
+// batchRecorder records the sizes of the batches it gets.
+type batchRecorder struct {
+	m       sync.Mutex
+	batches []int
+}
+
+func (r *batchRecorder) LogBatch(logs []*slog.Log) {
+	r.m.Lock()
+	r.batches = append(r.batches, len(logs))
+	r.m.Unlock()
+}
+
+func (r *batchRecorder) sizes() []int {
+	r.m.Lock()
+	defer r.m.Unlock()
+	return append([]int(nil), r.batches...)
+}
+
+func TestBatch(t *testing.T) {
+
+	r := &batchRecorder{}
+	b := slog.Batch(r, 3, time.Hour)
+
+	for i := 0; i < 7; i++ {
+		b.Log(&slog.Log{})
+	}
+	require.Equal(t, []int{3, 3}, r.sizes())
+
+	require.NoError(t, b.Close())
+	require.Equal(t, []int{3, 3, 1}, r.sizes())
+
+	b.Log(&slog.Log{})
+	b.Flush()
+	require.Equal(t, []int{3, 3, 1}, r.sizes())
+
+}
+
+func TestBatchDelay(t *testing.T) {
+
+	r := &batchRecorder{}
+	b := slog.Batch(r, 100, 10*time.Millisecond)
+	defer b.Close()
+
+	b.Log(&slog.Log{})
+	b.Log(&slog.Log{})
+	require.Eventually(t, func() bool {
+		return len(r.sizes()) == 1
+	}, time.Second, time.Millisecond)
+	require.Equal(t, []int{2}, r.sizes())
+
+}
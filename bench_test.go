@@ -0,0 +1,120 @@
+package slog_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/slog"
+	"github.com/stretchr/testify/require"
+)
+
+func newBenchLogger(level slog.Level) slog.RootLogger {
+	l := slog.New("bench", level)
+	l.SetReporter(slog.DiscardReporter)
+	return l
+}
+
+func BenchmarkSkipped(b *testing.B) {
+	l := newBenchLogger(slog.LevelErr)
+	defer l.Stop(0)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		l.Info("message", i)
+	}
+}
+
+func BenchmarkSkippedGuard(b *testing.B) {
+	l := newBenchLogger(slog.LevelErr)
+	defer l.Stop(0)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if l.Info() {
+			l.Info("message", i)
+		}
+	}
+}
+
+func BenchmarkSkippedSourceLevels(b *testing.B) {
+	root := newBenchLogger(slog.LevelErr)
+	defer root.Stop(0)
+	root.SetSourceLevel("bench>other", slog.LevelDebug)
+	l := root.New("child").New("grandchild")
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if l.Info() {
+			l.Info("message", i)
+		}
+	}
+}
+
+func BenchmarkLog(b *testing.B) {
+	l := newBenchLogger(slog.LevelInfo)
+	defer l.Stop(0)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		l.Info("message")
+	}
+}
+
+func BenchmarkLogFields(b *testing.B) {
+	l := newBenchLogger(slog.LevelInfo).With("request", "abc")
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		l.Info("message", slog.Fields{"n": 1})
+	}
+}
+
+func TestSkippedAllocs(t *testing.T) {
+
+	root := newBenchLogger(slog.LevelErr)
+	defer root.Stop(0)
+	root.SetSourceLevel("bench>other", slog.LevelDebug)
+	l := root.New("child")
+
+	allocs := testing.AllocsPerRun(100, func() {
+		if l.Info() {
+			t.Fatal("should skip")
+		}
+	})
+	require.Equal(t, float64(0), allocs)
+
+}
+
+func TestNewAllocs(t *testing.T) {
+
+	l := slog.New("parent", slog.LevelInfo)
+	l.SetSync(true)
+	l.New("http").Close()
+
+	allocs := testing.AllocsPerRun(100, func() {
+		l.New("http").Close()
+	})
+	require.Equal(t, 1.0, allocs)
+
+	// the path kept for the child isn't used once the parent is renamed
+	r := NewTestReporter()
+	l.SetReporter(r)
+	l.SetSource("renamed")
+	l.New("http").Info("renamed")
+	require.Equal(t, slog.Source{"renamed", "http"}, r.logs[0].Source)
+
+}
+
+func BenchmarkNew(b *testing.B) {
+	l := newBenchLogger(slog.LevelInfo)
+	defer l.Stop(0)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		l.New("request").Close()
+	}
+}
+
+func BenchmarkAcquire(b *testing.B) {
+	l := newBenchLogger(slog.LevelInfo)
+	defer l.Stop(0)
+	b.ReportAllocs()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			l.Acquire("request").Release()
+		}
+	})
+}
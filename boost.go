@@ -0,0 +1,84 @@
+package slog
+
+import (
+	"strings"
+	"time"
+)
+
+// boost is a single call to Boost.
+type boost struct {
+	level Level
+	until time.Time
+}
+
+func (l *logger) Boost(sourcePrefix string, level Level, d time.Duration) (cancel func()) {
+	root := l.root
+	sourcePrefix = l.sourceName(sourcePrefix)
+	b := &boost{level: level, until: root.now().Add(d)}
+	root.m.Lock()
+	if root.boosts == nil {
+		root.boosts = make(map[string][]*boost)
+	}
+	root.boosts[sourcePrefix] = append(root.boosts[sourcePrefix], b)
+	root.updateOverrides()
+	root.m.Unlock()
+	return func() {
+		root.m.Lock()
+		bs := root.boosts[sourcePrefix]
+		for i := range bs {
+			if bs[i] == b {
+				bs = append(bs[:i:i], bs[i+1:]...)
+				break
+			}
+		}
+		if len(bs) == 0 {
+			delete(root.boosts, sourcePrefix)
+		} else {
+			root.boosts[sourcePrefix] = bs
+		}
+		root.updateOverrides()
+		root.m.Unlock()
+	}
+}
+
+// boostLevel gets the highest level boosted for the source,
+// or LevelInvalid if it isn't boosted.
+// Expired boosts are removed.
+// Must only be called on the root logger.
+func (l *logger) boostLevel(source string) Level {
+	now := l.now()
+	level := LevelInvalid
+	l.m.Lock()
+	for prefix, bs := range l.boosts {
+		var until time.Time
+		var max Level
+		for _, b := range bs {
+			if b.until.After(until) {
+				until = b.until
+			}
+			if b.level > max {
+				max = b.level
+			}
+		}
+		if !now.Before(until) {
+			delete(l.boosts, prefix)
+			continue
+		}
+		if max > level && hasSourcePrefix(source, prefix) {
+			level = max
+		}
+	}
+	l.updateOverrides()
+	l.m.Unlock()
+	return level
+}
+
+// hasSourcePrefix gets whether the joined source is, or is a
+// child of, the joined prefix.
+func hasSourcePrefix(source, prefix string) bool {
+	if prefix == "" || source == prefix {
+		return true
+	}
+	return strings.HasPrefix(source, prefix) &&
+		strings.HasPrefix(source[len(prefix):], nestedLogSep)
+}
@@ -0,0 +1,95 @@
+package slog_test
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/pat/stop"
+	"github.com/stretchr/slog"
+	"github.com/stretchr/testify/require"
+)
+
+type testClock struct {
+	m   sync.Mutex
+	now time.Time
+}
+
+func (c *testClock) Now() time.Time {
+	c.m.Lock()
+	defer c.m.Unlock()
+	return c.now
+}
+
+func (c *testClock) Add(d time.Duration) {
+	c.m.Lock()
+	c.now = c.now.Add(d)
+	c.m.Unlock()
+}
+
+func TestBoost(t *testing.T) {
+
+	l := slog.New("parent", slog.LevelErr)
+	defer func() {
+		l.Stop(stop.NoWait)
+		<-l.StopChan()
+	}()
+	clock := &testClock{now: time.Date(2015, 1, 1, 0, 0, 0, 0, time.UTC)}
+	slog.SetNow(l, clock.Now)
+
+	db := l.New("db")
+	dbx := l.New("dbx")
+	http := l.New("http")
+
+	l.Boost("parent>db", slog.LevelDebug, 5*time.Minute)
+	require.True(t, db.Debug())
+	require.True(t, db.New("child").Info())
+	require.False(t, dbx.Info())
+	require.False(t, http.Info())
+	require.False(t, l.Info())
+
+	clock.Add(5 * time.Minute)
+	require.False(t, db.Debug())
+	require.True(t, db.Err())
+
+}
+
+func TestBoostCancel(t *testing.T) {
+
+	l := slog.New("parent", slog.LevelErr)
+	defer func() {
+		l.Stop(stop.NoWait)
+		<-l.StopChan()
+	}()
+	clock := &testClock{now: time.Date(2015, 1, 1, 0, 0, 0, 0, time.UTC)}
+	slog.SetNow(l, clock.Now)
+
+	cancel := l.Boost("", slog.LevelInfo, time.Hour)
+	require.True(t, l.Info())
+	cancel()
+	require.False(t, l.Info())
+	cancel() // cancelling twice is safe
+
+}
+
+func TestBoostOverlapping(t *testing.T) {
+
+	l := slog.New("parent", slog.LevelErr)
+	defer func() {
+		l.Stop(stop.NoWait)
+		<-l.StopChan()
+	}()
+	clock := &testClock{now: time.Date(2015, 1, 1, 0, 0, 0, 0, time.UTC)}
+	slog.SetNow(l, clock.Now)
+
+	l.Boost("parent", slog.LevelDebug, time.Minute)
+	cancel := l.Boost("parent", slog.LevelWarn, 10*time.Minute)
+
+	// highest level until the latest deadline
+	clock.Add(5 * time.Minute)
+	require.True(t, l.Debug())
+
+	cancel()
+	require.False(t, l.Warn())
+
+}
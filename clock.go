@@ -0,0 +1,39 @@
+package slog
+
+import "time"
+
+// Clock tells the time, and can be replaced to test code that
+// logs without waiting for time to pass.
+type Clock interface {
+	// Now gets the current time.
+	Now() time.Time
+}
+
+// ClockFunc is a func that can be used as a Clock.
+type ClockFunc func() time.Time
+
+// Now calls f().
+func (f ClockFunc) Now() time.Time {
+	return f()
+}
+
+// SystemClock is the Clock that uses time.Now.
+var SystemClock Clock = ClockFunc(time.Now)
+
+// clockBox holds a Clock, so Clocks of any type can be stored in
+// an atomic.Value.
+type clockBox struct {
+	c Clock
+}
+
+func (l *logger) SetClock(c Clock) {
+	if c == nil {
+		c = SystemClock
+	}
+	l.root.clock.Store(clockBox{c: c})
+}
+
+// now gets the time from the Clock of the root logger.
+func (l *logger) now() time.Time {
+	return l.root.clock.Load().(clockBox).c.Now()
+}
@@ -0,0 +1,38 @@
+package slog_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/slog"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSetClock(t *testing.T) {
+
+	clock := &testClock{now: time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)}
+	l := slog.New("parent", slog.LevelWarn)
+	r := NewTestReporter()
+	l.SetReporter(r)
+	l.SetClock(clock)
+
+	l.Warn("first")
+	cancel := l.Boost("parent", slog.LevelInfo, time.Minute)
+	defer cancel()
+	require.True(t, l.Info("boosted"))
+	clock.Add(time.Minute)
+	require.False(t, l.Info("no longer boosted"))
+	l.Warn("second")
+
+	l.SetClock(nil)
+	l.Warn("third")
+	require.NoError(t, l.StopContext(context.Background()))
+
+	require.Equal(t, 4, len(r.logs))
+	require.Equal(t, time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC), r.logs[0].When)
+	require.Equal(t, time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC), r.logs[1].When)
+	require.Equal(t, time.Date(2020, 1, 2, 3, 5, 5, 0, time.UTC), r.logs[2].When)
+	require.True(t, r.logs[3].When.After(time.Date(2020, 1, 2, 3, 5, 5, 0, time.UTC)))
+
+}
@@ -0,0 +1,389 @@
+// Package cloudwatch provides a slog.Reporter that sends logs to
+// AWS CloudWatch Logs, using only the standard library.
+package cloudwatch
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+	"unicode/utf8"
+
+	"github.com/stretchr/slog"
+)
+
+// The limits of a PutLogEvents request.
+const (
+	maxBatchEvents = 10000
+	maxBatchBytes  = 1048576
+	maxEventBytes  = 262144
+	eventOverhead  = 26
+	maxBatchSpan   = 24 * time.Hour
+)
+
+// Credentials represents AWS credentials.
+type Credentials struct {
+	AccessKeyID     string
+	SecretAccessKey string
+	SessionToken    string
+}
+
+// Options represents the options for a Reporter.
+type Options struct {
+	// Region is the AWS region, defaulting to $AWS_REGION.
+	Region string
+	// Credentials default to $AWS_ACCESS_KEY_ID,
+	// $AWS_SECRET_ACCESS_KEY and $AWS_SESSION_TOKEN.
+	Credentials Credentials
+	// LogGroup and LogStream are where the logs are sent. They are
+	// created if they don't exist.
+	LogGroup  string
+	LogStream string
+	// Formatter formats the message of each event, defaulting to
+	// slog.JSONFormatter.
+	Formatter slog.Formatter
+	// MaxDelay is the longest a log waits to be sent, defaulting
+	// to five seconds.
+	MaxDelay time.Duration
+	// Endpoint is the URL of CloudWatch Logs, defaulting to the
+	// one for the region.
+	Endpoint string
+	// Client is the client used to send logs, defaulting to
+	// one whose requests time out after ten seconds, so Flush
+	// and Close can't wait forever.
+	Client *http.Client
+}
+
+// Reporter is a slog.Reporter that sends batches of logs to a
+// CloudWatch Logs stream, from its own goroutine.
+type Reporter struct {
+	opts    Options
+	b       *slog.Batcher
+	m       sync.RWMutex
+	closed  bool
+	batches chan []*slog.Log
+	stopped chan struct{}
+	dropped uint64
+	pm      sync.Mutex
+	sent    *sync.Cond // broadcast when a batch has been sent
+	pending int        // number of batches queued but not sent
+
+	// used only by the sending goroutine
+	created bool
+	token   string
+}
+
+var _ slog.Reporter = (*Reporter)(nil)
+var _ io.Closer = (*Reporter)(nil)
+
+// New makes a Reporter that sends logs as the options say.
+func New(opts Options) (*Reporter, error) {
+	if opts.Region == "" {
+		opts.Region = os.Getenv("AWS_REGION")
+	}
+	if opts.Credentials == (Credentials{}) {
+		opts.Credentials = Credentials{
+			AccessKeyID:     os.Getenv("AWS_ACCESS_KEY_ID"),
+			SecretAccessKey: os.Getenv("AWS_SECRET_ACCESS_KEY"),
+			SessionToken:    os.Getenv("AWS_SESSION_TOKEN"),
+		}
+	}
+	switch {
+	case opts.Region == "":
+		return nil, errors.New("cloudwatch: no region")
+	case opts.Credentials.AccessKeyID == "" || opts.Credentials.SecretAccessKey == "":
+		return nil, errors.New("cloudwatch: no credentials")
+	case opts.LogGroup == "" || opts.LogStream == "":
+		return nil, errors.New("cloudwatch: no log group or stream")
+	}
+	if opts.Formatter == nil {
+		opts.Formatter = slog.JSONFormatter
+	}
+	if opts.MaxDelay == 0 {
+		opts.MaxDelay = 5 * time.Second
+	}
+	if opts.Endpoint == "" {
+		opts.Endpoint = "https://logs." + opts.Region + ".amazonaws.com/"
+	}
+	if opts.Client == nil {
+		opts.Client = &http.Client{Timeout: 10 * time.Second}
+	}
+	r := &Reporter{
+		opts:    opts,
+		batches: make(chan []*slog.Log, 16),
+		stopped: make(chan struct{}),
+	}
+	r.sent = sync.NewCond(&r.pm)
+	r.b = slog.Batch(slog.BatchReporterFunc(r.queue), maxBatchEvents, opts.MaxDelay)
+	go r.run()
+	return r, nil
+}
+
+// Log adds the log to the batch to be sent.
+func (r *Reporter) Log(l *slog.Log) {
+	r.b.Log(l)
+}
+
+// Dropped gets the number of logs dropped, because the queue was
+// full or they couldn't be sent.
+func (r *Reporter) Dropped() uint64 {
+	return atomic.LoadUint64(&r.dropped)
+}
+
+// Flush sends the logs in the batch now, and waits for them, and
+// those queued before, to be sent.
+func (r *Reporter) Flush() {
+	r.b.Flush()
+	r.pm.Lock()
+	for r.pending > 0 {
+		r.sent.Wait()
+	}
+	r.pm.Unlock()
+}
+
+// Close sends the remaining logs, and stops the Reporter.
+func (r *Reporter) Close() error {
+	r.b.Close()
+	r.m.Lock()
+	if !r.closed {
+		r.closed = true
+		close(r.batches)
+	}
+	r.m.Unlock()
+	<-r.stopped
+	return nil
+}
+
+func (r *Reporter) queue(logs []*slog.Log) {
+	r.m.RLock()
+	defer r.m.RUnlock()
+	if r.closed {
+		atomic.AddUint64(&r.dropped, uint64(len(logs)))
+		return
+	}
+	r.pm.Lock()
+	r.pending++
+	r.pm.Unlock()
+	select {
+	case r.batches <- logs:
+	default:
+		atomic.AddUint64(&r.dropped, uint64(len(logs)))
+		r.doneSending()
+	}
+}
+
+// doneSending records that a queued batch has been sent, or
+// dropped.
+func (r *Reporter) doneSending() {
+	r.pm.Lock()
+	r.pending--
+	r.sent.Broadcast()
+	r.pm.Unlock()
+}
+
+func (r *Reporter) run() {
+	defer close(r.stopped)
+	for logs := range r.batches {
+		for _, events := range r.events(logs) {
+			if err := r.put(events); err != nil {
+				atomic.AddUint64(&r.dropped, uint64(len(events)))
+			}
+		}
+		r.doneSending()
+	}
+}
+
+type event struct {
+	Timestamp int64  `json:"timestamp"`
+	Message   string `json:"message"`
+}
+
+// events makes events from the logs, in order of time, and splits
+// them into batches within the limits of PutLogEvents.
+func (r *Reporter) events(logs []*slog.Log) [][]event {
+	events := make([]event, 0, len(logs))
+	for _, l := range logs {
+		message := strings.TrimSuffix(string(r.opts.Formatter.Format(l)), "\n")
+		if message == "" {
+			continue
+		}
+		message = truncate(message, maxEventBytes-eventOverhead)
+		events = append(events, event{Timestamp: l.When.UnixNano() / int64(time.Millisecond), Message: message})
+	}
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].Timestamp < events[j].Timestamp
+	})
+	var batches [][]event
+	start, size := 0, 0
+	for i, e := range events {
+		n := len(e.Message) + eventOverhead
+		if i > start && (size+n > maxBatchBytes || i-start == maxBatchEvents ||
+			time.Duration(e.Timestamp-events[start].Timestamp)*time.Millisecond > maxBatchSpan) {
+			batches = append(batches, events[start:i])
+			start, size = i, 0
+		}
+		size += n
+	}
+	if start < len(events) {
+		batches = append(batches, events[start:])
+	}
+	return batches
+}
+
+// truncate cuts the message short, ending with
+// slog.TruncatedSuffix, if it is longer than max bytes, as a single
+// event bigger than CloudWatch allows gets the whole batch rejected.
+func truncate(message string, max int) string {
+	if len(message) <= max {
+		return message
+	}
+	n := max - len(slog.TruncatedSuffix)
+	for n > 0 && !utf8.RuneStart(message[n]) {
+		n--
+	}
+	return message[:n] + slog.TruncatedSuffix
+}
+
+// put sends the events, creating the log group and stream the
+// first time, and retrying once with the expected sequence token
+// if it is wrong. Events CloudWatch says it has already accepted
+// aren't sent again.
+func (r *Reporter) put(events []event) error {
+	if !r.created {
+		if err := r.create(); err != nil {
+			return err
+		}
+		r.created = true
+	}
+	for attempt := 0; ; attempt++ {
+		req := map[string]interface{}{
+			"logGroupName":  r.opts.LogGroup,
+			"logStreamName": r.opts.LogStream,
+			"logEvents":     events,
+		}
+		if r.token != "" {
+			req["sequenceToken"] = r.token
+		}
+		var res struct {
+			NextSequenceToken string `json:"nextSequenceToken"`
+		}
+		err := r.call("PutLogEvents", req, &res)
+		if aerr, ok := err.(*apiError); ok {
+			if aerr.is("DataAlreadyAcceptedException") {
+				r.token = aerr.expectedToken()
+				return nil
+			}
+			if attempt == 0 && aerr.is("InvalidSequenceTokenException") && aerr.expectedToken() != "" {
+				r.token = aerr.expectedToken()
+				continue
+			}
+		}
+		if err != nil {
+			return err
+		}
+		r.token = res.NextSequenceToken
+		return nil
+	}
+}
+
+// create creates the log group and stream, if they don't exist.
+func (r *Reporter) create() error {
+	err := r.call("CreateLogGroup", map[string]string{"logGroupName": r.opts.LogGroup}, nil)
+	if err != nil && !isExists(err) {
+		return err
+	}
+	err = r.call("CreateLogStream", map[string]string{
+		"logGroupName":  r.opts.LogGroup,
+		"logStreamName": r.opts.LogStream,
+	}, nil)
+	if err != nil && !isExists(err) {
+		return err
+	}
+	return nil
+}
+
+// apiError is an error response from CloudWatch Logs.
+type apiError struct {
+	Type          string `json:"__type"`
+	Message       string `json:"message"`
+	ExpectedToken string `json:"expectedSequenceToken"`
+}
+
+func (e *apiError) Error() string {
+	return "cloudwatch: " + e.Type + ": " + e.Message
+}
+
+// is gets whether the error is of the type, which may be
+// qualified with a namespace.
+func (e *apiError) is(typ string) bool {
+	return strings.HasSuffix(e.Type, typ)
+}
+
+// expectedToken gets the sequence token an
+// InvalidSequenceTokenException or DataAlreadyAcceptedException
+// says is next, from the error or its message.
+func (e *apiError) expectedToken() string {
+	if e.ExpectedToken != "" {
+		return e.ExpectedToken
+	}
+	const prefix = "sequenceToken"
+	i := strings.LastIndex(e.Message, prefix)
+	if i < 0 {
+		return ""
+	}
+	// "sequenceToken is: 123" or "sequenceToken: 123"
+	token := strings.TrimPrefix(strings.TrimSpace(e.Message[i+len(prefix):]), "is")
+	if !strings.HasPrefix(token, ":") {
+		return ""
+	}
+	return strings.TrimSpace(token[1:])
+}
+
+func isExists(err error) bool {
+	aerr, ok := err.(*apiError)
+	return ok && aerr.is("ResourceAlreadyExistsException")
+}
+
+// call calls the action of the CloudWatch Logs API, decoding the
+// response into res if it isn't nil.
+func (r *Reporter) call(action string, req, res interface{}) error {
+	body, err := json.Marshal(req)
+	if err != nil {
+		return err
+	}
+	hreq, err := http.NewRequest("POST", r.opts.Endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	hreq.Header.Set("Content-Type", "application/x-amz-json-1.1")
+	hreq.Header.Set("X-Amz-Target", "Logs_20140328."+action)
+	sign(hreq, body, r.opts.Credentials, r.opts.Region, time.Now())
+	hres, err := r.opts.Client.Do(hreq)
+	if err != nil {
+		return err
+	}
+	defer hres.Body.Close()
+	b, err := io.ReadAll(hres.Body)
+	if err != nil {
+		return err
+	}
+	if hres.StatusCode >= 300 {
+		aerr := &apiError{}
+		if json.Unmarshal(b, aerr) != nil || aerr.Type == "" {
+			return fmt.Errorf("cloudwatch: %s calling %s", hres.Status, action)
+		}
+		return aerr
+	}
+	if res != nil && len(b) > 0 {
+		return json.Unmarshal(b, res)
+	}
+	return nil
+}
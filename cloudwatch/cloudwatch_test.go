@@ -0,0 +1,189 @@
+package cloudwatch_test
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/slog"
+	"github.com/stretchr/slog/cloudwatch"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReporter(t *testing.T) {
+
+	var m sync.Mutex
+	var actions, auths []string
+	var puts []map[string]interface{}
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		m.Lock()
+		defer m.Unlock()
+		action := strings.TrimPrefix(r.Header.Get("X-Amz-Target"), "Logs_20140328.")
+		actions = append(actions, action)
+		auths = append(auths, r.Header.Get("Authorization"))
+		switch action {
+		case "CreateLogGroup":
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"__type":"ResourceAlreadyExistsException","message":"exists"}`))
+		case "CreateLogStream":
+		case "PutLogEvents":
+			var put map[string]interface{}
+			json.Unmarshal(body, &put)
+			if put["sequenceToken"] == nil {
+				w.WriteHeader(http.StatusBadRequest)
+				w.Write([]byte(`{"__type":"InvalidSequenceTokenException","message":"The given sequenceToken is invalid. The next expected sequenceToken is: 123"}`))
+				return
+			}
+			puts = append(puts, put)
+			w.Write([]byte(`{"nextSequenceToken":"456"}`))
+		}
+	}))
+	defer s.Close()
+
+	r, err := cloudwatch.New(cloudwatch.Options{
+		Region:      "us-east-1",
+		Credentials: cloudwatch.Credentials{AccessKeyID: "AKID", SecretAccessKey: "secret"},
+		LogGroup:    "app",
+		LogStream:   "web-1",
+		Formatter: slog.FormatterFunc(func(l *slog.Log) []byte {
+			return []byte(l.Data[0].(string) + "\n")
+		}),
+		Endpoint: s.URL,
+	})
+	require.NoError(t, err)
+
+	when := time.Date(2015, 1, 2, 3, 4, 5, 0, time.UTC)
+	r.Log(&slog.Log{When: when.Add(time.Second), Data: []interface{}{"two"}})
+	r.Log(&slog.Log{When: when, Data: []interface{}{"one"}})
+	require.NoError(t, r.Close())
+
+	require.Equal(t, []string{"CreateLogGroup", "CreateLogStream", "PutLogEvents", "PutLogEvents"}, actions)
+	for _, auth := range auths {
+		require.True(t, strings.HasPrefix(auth, "AWS4-HMAC-SHA256 Credential=AKID/"))
+		require.True(t, strings.Contains(auth, "/us-east-1/logs/aws4_request, SignedHeaders=content-type;host;x-amz-date;x-amz-target, Signature="))
+	}
+	require.Equal(t, 1, len(puts))
+	require.Equal(t, "123", puts[0]["sequenceToken"])
+	require.Equal(t, []interface{}{
+		map[string]interface{}{"timestamp": float64(1420167845000), "message": "one"},
+		map[string]interface{}{"timestamp": float64(1420167846000), "message": "two"},
+	}, puts[0]["logEvents"])
+	require.Equal(t, uint64(0), r.Dropped())
+
+}
+
+func TestNewErrors(t *testing.T) {
+
+	t.Setenv("AWS_REGION", "")
+	t.Setenv("AWS_ACCESS_KEY_ID", "")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "")
+	_, err := cloudwatch.New(cloudwatch.Options{LogGroup: "app", LogStream: "web-1"})
+	require.Error(t, err)
+
+	t.Setenv("AWS_REGION", "us-east-1")
+	_, err = cloudwatch.New(cloudwatch.Options{LogGroup: "app", LogStream: "web-1"})
+	require.Error(t, err)
+
+	t.Setenv("AWS_ACCESS_KEY_ID", "AKID")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "secret")
+	_, err = cloudwatch.New(cloudwatch.Options{})
+	require.Error(t, err)
+
+}
+
+func TestReporterFlush(t *testing.T) {
+
+	var m sync.Mutex
+	var events int
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Amz-Target") != "Logs_20140328.PutLogEvents" {
+			return
+		}
+		var put struct {
+			LogEvents []interface{} `json:"logEvents"`
+		}
+		json.NewDecoder(r.Body).Decode(&put)
+		time.Sleep(10 * time.Millisecond)
+		m.Lock()
+		events += len(put.LogEvents)
+		m.Unlock()
+	}))
+	defer s.Close()
+
+	r, err := cloudwatch.New(cloudwatch.Options{
+		Region:      "us-east-1",
+		Credentials: cloudwatch.Credentials{AccessKeyID: "AKID", SecretAccessKey: "secret"},
+		LogGroup:    "app",
+		LogStream:   "web-1",
+		MaxDelay:    time.Hour,
+		Endpoint:    s.URL,
+	})
+	require.NoError(t, err)
+	defer r.Close()
+	r.Log(&slog.Log{When: time.Now(), Data: []interface{}{"one"}})
+	r.Log(&slog.Log{When: time.Now(), Data: []interface{}{"two"}})
+	r.Flush()
+
+	// the events have been sent without closing the Reporter
+	m.Lock()
+	require.Equal(t, 2, events)
+	m.Unlock()
+
+}
+
+func TestReporterAlreadyAccepted(t *testing.T) {
+
+	var m sync.Mutex
+	var puts []map[string]interface{}
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Amz-Target") != "Logs_20140328.PutLogEvents" {
+			return
+		}
+		var put map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&put)
+		m.Lock()
+		defer m.Unlock()
+		puts = append(puts, put)
+		if len(puts) == 1 {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"__type":"DataAlreadyAcceptedException","message":"The given batch of log events has already been accepted. The next batch can be sent with sequenceToken: 789"}`))
+			return
+		}
+		w.Write([]byte(`{"nextSequenceToken":"456"}`))
+	}))
+	defer s.Close()
+
+	r, err := cloudwatch.New(cloudwatch.Options{
+		Region:      "us-east-1",
+		Credentials: cloudwatch.Credentials{AccessKeyID: "AKID", SecretAccessKey: "secret"},
+		LogGroup:    "app",
+		LogStream:   "web-1",
+		Formatter: slog.FormatterFunc(func(l *slog.Log) []byte {
+			return []byte(l.Data[0].(string) + "\n")
+		}),
+		MaxDelay: time.Hour,
+		Endpoint: s.URL,
+	})
+	require.NoError(t, err)
+	r.Log(&slog.Log{When: time.Now(), Data: []interface{}{"one"}})
+	r.Flush()
+	r.Log(&slog.Log{When: time.Now(), Data: []interface{}{strings.Repeat("x", 300<<10)}})
+	require.NoError(t, r.Close())
+
+	// the accepted batch isn't sent again
+	require.Equal(t, 2, len(puts))
+	require.Equal(t, "789", puts[1]["sequenceToken"])
+	require.Equal(t, uint64(0), r.Dropped())
+
+	// events too big for CloudWatch are cut short
+	message := puts[1]["logEvents"].([]interface{})[0].(map[string]interface{})["message"].(string)
+	require.True(t, len(message) <= 256<<10-26)
+	require.True(t, strings.HasSuffix(message, slog.TruncatedSuffix))
+
+}
@@ -0,0 +1,353 @@
+// Package config builds a RootLogger, and the Reporters it reports
+// to, from a JSON or YAML document, so logging can be changed
+// without changing code:
+//
+//	source: app
+//	level: info
+//	sources:
+//	  app>db: debug
+//	reporters:
+//	  - type: stdout
+//	    format: json
+//	  - type: file
+//	    path: /var/log/app.log
+//	    level: warn
+//	    max_size: 104857600
+//	    max_backups: 5
+//
+// Reporters of other types can be added with Register.
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/stretchr/slog"
+	"gopkg.in/yaml.v3"
+)
+
+// Config represents the settings of a RootLogger.
+type Config struct {
+	// Source is the source of the RootLogger.
+	Source string `json:"source" yaml:"source"`
+	// Level is the level of the RootLogger, defaulting to info.
+	Level slog.Level `json:"level" yaml:"level"`
+	// Sources are the levels of sources, as SetSourceLevel sets
+	// them.
+	Sources map[string]slog.Level `json:"sources" yaml:"sources"`
+	// Buffer is the size of the buffer, as SetBuffer sets it.
+	Buffer int `json:"buffer" yaml:"buffer"`
+	// DropPolicy is what happens to logs when the buffer is full,
+	// "block" (the default), "oldest" or "newest".
+	DropPolicy string `json:"drop_policy" yaml:"drop_policy"`
+	// CaptureCaller is whether logs have their Caller captured.
+	CaptureCaller bool `json:"capture_caller" yaml:"capture_caller"`
+	// CaptureStack is the level logs capture their Stack at.
+	CaptureStack slog.Level `json:"capture_stack" yaml:"capture_stack"`
+	// SourceSep separates the names of sources when they are
+	// written.
+	SourceSep string `json:"source_sep" yaml:"source_sep"`
+	// Truncate is the size logs are truncated to, if not zero.
+	Truncate int `json:"truncate" yaml:"truncate"`
+	// Reporters are the Reporters logs are reported to, defaulting
+	// to stdout.
+	Reporters []Reporter `json:"reporters" yaml:"reporters"`
+}
+
+// Reporter represents the settings of a Reporter.
+type Reporter struct {
+	// Type is the type of the Reporter: "stdout", "stderr",
+	// "console", "file", "syslog", "discard", or one given to
+	// Register.
+	Type string `json:"type" yaml:"type"`
+	// Level is the most verbose level reported, if set.
+	Level slog.Level `json:"level" yaml:"level"`
+	// Sources are the patterns of a SourceFilter for the Reporter,
+	// if set.
+	Sources map[string]slog.Level `json:"sources" yaml:"sources"`
+	// Format is how stdout, stderr and file Reporters format logs,
+	// "text" (the default), "json" or "logfmt".
+	Format string `json:"format" yaml:"format"`
+	// TimeLayout and TimeZone are how stdout, stderr and file
+	// Reporters write times, e.g. "15:04:05.000" and "UTC",
+	// defaulting to the layout of the format and local time.
+	TimeLayout string `json:"time_layout" yaml:"time_layout"`
+	TimeZone   string `json:"time_zone" yaml:"time_zone"`
+
+	// Path is the path of the file of a file Reporter.
+	Path         string   `json:"path" yaml:"path"`
+	MaxSize      int64    `json:"max_size" yaml:"max_size"`
+	MaxBackups   int      `json:"max_backups" yaml:"max_backups"`
+	Compress     bool     `json:"compress" yaml:"compress"`
+	RotateEvery  Duration `json:"rotate_every" yaml:"rotate_every"`
+	BackupLayout string   `json:"backup_layout" yaml:"backup_layout"`
+	MaxAge       Duration `json:"max_age" yaml:"max_age"`
+
+	// Network and Addr are where a syslog Reporter sends logs,
+	// defaulting to the local syslog daemon.
+	Network string `json:"network" yaml:"network"`
+	Addr    string `json:"addr" yaml:"addr"`
+
+	// Options are the settings of Reporters of types given to
+	// Register.
+	Options map[string]interface{} `json:"options" yaml:"options"`
+}
+
+// Duration is a time.Duration written like "24h" or "1m30s".
+type Duration time.Duration
+
+// UnmarshalText sets the duration from text, as time.ParseDuration
+// parses it.
+func (d *Duration) UnmarshalText(text []byte) error {
+	v, err := time.ParseDuration(string(text))
+	if err != nil {
+		return err
+	}
+	*d = Duration(v)
+	return nil
+}
+
+// MarshalText gets the duration as text.
+func (d Duration) MarshalText() ([]byte, error) {
+	return []byte(time.Duration(d).String()), nil
+}
+
+// Factory makes a Reporter from its settings.
+type Factory func(c Reporter) (slog.Reporter, error)
+
+var (
+	m         sync.RWMutex
+	factories = map[string]Factory{
+		"stdout":  func(c Reporter) (slog.Reporter, error) { return writer(os.Stdout, c) },
+		"stderr":  func(c Reporter) (slog.Reporter, error) { return writer(os.Stderr, c) },
+		"console": func(c Reporter) (slog.Reporter, error) { return slog.NewConsoleReporter(os.Stderr), nil },
+		"file":    file,
+		"syslog":  syslog,
+		"discard": func(c Reporter) (slog.Reporter, error) { return slog.DiscardReporter, nil },
+	}
+)
+
+// Register makes Reporters of the type with f, replacing any f
+// given for the type before.
+func Register(typ string, f Factory) {
+	m.Lock()
+	factories[typ] = f
+	m.Unlock()
+}
+
+// Parse parses the config from JSON, or YAML, which JSON is a
+// subset of.
+func Parse(b []byte) (*Config, error) {
+	var c Config
+	if err := yaml.Unmarshal(b, &c); err != nil {
+		return nil, fmt.Errorf("config: %w", err)
+	}
+	return &c, nil
+}
+
+// ParseJSON parses the config from JSON, failing on unknown keys.
+func ParseJSON(r io.Reader) (*Config, error) {
+	var c Config
+	d := json.NewDecoder(r)
+	d.DisallowUnknownFields()
+	if err := d.Decode(&c); err != nil {
+		return nil, fmt.Errorf("config: %w", err)
+	}
+	return &c, nil
+}
+
+// Load reads the config from the file at path, as JSON if it ends
+// in .json, and as YAML otherwise, and builds the RootLogger.
+func Load(path string) (slog.RootLogger, error) {
+	c, err := ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return c.Build()
+}
+
+// ReadFile reads the config from the file at path, as JSON if it
+// ends in .json, and as YAML otherwise.
+func ReadFile(path string) (*Config, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if isJSON(path) {
+		return ParseJSON(bytes.NewReader(b))
+	}
+	return Parse(b)
+}
+
+// isJSON gets whether the file at path holds JSON.
+func isJSON(path string) bool {
+	return strings.EqualFold(filepath.Ext(path), ".json")
+}
+
+// Build makes the RootLogger the config describes.
+func (c *Config) Build() (slog.RootLogger, error) {
+	r, err := c.Reporter()
+	if err != nil {
+		return nil, err
+	}
+	policy, err := dropPolicy(c.DropPolicy)
+	if err != nil {
+		return nil, err
+	}
+	level := c.Level
+	if level == slog.LevelInvalid {
+		level = slog.LevelInfo
+	}
+	l := slog.NewWithOptions(c.Source,
+		slog.WithLevel(level),
+		slog.WithReporter(r),
+		slog.WithBuffer(c.Buffer),
+		slog.WithDropPolicy(policy),
+		slog.WithCaptureCaller(c.CaptureCaller),
+		slog.WithSourceSep(c.SourceSep),
+	)
+	for source, level := range c.Sources {
+		l.SetSourceLevel(source, level)
+	}
+	if c.CaptureStack != slog.LevelInvalid {
+		l.SetCaptureStack(c.CaptureStack)
+	}
+	if c.Truncate > 0 {
+		l.AddHook(slog.Truncate(c.Truncate))
+	}
+	return l, nil
+}
+
+// Reporter makes the Reporter logs are reported to, closing any
+// it made if it fails.
+func (c *Config) Reporter() (slog.Reporter, error) {
+	if len(c.Reporters) == 0 {
+		return writer(os.Stdout, Reporter{})
+	}
+	rs := make([]slog.Reporter, 0, len(c.Reporters))
+	for i, rc := range c.Reporters {
+		r, err := rc.build()
+		if err != nil {
+			slog.Reporters(rs...).(io.Closer).Close()
+			return nil, fmt.Errorf("config: reporter %d: %w", i, err)
+		}
+		rs = append(rs, r)
+	}
+	if len(rs) == 1 {
+		return rs[0], nil
+	}
+	return slog.Reporters(rs...), nil
+}
+
+func (c Reporter) build() (slog.Reporter, error) {
+	m.RLock()
+	f, ok := factories[c.Type]
+	m.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("unknown type %q (known types are %s)", c.Type, strings.Join(types(), ", "))
+	}
+	r, err := f(c)
+	if err != nil {
+		return nil, err
+	}
+	if len(c.Sources) > 0 {
+		f, err := slog.NewSourceFilter(r, c.Sources)
+		if err != nil {
+			slog.Reporters(r).(io.Closer).Close()
+			return nil, err
+		}
+		r = f
+	}
+	if c.Level != slog.LevelInvalid {
+		r = slog.AtLevel(c.Level, r)
+	}
+	return r, nil
+}
+
+// types gets the types of Reporters that can be made, in order.
+func types() []string {
+	m.RLock()
+	defer m.RUnlock()
+	ts := make([]string, 0, len(factories))
+	for t := range factories {
+		ts = append(ts, t)
+	}
+	sort.Strings(ts)
+	return ts
+}
+
+var formatters = map[string]func(slog.TimeFormat) slog.Formatter{
+	"":       slog.NewTextFormatter,
+	"text":   slog.NewTextFormatter,
+	"json":   slog.NewJSONFormatter,
+	"logfmt": slog.NewLogfmtFormatter,
+}
+
+// formatter makes the Formatter for the format and times of the
+// Reporter.
+func formatter(c Reporter) (slog.Formatter, error) {
+	f, ok := formatters[strings.ToLower(c.Format)]
+	if !ok {
+		return nil, fmt.Errorf("unknown format %q", c.Format)
+	}
+	tf := slog.TimeFormat{Layout: c.TimeLayout}
+	if c.TimeZone != "" {
+		loc, err := time.LoadLocation(c.TimeZone)
+		if err != nil {
+			return nil, err
+		}
+		tf.Location = loc
+	}
+	return f(tf), nil
+}
+
+func writer(w io.Writer, c Reporter) (slog.Reporter, error) {
+	f, err := formatter(c)
+	if err != nil {
+		return nil, err
+	}
+	return slog.NewWriterReporter(w, f), nil
+}
+
+func file(c Reporter) (slog.Reporter, error) {
+	if c.Path == "" {
+		return nil, fmt.Errorf("file reporter needs a path")
+	}
+	f, err := formatter(c)
+	if err != nil {
+		return nil, err
+	}
+	return slog.NewFileReporter(c.Path, slog.FileOptions{
+		MaxSize:      c.MaxSize,
+		MaxBackups:   c.MaxBackups,
+		Compress:     c.Compress,
+		Formatter:    f,
+		RotateEvery:  time.Duration(c.RotateEvery),
+		BackupLayout: c.BackupLayout,
+		MaxAge:       time.Duration(c.MaxAge),
+	})
+}
+
+func syslog(c Reporter) (slog.Reporter, error) {
+	return slog.NewSyslogReporter(slog.SyslogOptions{Network: c.Network, Addr: c.Addr})
+}
+
+func dropPolicy(s string) (slog.DropPolicy, error) {
+	switch strings.ToLower(s) {
+	case "", "block":
+		return slog.BlockWhenFull, nil
+	case "oldest":
+		return slog.DropOldest, nil
+	case "newest":
+		return slog.DropNewest, nil
+	}
+	return 0, fmt.Errorf("config: unknown drop policy %q", s)
+}
@@ -0,0 +1,113 @@
+package config_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/slog"
+	"github.com/stretchr/slog/config"
+	"github.com/stretchr/testify/require"
+)
+
+type memory struct {
+	m    sync.Mutex
+	logs []*slog.Log
+}
+
+func (r *memory) Log(l *slog.Log) {
+	r.m.Lock()
+	r.logs = append(r.logs, l)
+	r.m.Unlock()
+}
+
+func TestLoad(t *testing.T) {
+
+	var mem memory
+	config.Register("memory", func(c config.Reporter) (slog.Reporter, error) {
+		require.Equal(t, "yes", c.Options["keep"])
+		return &mem, nil
+	})
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "app.log")
+	doc := `
+source: app
+level: debug
+sources:
+  app>db: warn
+reporters:
+  - type: memory
+    options:
+      keep: "yes"
+  - type: file
+    path: ` + path + `
+    format: json
+    time_layout: "2006"
+    time_zone: UTC
+    level: warn
+    max_size: 1024
+    rotate_every: 24h
+`
+	file := filepath.Join(dir, "slog.yaml")
+	require.NoError(t, os.WriteFile(file, []byte(doc), 0644))
+
+	l, err := config.Load(file)
+	require.NoError(t, err)
+	require.Equal(t, slog.LevelDebug, l.Level())
+	require.True(t, l.Debug("debug"))
+	require.True(t, l.Warn("warn"))
+	require.False(t, l.New("db").Info("skipped"))
+	require.NoError(t, l.StopContext(context.Background()))
+
+	require.Len(t, mem.logs, 2)
+	require.Equal(t, slog.Source{"app"}, mem.logs[0].Source)
+	b, err := os.ReadFile(path)
+	require.NoError(t, err)
+	require.Equal(t, 1, strings.Count(string(b), "\n"))
+	require.Contains(t, string(b), `"warn"`)
+	require.Contains(t, string(b), `"time":"`+time.Now().UTC().Format("2006")+`"`)
+
+}
+
+func TestParseJSON(t *testing.T) {
+
+	c, err := config.ParseJSON(strings.NewReader(`{"level": "warning", "drop_policy": "oldest", "buffer": 10, "reporters": [{"type": "discard"}]}`))
+	require.NoError(t, err)
+	require.Equal(t, slog.LevelWarn, c.Level)
+	require.Equal(t, 10, c.Buffer)
+
+	l, err := c.Build()
+	require.NoError(t, err)
+	require.Equal(t, slog.LevelWarn, l.Level())
+	require.NoError(t, l.StopContext(context.Background()))
+
+	_, err = config.ParseJSON(strings.NewReader(`{"levle": "warning"}`))
+	require.Error(t, err)
+
+}
+
+func TestBuildErrors(t *testing.T) {
+
+	for _, doc := range []string{
+		`level: loud`,
+		`drop_policy: sometimes`,
+		`reporters: [{type: carrier-pigeon}]`,
+		`reporters: [{type: stdout, format: xml}]`,
+		`reporters: [{type: file}]`,
+		`reporters: [{type: stdout, sources: {"": info}}]`,
+		`reporters: [{type: file, max_age: forever}]`,
+		`reporters: [{type: stdout, time_zone: Nowhere/Special}]`,
+	} {
+		c, err := config.Parse([]byte(doc))
+		if err == nil {
+			_, err = c.Build()
+		}
+		require.Error(t, err, doc)
+	}
+
+}
@@ -0,0 +1,133 @@
+package config
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/stretchr/slog"
+)
+
+// Apply applies the config to a RootLogger that is already
+// logging, replacing its Reporter and levels.
+// The new Reporter is made first, so if that fails nothing is
+// changed. Logs made before the Reporter is replaced are
+// reported by the old one, which is then flushed and closed.
+// If ctx is done before those logs are reported, the old Reporter
+// is closed without waiting for them, and as the config has been
+// applied no error is returned for it.
+// Source and SourceSep can't be changed, and Truncate is only
+// used by Build.
+func (c *Config) Apply(ctx context.Context, l slog.RootLogger) error {
+	r, err := c.Reporter()
+	if err != nil {
+		return err
+	}
+	policy, err := dropPolicy(c.DropPolicy)
+	if err != nil {
+		slog.Reporters(r).(io.Closer).Close()
+		return err
+	}
+	level := c.Level
+	if level == slog.LevelInvalid {
+		level = slog.LevelInfo
+	}
+	l.SetLevel(level)
+	for source := range l.SourceLevels() {
+		if _, ok := c.Sources[source]; !ok {
+			l.SetSourceLevel(source, slog.LevelInvalid)
+		}
+	}
+	for source, level := range c.Sources {
+		l.SetSourceLevel(source, level)
+	}
+	l.SetBuffer(c.Buffer, policy)
+	l.SetCaptureCaller(c.CaptureCaller)
+	l.SetCaptureStack(c.CaptureStack)
+
+	old := l.SwapReporter(r)
+	flushed := l.Flush(ctx) == nil
+	if old == nil {
+		return nil
+	}
+	rs := slog.Reporters(old)
+	if flushed {
+		rs.(slog.Flusher).Flush()
+	}
+	return rs.(io.Closer).Close()
+}
+
+// Watch checks the file at path every interval, and applies the
+// config in it to l whenever it changes, until the returned cancel
+// func is called or l stops.
+// Configs that can't be read or applied are logged to l as errors,
+// and l carries on as it was. Empty files are ignored, but to be
+// sure a half written file isn't read, write the new config to
+// another file and rename it to path.
+func Watch(l slog.RootLogger, path string, every time.Duration) (cancel func()) {
+	w := &watcher{l: l, path: path}
+	w.last, _ = os.ReadFile(path)
+	done := make(chan struct{})
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		t := time.NewTicker(every)
+		defer t.Stop()
+		for {
+			select {
+			case <-t.C:
+				w.check()
+			case <-done:
+				return
+			case <-l.StopChan():
+				return
+			}
+		}
+	}()
+	return func() {
+		select {
+		case <-done:
+		default:
+			close(done)
+		}
+		<-stopped
+	}
+}
+
+type watcher struct {
+	l    slog.RootLogger
+	path string
+	last []byte
+}
+
+// check applies the config if the file has changed since it was
+// last read.
+func (w *watcher) check() {
+	b, err := os.ReadFile(w.path)
+	if err != nil {
+		if !os.IsNotExist(err) || w.last != nil {
+			w.l.Err(fmt.Errorf("config: reading %s: %w", w.path, err))
+		}
+		w.last = nil
+		return
+	}
+	if len(b) == 0 || w.last != nil && bytes.Equal(b, w.last) {
+		return
+	}
+	w.last = b
+	var c *Config
+	if isJSON(w.path) {
+		c, err = ParseJSON(bytes.NewReader(b))
+	} else {
+		c, err = Parse(b)
+	}
+	if err == nil {
+		err = c.Apply(context.Background(), w.l)
+	}
+	if err != nil {
+		w.l.Err(fmt.Errorf("config: reloading %s: %w", w.path, err))
+	}
+}
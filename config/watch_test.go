@@ -0,0 +1,159 @@
+package config_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/slog"
+	"github.com/stretchr/slog/config"
+	"github.com/stretchr/testify/require"
+)
+
+type closeMemory struct {
+	memory
+	closed int
+}
+
+func (r *closeMemory) Close() error {
+	r.m.Lock()
+	r.closed++
+	r.m.Unlock()
+	return nil
+}
+
+func (r *closeMemory) count() (logs, closed int) {
+	r.m.Lock()
+	defer r.m.Unlock()
+	return len(r.logs), r.closed
+}
+
+var named = struct {
+	sync.Mutex
+	rs map[string]*closeMemory
+}{rs: make(map[string]*closeMemory)}
+
+func init() {
+	config.Register("named", func(c config.Reporter) (slog.Reporter, error) {
+		named.Lock()
+		defer named.Unlock()
+		name, _ := c.Options["name"].(string)
+		r := &closeMemory{}
+		named.rs[name] = r
+		return r, nil
+	})
+}
+
+func namedReporter(name string) *closeMemory {
+	named.Lock()
+	defer named.Unlock()
+	return named.rs[name]
+}
+
+// writeFile replaces the file at path all at once, so it isn't
+// read half written.
+func writeFile(path string, b []byte, perm os.FileMode) error {
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, b, perm); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+func TestApply(t *testing.T) {
+
+	c, err := config.Parse([]byte(`{level: info, sources: {app>db: debug}, reporters: [{type: named, options: {name: apply1}}]}`))
+	require.NoError(t, err)
+	l, err := c.Build()
+	require.NoError(t, err)
+	defer l.StopContext(context.Background())
+	require.True(t, l.Info("one"))
+
+	c, err = config.Parse([]byte(`{level: debug, sources: {app>web: warn}, reporters: [{type: named, options: {name: apply2}}]}`))
+	require.NoError(t, err)
+	require.NoError(t, c.Apply(context.Background(), l))
+	require.Equal(t, slog.LevelDebug, l.Level())
+	require.Equal(t, map[string]slog.Level{"app>web": slog.LevelWarn}, l.SourceLevels())
+	require.True(t, l.Debug("two"))
+
+	logs, closed := namedReporter("apply1").count()
+	require.Equal(t, 1, logs)
+	require.Equal(t, 1, closed)
+	logs, closed = namedReporter("apply2").count()
+	require.Equal(t, 1, logs)
+	require.Equal(t, 0, closed)
+
+	c, err = config.Parse([]byte(`{level: trace, reporters: [{type: file}]}`))
+	require.NoError(t, err)
+	require.Error(t, c.Apply(context.Background(), l))
+	require.Equal(t, slog.LevelDebug, l.Level())
+
+}
+
+// blockingReporter is a closeMemory that doesn't report logs until
+// release is closed.
+type blockingReporter struct {
+	closeMemory
+	release chan struct{}
+}
+
+func (r *blockingReporter) Log(l *slog.Log) {
+	<-r.release
+	r.closeMemory.Log(l)
+}
+
+func TestApplyTimeout(t *testing.T) {
+
+	old := &blockingReporter{release: make(chan struct{})}
+	l := slog.New("app", slog.LevelInfo)
+	l.SetReporter(old)
+	defer l.StopContext(context.Background())
+	defer close(old.release)
+	require.True(t, l.Info("stuck"))
+
+	c, err := config.Parse([]byte(`{level: info, reporters: [{type: named, options: {name: timeout}}]}`))
+	require.NoError(t, err)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	require.NoError(t, c.Apply(ctx, l))
+	_, closed := old.count()
+	require.Equal(t, 1, closed)
+
+}
+
+func TestWatch(t *testing.T) {
+
+	path := filepath.Join(t.TempDir(), "slog.yaml")
+	require.NoError(t, writeFile(path, []byte("level: info\nreporters: [{type: named, options: {name: watch1}}]\n"), 0644))
+	l, err := config.Load(path)
+	require.NoError(t, err)
+	defer l.StopContext(context.Background())
+
+	cancel := config.Watch(l, path, 10*time.Millisecond)
+	defer cancel()
+
+	require.NoError(t, writeFile(path, []byte("level: debug\nreporters: [{type: named, options: {name: watch2}}]\n"), 0644))
+	require.Eventually(t, func() bool {
+		return l.Level() == slog.LevelDebug
+	}, time.Second, 10*time.Millisecond)
+	require.Eventually(t, func() bool {
+		_, closed := namedReporter("watch1").count()
+		return closed == 1
+	}, time.Second, 10*time.Millisecond)
+
+	require.NoError(t, writeFile(path, []byte("level: loud\n"), 0644))
+	require.Eventually(t, func() bool {
+		logs, _ := namedReporter("watch2").count()
+		return logs == 1
+	}, time.Second, 10*time.Millisecond)
+	require.Equal(t, slog.LevelDebug, l.Level())
+
+	cancel()
+	require.NoError(t, writeFile(path, []byte("level: trace\nreporters: [{type: named, options: {name: watch3}}]\n"), 0644))
+	time.Sleep(50 * time.Millisecond)
+	require.Equal(t, slog.LevelDebug, l.Level())
+
+}
@@ -0,0 +1,105 @@
+package slog
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+)
+
+// maxConsoleSourceWidth is the widest the source column of a
+// ConsoleReporter will grow to.
+const maxConsoleSourceWidth = 32
+
+var consoleLevels = map[Level]string{
+	LevelErr:   "ERROR",
+	LevelWarn:  "WARN",
+	LevelInfo:  "INFO",
+	LevelDebug: "DEBUG",
+	LevelTrace: "TRACE",
+}
+
+var consoleColors = map[Level]string{
+	LevelErr:   "\x1b[31m",
+	LevelWarn:  "\x1b[33m",
+	LevelInfo:  "\x1b[36m",
+	LevelDebug: "\x1b[90m",
+	LevelTrace: "\x1b[90m",
+}
+
+const consoleReset = "\x1b[0m"
+
+// ConsoleReporter is a Reporter that writes logs for people to
+// read, with the time since it was made, colors for each level,
+// and aligned sources.
+type ConsoleReporter struct {
+	m     sync.Mutex
+	w     io.Writer
+	color bool
+	start time.Time
+	width int
+}
+
+var _ Reporter = (*ConsoleReporter)(nil)
+
+// NewConsoleReporter makes a ConsoleReporter that writes to w.
+// Colors are only used if w is a terminal.
+func NewConsoleReporter(w io.Writer) *ConsoleReporter {
+	return &ConsoleReporter{
+		w:     w,
+		color: isTerminal(w),
+		start: time.Now(),
+	}
+}
+
+// SetColor sets whether colors are used.
+func (c *ConsoleReporter) SetColor(color bool) {
+	c.m.Lock()
+	c.color = color
+	c.m.Unlock()
+}
+
+// Log writes the log.
+func (c *ConsoleReporter) Log(l *Log) {
+	source := l.SourceString()
+	level, ok := consoleLevels[l.Level]
+	if !ok {
+		level = l.Level.String()
+	}
+	args := l.text()
+
+	c.m.Lock()
+	defer c.m.Unlock()
+	if len(source) > c.width {
+		c.width = len(source)
+		if c.width > maxConsoleSourceWidth {
+			c.width = maxConsoleSourceWidth
+		}
+	}
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "%+9.3fs ", l.When.Sub(c.start).Seconds())
+	if c.color {
+		buf.WriteString(consoleColors[l.Level])
+	}
+	fmt.Fprintf(&buf, "%-5s", level)
+	if c.color {
+		buf.WriteString(consoleReset)
+	}
+	fmt.Fprintf(&buf, " %-*s %s\n", c.width, source, lines(sprint(args), l.Stack))
+	c.w.Write(buf.Bytes())
+}
+
+// isTerminal gets whether w is a terminal.
+func isTerminal(w io.Writer) bool {
+	f, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+	info, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
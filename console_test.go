@@ -0,0 +1,35 @@
+package slog_test
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/slog"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConsoleReporter(t *testing.T) {
+
+	var buf bytes.Buffer
+	r := slog.NewConsoleReporter(&buf)
+	when := time.Now().Add(1500 * time.Millisecond)
+
+	r.Log(&slog.Log{Level: slog.LevelErr, When: when, Source: []string{"parent", "child"}, Data: []interface{}{"failed"}})
+	r.Log(&slog.Log{Level: slog.LevelInfo, When: when, Source: []string{"parent"}, Data: []interface{}{"ok"}, Fields: slog.Fields{"n": 1}})
+
+	require.Equal(t, `   +1.500s ERROR parent>child failed
+   +1.500s INFO  parent       ok n=1
+`, buf.String())
+
+	buf.Reset()
+	r.SetColor(true)
+	r.Log(&slog.Log{Level: slog.LevelWarn, When: when, Source: []string{"parent"}, Data: []interface{}{"careful"}})
+	require.Equal(t, "   +1.500s \x1b[33mWARN \x1b[0m parent       careful\n", buf.String())
+
+	buf.Reset()
+	r.SetColor(false)
+	r.Log(&slog.Log{Level: slog.LevelErr, When: when, Source: []string{"parent"}, Data: []interface{}{"failed"}, Stack: "goroutine 1 [running]:\nmain.main()\n"})
+	require.Equal(t, "   +1.500s ERROR parent       failed\n\tgoroutine 1 [running]:\n\tmain.main()\n", buf.String())
+
+}
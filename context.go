@@ -0,0 +1,19 @@
+package slog
+
+import "context"
+
+type contextKey struct{}
+
+// NewContext gets a copy of ctx that carries the Logger.
+func NewContext(ctx context.Context, l Logger) context.Context {
+	return context.WithValue(ctx, contextKey{}, l)
+}
+
+// FromContext gets the Logger carried by ctx, or NilLogger
+// if there isn't one.
+func FromContext(ctx context.Context) Logger {
+	if l, ok := ctx.Value(contextKey{}).(Logger); ok {
+		return l
+	}
+	return NilLogger
+}
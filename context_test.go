@@ -0,0 +1,26 @@
+package slog_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/pat/stop"
+	"github.com/stretchr/slog"
+	"github.com/stretchr/testify/require"
+)
+
+func TestContext(t *testing.T) {
+
+	require.Equal(t, slog.NilLogger, slog.FromContext(context.Background()))
+
+	l := slog.New("parent", slog.LevelInfo)
+	defer func() {
+		l.Stop(stop.NoWait)
+		<-l.StopChan()
+	}()
+	child := l.New("request")
+
+	ctx := slog.NewContext(context.Background(), child)
+	require.Equal(t, child, slog.FromContext(ctx))
+
+}
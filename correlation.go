@@ -0,0 +1,36 @@
+package slog
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+)
+
+// CorrelationIDKey is the field holding the correlation ID of a
+// log, shared by all the logs of one request or job.
+const CorrelationIDKey = "correlation_id"
+
+// NewCorrelationID generates a random correlation ID, as 32 hex
+// digits.
+func NewCorrelationID() string {
+	var b [16]byte
+	rand.Read(b[:])
+	return hex.EncodeToString(b[:])
+}
+
+// WithCorrelationID gets a child of l that adds a new correlation
+// ID to every log it, and its children, make, along with the ID,
+// to pass on to other services, e.g. in a header.
+func WithCorrelationID(l Logger) (Logger, string) {
+	id := NewCorrelationID()
+	return l.With(CorrelationIDKey, id), id
+}
+
+// CorrelationID gets the correlation ID added to the logs of l, or
+// an empty string if there isn't one.
+func CorrelationID(l Logger) string {
+	if pl, ok := l.(*logger); ok {
+		id, _ := pl.fields[CorrelationIDKey].(string)
+		return id
+	}
+	return ""
+}
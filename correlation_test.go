@@ -0,0 +1,37 @@
+package slog_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/slog"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithCorrelationID(t *testing.T) {
+
+	l := slog.New("parent", slog.LevelInfo)
+	r := NewTestReporter()
+	l.SetReporter(r)
+	require.Equal(t, "", slog.CorrelationID(l))
+
+	job, id := slog.WithCorrelationID(l)
+	require.Len(t, id, 32)
+	require.Equal(t, id, slog.CorrelationID(job))
+	require.Equal(t, id, slog.CorrelationID(job.New("child").With("user", "mat")))
+
+	_, other := slog.WithCorrelationID(l)
+	require.NotEqual(t, id, other)
+
+	job.Info("started")
+	job.New("child").Info("working")
+	l.Info("unrelated")
+	require.NoError(t, l.StopContext(context.Background()))
+
+	require.Equal(t, 3, len(r.logs))
+	require.Equal(t, id, r.logs[0].Fields[slog.CorrelationIDKey])
+	require.Equal(t, id, r.logs[1].Fields[slog.CorrelationIDKey])
+	require.Nil(t, r.logs[2].Fields[slog.CorrelationIDKey])
+	require.Equal(t, "", slog.CorrelationID(slog.NilLogger))
+
+}
@@ -0,0 +1,117 @@
+package slog
+
+import (
+	"io"
+	"sync"
+	"time"
+)
+
+// RepeatedKey is the field a Deduper puts the number of times a
+// log was repeated in.
+const RepeatedKey = "repeated"
+
+// Deduper is a Reporter that collapses a log repeated one after
+// another into the first one and a summary, like syslog's "last
+// message repeated N times".
+// Logs are the same if they have the same level, source and Data.
+type Deduper struct {
+	m       sync.Mutex
+	r       Reporter
+	window  time.Duration
+	clock   Clock
+	key     string
+	start   time.Time // when the first of the repeated logs was made
+	last    *Log      // the last of the repeated logs
+	repeats int
+	timer   *time.Timer
+}
+
+var _ Reporter = (*Deduper)(nil)
+var _ io.Closer = (*Deduper)(nil)
+
+// NewDeduper makes a Deduper that reports to r, collapsing logs
+// repeated within window of the first of them.
+// The summary is the last of the repeated logs, with the number
+// of times it was repeated under RepeatedKey. It is reported when
+// a different log is, or once window has passed.
+func NewDeduper(r Reporter, window time.Duration) *Deduper {
+	return &Deduper{r: r, window: window, clock: SystemClock}
+}
+
+// Log reports the log, unless it repeats the one before.
+func (d *Deduper) Log(l *Log) {
+	key := l.Level.String() + ":" + l.SourceString() + ":" + sprint(l.Data)
+	d.m.Lock()
+	defer d.m.Unlock()
+	now := d.clock.Now()
+	if key == d.key && now.Sub(d.start) < d.window {
+		d.repeats++
+		d.last = l
+		if d.timer == nil {
+			d.timer = time.AfterFunc(d.window-now.Sub(d.start), d.expire)
+		}
+		return
+	}
+	d.flush()
+	d.key, d.start = key, now
+	d.r.Log(l)
+}
+
+// expire reports the summary once the window has passed.
+func (d *Deduper) expire() {
+	d.m.Lock()
+	d.flush()
+	d.key = ""
+	d.m.Unlock()
+}
+
+// flush reports the summary of the repeated logs, if there were
+// any.
+// Must be called with m held.
+func (d *Deduper) flush() {
+	if d.timer != nil {
+		d.timer.Stop()
+		d.timer = nil
+	}
+	if d.repeats == 0 {
+		return
+	}
+	summary := *d.last
+	summary.Fields = d.last.Fields.merge(Fields{RepeatedKey: d.repeats})
+	d.repeats, d.last = 0, nil
+	d.r.Log(&summary)
+}
+
+// SetClock sets the Clock used to tell how long logs have been
+// repeated for, which is SystemClock by default.
+func (d *Deduper) SetClock(c Clock) {
+	d.m.Lock()
+	d.clock = c
+	d.m.Unlock()
+}
+
+// Flush reports the summary of any repeated logs so far, and then
+// flushes the Reporter, if it is a Flusher.
+func (d *Deduper) Flush() {
+	d.m.Lock()
+	d.flush()
+	d.m.Unlock()
+	reporters{d.r}.Flush()
+}
+
+// Start starts the Reporter, if it is a Starter.
+func (d *Deduper) Start() {
+	reporters{d.r}.Start()
+}
+
+// Close reports the summary of any repeated logs, and then closes
+// the Reporter, if it is an io.Closer.
+func (d *Deduper) Close() error {
+	d.m.Lock()
+	d.flush()
+	d.m.Unlock()
+	if c, ok := d.r.(io.Closer); ok {
+		return c.Close()
+	}
+	return nil
+}
@@ -0,0 +1,69 @@
+package slog_test
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/slog"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeduper(t *testing.T) {
+
+	r := NewTestReporter()
+	d := slog.NewDeduper(r, time.Hour)
+	clock := &testClock{now: time.Date(2015, 1, 1, 0, 0, 0, 0, time.UTC)}
+	d.SetClock(clock)
+
+	miss := func() *slog.Log {
+		return &slog.Log{Level: slog.LevelInfo, Source: []string{"parent"}, Data: []interface{}{"cache miss"}}
+	}
+	for n := 0; n < 4; n++ {
+		d.Log(miss())
+	}
+	d.Log(&slog.Log{Level: slog.LevelWarn, Source: []string{"parent"}, Data: []interface{}{"cache miss"}})
+	d.Log(miss())
+	clock.Add(time.Hour)
+	d.Log(miss())
+	d.Log(miss())
+	require.NoError(t, d.Close())
+
+	var got []string
+	for _, l := range r.logs {
+		got = append(got, l.Level.String()+" "+l.Fields.String())
+	}
+	require.Equal(t, []string{
+		"info ",
+		"info repeated=3",
+		"warning ",
+		"info ",
+		"info ",
+		"info repeated=1",
+	}, got)
+
+}
+
+func TestDeduperWindow(t *testing.T) {
+
+	var m sync.Mutex
+	var got []*slog.Log
+	d := slog.NewDeduper(slog.ReporterFunc(func(l *slog.Log) {
+		m.Lock()
+		got = append(got, l)
+		m.Unlock()
+	}), 10*time.Millisecond)
+
+	for n := 0; n < 3; n++ {
+		d.Log(&slog.Log{Level: slog.LevelInfo, Data: []interface{}{"cache miss"}})
+	}
+	require.Eventually(t, func() bool {
+		m.Lock()
+		defer m.Unlock()
+		return len(got) == 2
+	}, time.Second, time.Millisecond)
+	m.Lock()
+	require.Equal(t, 2, got[1].Fields[slog.RepeatedKey])
+	m.Unlock()
+
+}
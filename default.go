@@ -0,0 +1,105 @@
+package slog
+
+import (
+	"fmt"
+	"sync/atomic"
+)
+
+// defaultLogger holds the Logger the package level funcs log to,
+// in a loggerBox.
+var defaultLogger atomic.Value
+
+type loggerBox struct {
+	l Logger
+}
+
+// SetDefault sets the Logger used by the package level funcs,
+// such as Info, which is NilLogger until it is set. Setting
+// nil sets it back to NilLogger.
+func SetDefault(l RootLogger) {
+	if l == nil {
+		defaultLogger.Store(loggerBox{l: NilLogger})
+		return
+	}
+	defaultLogger.Store(loggerBox{l: l})
+}
+
+// Default gets the Logger used by the package level funcs.
+func Default() Logger {
+	if b, ok := defaultLogger.Load().(loggerBox); ok {
+		return b.l
+	}
+	return NilLogger
+}
+
+// Trace logs to the default Logger as Logger.Trace does.
+func Trace(a ...interface{}) bool {
+	return logDefault(LevelTrace, a)
+}
+
+// Debug logs to the default Logger as Logger.Debug does.
+func Debug(a ...interface{}) bool {
+	return logDefault(LevelDebug, a)
+}
+
+// Info logs to the default Logger as Logger.Info does.
+func Info(a ...interface{}) bool {
+	return logDefault(LevelInfo, a)
+}
+
+// Warn logs to the default Logger as Logger.Warn does.
+func Warn(a ...interface{}) bool {
+	return logDefault(LevelWarn, a)
+}
+
+// Err logs to the default Logger as Logger.Err does.
+func Err(a ...interface{}) bool {
+	return logDefault(LevelErr, a)
+}
+
+// Infof logs to the default Logger as Logger.Infof does.
+func Infof(format string, a ...interface{}) bool {
+	return logDefaultf(LevelInfo, format, a)
+}
+
+// Warnf logs to the default Logger as Logger.Warnf does.
+func Warnf(format string, a ...interface{}) bool {
+	return logDefaultf(LevelWarn, format, a)
+}
+
+// Errf logs to the default Logger as Logger.Errf does.
+func Errf(format string, a ...interface{}) bool {
+	return logDefaultf(LevelErr, format, a)
+}
+
+// logDefault logs a to the default Logger at the level.
+// It must be called directly from the package level func so the
+// caller's file and line are recorded.
+func logDefault(level Level, a []interface{}) bool {
+	pl, ok := Default().(*logger)
+	if !ok {
+		return logAt(Default(), level, 0, a)
+	}
+	if pl.skip(level) {
+		return false
+	}
+	if len(a) == 0 {
+		return true
+	}
+	return pl.emit(level, callerPC(2), a)
+}
+
+// logDefaultf is like logDefault, but formats the message with
+// fmt.Sprintf only if the default Logger is logging at the level.
+func logDefaultf(level Level, format string, a []interface{}) bool {
+	pl, ok := Default().(*logger)
+	if !ok {
+		return logAt(Default(), level, 0, []interface{}{fmt.Sprintf(format, a...)})
+	}
+	if pl.skip(level) {
+		return false
+	}
+	item := pl.makeLog(level, callerPC(2), []interface{}{fmt.Sprintf(format, a...)})
+	item.Template = format
+	return pl.emitLog(item)
+}
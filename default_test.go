@@ -0,0 +1,43 @@
+package slog_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/pat/stop"
+	"github.com/stretchr/slog"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDefault(t *testing.T) {
+
+	require.Equal(t, slog.NilLogger, slog.Default())
+	require.False(t, slog.Info("nothing"))
+
+	l := slog.New("parent", slog.LevelInfo)
+	l.SetSync(true)
+	defer func() {
+		l.Stop(stop.NoWait)
+		<-l.StopChan()
+	}()
+	r := NewTestReporter()
+	l.SetReporter(r)
+	slog.SetDefault(l)
+	defer slog.SetDefault(nil)
+
+	require.Equal(t, l, slog.Default())
+	require.True(t, slog.Info("one"))
+	require.True(t, slog.Warnf("%d", 2))
+	require.False(t, slog.Debug("three"))
+	require.True(t, slog.Err())
+
+	require.Equal(t, 2, len(r.logs))
+	require.Equal(t, slog.LevelInfo, r.logs[0].Level)
+	require.Contains(t, r.logs[0].Data[0], "default_test.go:")
+	require.Equal(t, "one", r.logs[0].Data[1])
+	require.Equal(t, slog.LevelWarn, r.logs[1].Level)
+	require.Equal(t, "2", r.logs[1].Data[1])
+
+	slog.SetDefault(nil)
+	require.Equal(t, slog.NilLogger, slog.Default())
+
+}
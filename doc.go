@@ -2,7 +2,7 @@
 // with parent/child loggers.
 //
 //    * Concurrent safe
-//    * Three levels; `slog.Err`, `slog.Warn`, and `slog.Info`
+//    * Five levels; `slog.Err`, `slog.Warn`, `slog.Info`, `slog.Debug`, and `slog.Trace`
 //    * Children loggers for sub-processes
 //    * Built-in zero-memory `slog.NilLogger` to easily logging off without changing calling code
 //    * Custom reporters to send logs anywhere
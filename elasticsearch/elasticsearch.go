@@ -0,0 +1,88 @@
+// Package elasticsearch provides a slog.Reporter that indexes logs
+// in Elasticsearch or OpenSearch.
+package elasticsearch
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/stretchr/slog"
+)
+
+// DefaultIndex is the index template used when Options.Index is
+// empty.
+const DefaultIndex = "logs-2006.01.02"
+
+// Options represents the options for an Elasticsearch reporter.
+type Options struct {
+	// URL is the base URL of the cluster, e.g.
+	// "http://localhost:9200".
+	URL string
+	// Index is the name of the index for each log, as a time
+	// layout applied to Log.When in UTC, e.g.
+	// "app-logs-2006.01.02" for daily indices. Defaults to
+	// DefaultIndex.
+	Index string
+	// Header holds headers sent with each request, e.g.
+	// "Authorization: ApiKey ...".
+	Header http.Header
+	// MaxBatch and MaxDelay control the batching of logs, as they
+	// do for slog.HTTPOptions.
+	MaxBatch int
+	MaxDelay time.Duration
+	// Client is the client used to index logs, defaulting to
+	// http.DefaultClient.
+	Client *http.Client
+}
+
+// New makes a Reporter that indexes batches of logs with the bulk
+// API. Each document is the object written by
+// slog.NewJSONReporter, with its time also in "@timestamp".
+func New(opts Options) *slog.HTTPReporter {
+	if opts.Index == "" {
+		opts.Index = DefaultIndex
+	}
+	return slog.NewHTTPReporter(slog.HTTPOptions{
+		URL:         strings.TrimSuffix(opts.URL, "/") + "/_bulk",
+		Header:      opts.Header,
+		Gzip:        true,
+		MaxBatch:    opts.MaxBatch,
+		MaxDelay:    opts.MaxDelay,
+		Client:      opts.Client,
+		ContentType: "application/x-ndjson",
+		Encode: func(logs []*slog.Log) ([]byte, error) {
+			return encode(opts.Index, logs)
+		},
+	})
+}
+
+type action struct {
+	Index struct {
+		Index string `json:"_index"`
+	} `json:"index"`
+}
+
+// encode encodes the logs as the body of a bulk request.
+func encode(index string, logs []*slog.Log) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	for _, l := range logs {
+		var doc map[string]interface{}
+		if err := json.Unmarshal(slog.JSONFormatter.Format(l), &doc); err != nil {
+			return nil, err
+		}
+		doc["@timestamp"] = doc["time"]
+		var a action
+		a.Index.Index = l.When.UTC().Format(index)
+		if err := enc.Encode(a); err != nil {
+			return nil, err
+		}
+		if err := enc.Encode(doc); err != nil {
+			return nil, err
+		}
+	}
+	return buf.Bytes(), nil
+}
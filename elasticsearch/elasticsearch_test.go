@@ -0,0 +1,53 @@
+package elasticsearch_test
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/slog"
+	"github.com/stretchr/slog/elasticsearch"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReporter(t *testing.T) {
+
+	var m sync.Mutex
+	var paths, types []string
+	var bodies [][]byte
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		zr, err := gzip.NewReader(r.Body)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		body, _ := io.ReadAll(zr)
+		m.Lock()
+		defer m.Unlock()
+		paths = append(paths, r.URL.Path)
+		types = append(types, r.Header.Get("Content-Type"))
+		bodies = append(bodies, body)
+	}))
+	defer s.Close()
+
+	r := elasticsearch.New(elasticsearch.Options{URL: s.URL, Index: "app-2006.01.02"})
+	when := time.Date(2015, 1, 2, 23, 4, 5, 0, time.UTC)
+	r.Log(&slog.Log{Level: slog.LevelInfo, When: when, Source: []string{"parent"}, Data: []interface{}{"one"}})
+	r.Log(&slog.Log{Level: slog.LevelWarn, When: when.Add(time.Hour), Source: []string{"parent"}, Data: []interface{}{"two"}})
+	require.NoError(t, r.Close())
+
+	require.Equal(t, []string{"/_bulk"}, paths)
+	require.Equal(t, []string{"application/x-ndjson"}, types)
+	lines := bytes.Split(bytes.TrimSpace(bodies[0]), []byte("\n"))
+	require.Equal(t, 4, len(lines))
+	require.JSONEq(t, `{"index":{"_index":"app-2015.01.02"}}`, string(lines[0]))
+	require.JSONEq(t, `{"@timestamp":"2015-01-02T23:04:05Z","time":"2015-01-02T23:04:05Z","level":"info","source":"parent","data":["one"]}`, string(lines[1]))
+	require.JSONEq(t, `{"index":{"_index":"app-2015.01.03"}}`, string(lines[2]))
+	require.JSONEq(t, `{"@timestamp":"2015-01-03T00:04:05Z","time":"2015-01-03T00:04:05Z","level":"warning","source":"parent","data":["two"]}`, string(lines[3]))
+
+}
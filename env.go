@@ -0,0 +1,60 @@
+package slog
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// The environment variables read by NewFromEnv.
+const (
+	EnvLevel  = "SLOG_LEVEL"
+	EnvFormat = "SLOG_FORMAT"
+	EnvOutput = "SLOG_OUTPUT"
+)
+
+// envFormatters are the formats that may be given in EnvFormat.
+var envFormatters = map[string]Formatter{
+	"text":   TextFormatter,
+	"json":   JSONFormatter,
+	"logfmt": LogfmtFormatter,
+}
+
+// NewFromEnv makes a RootLogger configured by the environment:
+//
+//	SLOG_LEVEL   the level, defaulting to info
+//	SLOG_FORMAT  text (the default), json or logfmt
+//	SLOG_OUTPUT  stdout (the default), stderr, or the path of a
+//	             file to append to
+func NewFromEnv(source string) (RootLogger, error) {
+	level := LevelInfo
+	if s := os.Getenv(EnvLevel); s != "" {
+		if err := level.Set(s); err != nil {
+			return nil, err
+		}
+	}
+	format := strings.ToLower(os.Getenv(EnvFormat))
+	if format == "" {
+		format = "text"
+	}
+	f, ok := envFormatters[format]
+	if !ok {
+		return nil, fmt.Errorf("slog: unknown format %q", format)
+	}
+	var r Reporter
+	switch output := os.Getenv(EnvOutput); output {
+	case "", "stdout":
+		r = NewWriterReporter(os.Stdout, f)
+	case "stderr":
+		r = NewWriterReporter(os.Stderr, f)
+	default:
+		fr, err := NewFileReporter(output, FileOptions{Formatter: f})
+		if err != nil {
+			return nil, err
+		}
+		r = fr
+	}
+	l := New(source, level)
+	l.SetReporter(r)
+	return l, nil
+}
@@ -0,0 +1,48 @@
+package slog_test
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/slog"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewFromEnv(t *testing.T) {
+
+	path := filepath.Join(t.TempDir(), "app.log")
+	t.Setenv(slog.EnvLevel, "warn")
+	t.Setenv(slog.EnvFormat, "json")
+	t.Setenv(slog.EnvOutput, path)
+
+	l, err := slog.NewFromEnv("parent")
+	require.NoError(t, err)
+	require.Equal(t, slog.LevelWarn, l.Level())
+	require.False(t, l.Info("skipped"))
+	require.True(t, l.Warn("written"))
+	require.NoError(t, l.StopContext(context.Background()))
+
+	b, err := os.ReadFile(path)
+	require.NoError(t, err)
+	var item map[string]interface{}
+	require.NoError(t, json.Unmarshal(b, &item))
+	require.Equal(t, "warning", item["level"])
+	require.Equal(t, "parent", item["source"])
+
+}
+
+func TestNewFromEnvErrors(t *testing.T) {
+
+	t.Setenv(slog.EnvLevel, "loud")
+	_, err := slog.NewFromEnv("parent")
+	require.Error(t, err)
+
+	t.Setenv(slog.EnvLevel, "")
+	t.Setenv(slog.EnvFormat, "xml")
+	_, err = slog.NewFromEnv("parent")
+	require.Error(t, err)
+
+}
@@ -0,0 +1,64 @@
+package slog
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrDropped is given to the error handler for each log dropped
+// because the buffer was full.
+var ErrDropped = errors.New("slog: log dropped because the buffer is full")
+
+// ReportError is given to the error handler when a Reporter fails
+// to report a log, or panics.
+type ReportError struct {
+	// Log is the log that wasn't reported.
+	Log *Log
+	// Err is the error from the Reporter.
+	Err error
+	// Panic is the value the Reporter panicked with, if it did.
+	Panic interface{}
+}
+
+func (e *ReportError) Error() string {
+	if e.Panic != nil {
+		return fmt.Sprintf("slog: reporter panicked: %v", e.Panic)
+	}
+	return "slog: reporting log: " + e.Err.Error()
+}
+
+func (e *ReportError) Unwrap() error {
+	return e.Err
+}
+
+// errorHandler is held by the root logger, as atomic.Value can't
+// hold a nil func.
+type errorHandler struct {
+	f func(error)
+}
+
+func (l *logger) SetErrorHandler(f func(error)) {
+	l.root.errh.Store(errorHandler{f: f})
+}
+
+// handleError gives the error to the error handler, if there is
+// one.
+func (l *logger) handleError(err error) {
+	if h, ok := l.root.errh.Load().(errorHandler); ok && h.f != nil {
+		h.f(err)
+	}
+}
+
+// safeReport reports the log to r, getting a *ReportError if r
+// fails or panics.
+func safeReport(r Reporter, l *Log) (err error) {
+	defer func() {
+		if v := recover(); v != nil {
+			err = &ReportError{Log: l, Panic: v}
+		}
+	}()
+	if err := report(r, l); err != nil {
+		return &ReportError{Log: l, Err: err}
+	}
+	return nil
+}
@@ -0,0 +1,93 @@
+package slog_test
+
+import (
+	"bytes"
+	"errors"
+	"math"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/pat/stop"
+	"github.com/stretchr/slog"
+	"github.com/stretchr/testify/require"
+)
+
+// errorRecorder records the errors given to an error handler.
+type errorRecorder struct {
+	m    sync.Mutex
+	errs []error
+}
+
+func (r *errorRecorder) handle(err error) {
+	r.m.Lock()
+	r.errs = append(r.errs, err)
+	r.m.Unlock()
+}
+
+func (r *errorRecorder) get() []error {
+	r.m.Lock()
+	defer r.m.Unlock()
+	return append([]error(nil), r.errs...)
+}
+
+func TestErrorHandlerReporters(t *testing.T) {
+
+	l := slog.New("parent", slog.LevelInfo)
+	l.SetSync(true)
+	var errs errorRecorder
+	l.SetErrorHandler(errs.handle)
+
+	l.SetReporter(errReporter{})
+	l.Err("fails")
+	l.Info("works")
+	require.Len(t, errs.get(), 1)
+	var reportErr *slog.ReportError
+	require.True(t, errors.As(errs.get()[0], &reportErr))
+	require.Equal(t, "failed", reportErr.Err.Error())
+	require.Equal(t, "fails", reportErr.Log.Data[1])
+
+	l.SetReporterFunc(func(*slog.Log) { panic("oops") })
+	require.True(t, l.Info("panics"))
+	require.True(t, l.Info("panics again"))
+	require.Len(t, errs.get(), 3)
+	require.True(t, errors.As(errs.get()[2], &reportErr))
+	require.Equal(t, "oops", reportErr.Panic)
+	require.Equal(t, "slog: reporter panicked: oops", reportErr.Error())
+	require.Equal(t, uint64(3), l.Stats().Failed)
+
+	var buf bytes.Buffer
+	l.SetReporter(slog.NewJSONReporter(&buf))
+	l.Info("not a number", slog.Fields{"ratio": math.NaN()})
+	require.Empty(t, buf.String())
+	require.Len(t, errs.get(), 4)
+	require.ErrorContains(t, errs.get()[3], "slog: marshaling log")
+
+	l.SetErrorHandler(nil)
+	l.Info("not a number", slog.Fields{"ratio": math.NaN()})
+	require.Len(t, errs.get(), 4)
+	slog.NilLogger.SetErrorHandler(errs.handle)
+
+}
+
+func TestErrorHandlerDropped(t *testing.T) {
+
+	l := slog.New("parent", slog.LevelInfo)
+	defer func() {
+		l.Stop(stop.NoWait)
+		<-l.StopChan()
+	}()
+	var errs errorRecorder
+	l.SetErrorHandler(errs.handle)
+	r := newBlockingReporter(2)
+	l.SetReporter(r)
+	l.SetBuffer(1, slog.DropNewest)
+
+	l.Info("1")
+	<-r.logging
+	l.Info("2")
+	l.Info("3")
+	close(r.release)
+	<-r.done
+	require.Equal(t, []error{slog.ErrDropped}, errs.get())
+
+}
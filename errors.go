@@ -0,0 +1,50 @@
+package slog
+
+import (
+	"errors"
+	"fmt"
+)
+
+type errorArg struct {
+	err error
+}
+
+// Error wraps err so that, when passed to a Logger method, it is
+// recorded in Log.Err rather than in Data, e.g.
+//
+//	l.Err("save failed", slog.Error(err))
+func Error(err error) interface{} {
+	return errorArg{err: err}
+}
+
+func (l *logger) WithError(err error) Logger {
+	child := &logger{
+		root:   l.root,
+		fields: l.fields,
+		err:    err,
+		subs:   l.subs,
+	}
+	child.path.Store(l.sourcePath())
+	child.node.Store(l.treeNode())
+	return child
+}
+
+// ErrorInfo describes an error for structured reporters.
+type ErrorInfo struct {
+	Message string      `json:"message"`
+	Type    string      `json:"type"`
+	Chain   []ErrorInfo `json:"chain,omitempty"`
+}
+
+// NewErrorInfo describes err, and the chain of errors it wraps
+// (see errors.Unwrap).
+func NewErrorInfo(err error) *ErrorInfo {
+	if err == nil {
+		return nil
+	}
+	info := &ErrorInfo{Message: err.Error(), Type: fmt.Sprintf("%T", err)}
+	for wrapped := errors.Unwrap(err); wrapped != nil; wrapped = errors.Unwrap(wrapped) {
+		info.Chain = append(info.Chain, ErrorInfo{Message: wrapped.Error(), Type: fmt.Sprintf("%T", wrapped)})
+	}
+	return info
+}
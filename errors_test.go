@@ -0,0 +1,112 @@
+package slog_test
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/pat/stop"
+	"github.com/stretchr/slog"
+	"github.com/stretchr/testify/require"
+)
+
+func TestError(t *testing.T) {
+
+	var wg sync.WaitGroup
+
+	l := slog.New("parent", slog.LevelInfo)
+	defer func() {
+		l.Stop(stop.NoWait)
+		<-l.StopChan()
+	}()
+	r := NewTestReporter()
+	f := r.logFunc
+	r.logFunc = func(l *slog.Log) {
+		f(l)
+		wg.Done()
+	}
+	l.SetReporter(r)
+
+	err := errors.New("oops")
+	wg.Add(1)
+	l.Err("save failed", slog.Error(err))
+	wg.Wait()
+
+	require.Equal(t, 1, len(r.logs))
+	require.Equal(t, 2, len(r.logs[0].Data))
+	require.Equal(t, "save failed", r.logs[0].Data[1])
+	require.Equal(t, err, r.logs[0].Err)
+
+}
+
+func TestWithError(t *testing.T) {
+
+	var wg sync.WaitGroup
+
+	l := slog.New("parent", slog.LevelInfo)
+	defer func() {
+		l.Stop(stop.NoWait)
+		<-l.StopChan()
+	}()
+	r := NewTestReporter()
+	f := r.logFunc
+	r.logFunc = func(l *slog.Log) {
+		f(l)
+		wg.Done()
+	}
+	l.SetReporter(r)
+
+	err := errors.New("oops")
+	other := errors.New("other")
+	el := l.WithError(err).WithFields(slog.Fields{"id": 1})
+	wg.Add(3)
+	el.Err("failed")
+	el.New("child").Warn("retrying")
+	el.Err("failed", slog.Error(other))
+	wg.Wait()
+
+	require.Equal(t, 3, len(r.logs))
+	require.Equal(t, err, r.logs[0].Err)
+	require.Equal(t, slog.Fields{"id": 1}, r.logs[0].Fields)
+	require.Equal(t, err, r.logs[1].Err)
+	require.Equal(t, slog.Source{"parent", "child"}, r.logs[1].Source)
+	require.Equal(t, other, r.logs[2].Err)
+
+	require.Equal(t, slog.NilLogger, slog.NilLogger.WithError(err))
+
+}
+
+func TestErrorReporters(t *testing.T) {
+
+	when := time.Date(2015, 1, 2, 3, 4, 5, 0, time.UTC)
+	err := fmt.Errorf("saving: %w", errors.New("disk full"))
+	log := &slog.Log{
+		Level:  slog.LevelErr,
+		When:   when,
+		Source: []string{"parent"},
+		Data:   []interface{}{"failed"},
+		Err:    err,
+	}
+
+	var buf bytes.Buffer
+	slog.NewJSONReporter(&buf).Log(log)
+	require.JSONEq(t, `{
+		"level": "error",
+		"time": "2015-01-02T03:04:05Z",
+		"source": "parent",
+		"data": ["failed"],
+		"error": {
+			"message": "saving: disk full",
+			"type": "*fmt.wrapError",
+			"chain": [{"message": "disk full", "type": "*errors.errorString"}]
+		}
+	}`, buf.String())
+
+	buf.Reset()
+	slog.NewLogfmtReporter(&buf).Log(log)
+	require.Equal(t, "ts=2015-01-02T03:04:05Z level=error source=parent msg=failed err=\"saving: disk full\"\n", buf.String())
+
+}
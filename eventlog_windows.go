@@ -0,0 +1,107 @@
+package slog
+
+import (
+	"errors"
+	"io"
+	"sync"
+	"syscall"
+	"unsafe"
+)
+
+var (
+	advapi32                  = syscall.NewLazyDLL("advapi32.dll")
+	procRegisterEventSourceW  = advapi32.NewProc("RegisterEventSourceW")
+	procDeregisterEventSource = advapi32.NewProc("DeregisterEventSource")
+	procReportEventW          = advapi32.NewProc("ReportEventW")
+)
+
+// The event types of the Windows Event Log.
+const (
+	eventlogError       = 0x0001
+	eventlogWarning     = 0x0002
+	eventlogInformation = 0x0004
+)
+
+// eventlogTypes maps levels to event types.
+var eventlogTypes = map[Level]uint16{
+	LevelErr:   eventlogError,
+	LevelWarn:  eventlogWarning,
+	LevelInfo:  eventlogInformation,
+	LevelDebug: eventlogInformation,
+	LevelTrace: eventlogInformation,
+}
+
+// EventLogReporter is a Reporter that writes logs to the Windows
+// Event Log.
+// It is only available on Windows.
+type EventLogReporter struct {
+	m       sync.Mutex
+	handle  uintptr
+	eventID uint32
+}
+
+var _ ErrReporter = (*EventLogReporter)(nil)
+var _ io.Closer = (*EventLogReporter)(nil)
+
+// NewEventLogReporter makes an EventLogReporter that writes events
+// from the event source, with the event ID.
+// The source should be registered (e.g. with New-EventLog) for
+// Event Viewer to show the events without complaint.
+func NewEventLogReporter(source string, eventID uint32) (*EventLogReporter, error) {
+	name, err := syscall.UTF16PtrFromString(source)
+	if err != nil {
+		return nil, err
+	}
+	h, _, err := procRegisterEventSourceW.Call(0, uintptr(unsafe.Pointer(name)))
+	if h == 0 {
+		return nil, err
+	}
+	return &EventLogReporter{handle: h, eventID: eventID}, nil
+}
+
+// Log writes the log to the Event Log.
+func (e *EventLogReporter) Log(l *Log) {
+	e.Report(l)
+}
+
+// Report writes the log to the Event Log, returning an error if it
+// couldn't be written.
+// The message of the event is the source, Data, error and Fields
+// of the log.
+func (e *EventLogReporter) Report(l *Log) error {
+	typ, ok := eventlogTypes[l.Level]
+	if !ok {
+		typ = eventlogInformation
+	}
+	msg, err := syscall.UTF16PtrFromString(sprint(append([]interface{}{l.SourceString() + ":"}, l.text()...)))
+	if err != nil {
+		return err
+	}
+	e.m.Lock()
+	defer e.m.Unlock()
+	if e.handle == 0 {
+		return errors.New("slog: event log closed")
+	}
+	strs := []*uint16{msg}
+	r, _, err := procReportEventW.Call(e.handle, uintptr(typ), 0, uintptr(e.eventID), 0,
+		1, 0, uintptr(unsafe.Pointer(&strs[0])), 0)
+	if r == 0 {
+		return err
+	}
+	return nil
+}
+
+// Close deregisters the event source.
+func (e *EventLogReporter) Close() error {
+	e.m.Lock()
+	defer e.m.Unlock()
+	if e.handle == 0 {
+		return nil
+	}
+	r, _, err := procDeregisterEventSource.Call(e.handle)
+	e.handle = 0
+	if r == 0 {
+		return err
+	}
+	return nil
+}
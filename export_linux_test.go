@@ -0,0 +1,9 @@
+package slog
+
+// SetJournalSocket replaces the path of the journal socket,
+// returning a func that restores it.
+func SetJournalSocket(path string) (restore func()) {
+	old := journalSocket
+	journalSocket = path
+	return func() { journalSocket = old }
+}
@@ -0,0 +1,23 @@
+package slog
+
+import "time"
+
+// SetNow replaces the function the root logger uses to tell
+// the time.
+func SetNow(l RootLogger, now func() time.Time) {
+	l.SetClock(ClockFunc(now))
+}
+
+// SetExit replaces the function used to exit the program,
+// returning a func that restores it.
+func SetExit(f func(int)) (restore func()) {
+	old := exit
+	exit = f
+	return func() { exit = old }
+}
+
+// SetSamplerNow replaces the function the Sampler uses to tell
+// the time.
+func SetSamplerNow(s *Sampler, now func() time.Time) {
+	s.SetClock(ClockFunc(now))
+}
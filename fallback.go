@@ -0,0 +1,44 @@
+package slog
+
+import "io"
+
+type fallback struct {
+	primary   Reporter
+	secondary Reporter
+}
+
+// Fallback gets a Reporter that reports logs to primary, and to
+// secondary when primary fails, e.g. to a local file while a
+// network sink is down.
+// Only an ErrReporter can fail, so primary should be one.
+// The Reporter is itself an ErrReporter, failing if both fail.
+func Fallback(primary, secondary Reporter) Reporter {
+	return &fallback{primary: primary, secondary: secondary}
+}
+
+func (f *fallback) Log(l *Log) {
+	f.Report(l)
+}
+
+func (f *fallback) Report(l *Log) error {
+	if err := report(f.primary, l); err == nil {
+		return nil
+	}
+	return report(f.secondary, l)
+}
+
+// Close closes both reporters that are io.Closers, returning the
+// first error.
+func (f *fallback) Close() error {
+	return reporters{f.primary, f.secondary}.Close()
+}
+
+var _ io.Closer = (*fallback)(nil)
+
+func (f *fallback) Flush() {
+	reporters{f.primary, f.secondary}.Flush()
+}
+
+func (f *fallback) Start() {
+	reporters{f.primary, f.secondary}.Start()
+}
@@ -0,0 +1,45 @@
+package slog_test
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/slog"
+	"github.com/stretchr/testify/require"
+)
+
+// failingWriter fails every write while failing is set.
+type failingWriter struct {
+	bytes.Buffer
+	failing bool
+}
+
+func (w *failingWriter) Write(p []byte) (int, error) {
+	if w.failing {
+		return 0, errors.New("down")
+	}
+	return w.Buffer.Write(p)
+}
+
+func TestFallback(t *testing.T) {
+
+	primary := &failingWriter{}
+	secondary := &failingWriter{}
+	format := slog.FormatterFunc(func(l *slog.Log) []byte {
+		return []byte(l.Data[0].(string) + "\n")
+	})
+	r := slog.Fallback(slog.NewWriterReporter(primary, format), slog.NewWriterReporter(secondary, format))
+
+	r.Log(&slog.Log{Data: []interface{}{"one"}})
+	primary.failing = true
+	r.Log(&slog.Log{Data: []interface{}{"two"}})
+	secondary.failing = true
+	require.Error(t, r.(slog.ErrReporter).Report(&slog.Log{Data: []interface{}{"three"}}))
+	primary.failing = false
+	require.NoError(t, r.(slog.ErrReporter).Report(&slog.Log{Data: []interface{}{"four"}}))
+
+	require.Equal(t, "one\nfour\n", primary.String())
+	require.Equal(t, "two\n", secondary.String())
+
+}
@@ -0,0 +1,70 @@
+package slog
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+)
+
+// Fields represents structured key/value data that can be
+// attached to logs.
+// Passing Fields to a Logger method adds them to the Log rather
+// than its Data.
+type Fields map[string]interface{}
+
+// merge makes new Fields, containing the fields from f and then
+// from each of fs in turn.
+// f is returned if there is nothing to merge.
+func (f Fields) merge(fs ...Fields) Fields {
+	if len(fs) == 0 {
+		return f
+	}
+	merged := make(Fields, len(f))
+	for k, v := range f {
+		merged[k] = v
+	}
+	for _, more := range fs {
+		for k, v := range more {
+			merged[k] = v
+		}
+	}
+	return merged
+}
+
+// keys gets the keys of the fields in order.
+func (f Fields) keys() []string {
+	keys := make([]string, 0, len(f))
+	for k := range f {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// String gets the fields as key=value pairs, ordered by key.
+func (f Fields) String() string {
+	var buf bytes.Buffer
+	for i, k := range f.keys() {
+		if i > 0 {
+			buf.WriteByte(' ')
+		}
+		fmt.Fprintf(&buf, "%s=%v", k, f[k])
+	}
+	return buf.String()
+}
+
+func (l *logger) WithFields(fields Fields) Logger {
+	child := &logger{
+		root:   l.root,
+		fields: l.fields.merge(fields),
+		err:    l.err,
+		subs:   l.subs,
+	}
+	child.path.Store(l.sourcePath())
+	child.node.Store(l.treeNode())
+	return child
+}
+
+func (l *logger) With(key string, value interface{}) Logger {
+	return l.WithFields(Fields{key: value})
+}
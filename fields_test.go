@@ -0,0 +1,115 @@
+package slog_test
+
+import (
+	"bytes"
+	"log"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/pat/stop"
+	"github.com/stretchr/slog"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFields(t *testing.T) {
+
+	var wg sync.WaitGroup
+
+	l := slog.New("parent", slog.LevelInfo)
+	defer func() {
+		l.Stop(stop.NoWait)
+		<-l.StopChan()
+	}()
+	r := NewTestReporter()
+	f := r.logFunc
+	r.logFunc = func(l *slog.Log) {
+		f(l)
+		wg.Done()
+	}
+	l.SetReporter(r)
+
+	wg.Add(1)
+	l.Info("request done", slog.Fields{"status": 200})
+	wg.Wait()
+
+	require.Equal(t, 1, len(r.logs))
+	require.Equal(t, 2, len(r.logs[0].Data))
+	require.Equal(t, "request done", r.logs[0].Data[1])
+	require.Equal(t, slog.Fields{"status": 200}, r.logs[0].Fields)
+
+}
+
+func TestWithFields(t *testing.T) {
+
+	var wg sync.WaitGroup
+
+	l := slog.New("parent", slog.LevelInfo)
+	defer func() {
+		l.Stop(stop.NoWait)
+		<-l.StopChan()
+	}()
+	r := NewTestReporter()
+	f := r.logFunc
+	r.logFunc = func(l *slog.Log) {
+		f(l)
+		wg.Done()
+	}
+	l.SetReporter(r)
+
+	fl := l.WithFields(slog.Fields{"request_id": "abc", "status": 0})
+	child := fl.New("child")
+
+	wg.Add(3)
+	fl.Info("one")
+	child.Info("two", slog.Fields{"status": 200})
+	l.Info("three")
+	wg.Wait()
+
+	require.Equal(t, 3, len(r.logs))
+	require.Equal(t, slog.Source{"parent"}, r.logs[0].Source)
+	require.Equal(t, slog.Fields{"request_id": "abc", "status": 0}, r.logs[0].Fields)
+	require.Equal(t, slog.Source{"parent", "child"}, r.logs[1].Source)
+	require.Equal(t, slog.Fields{"request_id": "abc", "status": 200}, r.logs[1].Fields)
+	require.Nil(t, r.logs[2].Fields)
+
+}
+
+func TestWith(t *testing.T) {
+
+	var wg sync.WaitGroup
+
+	l := slog.New("parent", slog.LevelInfo)
+	defer func() {
+		l.Stop(stop.NoWait)
+		<-l.StopChan()
+	}()
+	r := NewTestReporter()
+	f := r.logFunc
+	r.logFunc = func(l *slog.Log) {
+		f(l)
+		wg.Done()
+	}
+	l.SetReporter(r)
+
+	tl := l.With("tenant", "acme").With("request_id", "abc")
+	wg.Add(2)
+	tl.Info("one")
+	tl.New("child").With("request_id", "def").Info("two")
+	wg.Wait()
+
+	require.Equal(t, slog.Fields{"tenant": "acme", "request_id": "abc"}, r.logs[0].Fields)
+	require.Equal(t, slog.Fields{"tenant": "acme", "request_id": "def"}, r.logs[1].Fields)
+	require.Equal(t, slog.Source{"parent", "child"}, r.logs[1].Source)
+	require.Equal(t, slog.NilLogger, slog.NilLogger.With("tenant", "acme"))
+
+}
+
+func TestLogReporterFields(t *testing.T) {
+
+	var buf bytes.Buffer
+	r := slog.NewLogReporter(log.New(&buf, "", 0), false)
+	r.Log(&slog.Log{Source: []string{"parent"}, Data: []interface{}{"message"}, Fields: slog.Fields{"b": 2, "a": 1}})
+
+	require.Equal(t, "parent: message a=1 b=2\n", buf.String())
+
+}
@@ -0,0 +1,476 @@
+package slog
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// FileOptions represents the options for a FileReporter.
+// The zero value writes to a single file that is never rotated.
+type FileOptions struct {
+	// MaxSize is the size in bytes the file may grow to before it
+	// is rotated. Zero means it is never rotated.
+	MaxSize int64
+	// MaxBackups is the number of rotated files to keep, named
+	// path.1 (the newest), path.2 and so on. Older files are
+	// removed.
+	MaxBackups int
+	// Compress is whether rotated files are gzip compressed,
+	// adding .gz to their names. Files are compressed in the
+	// background, and the next rotation or Close waits for them.
+	Compress bool
+	// Compressor compresses rotated files instead of gzip, e.g.
+	// with zstd. Setting it implies Compress.
+	Compressor *Compressor
+	// OnRotate, if set, is called with the path of each rotated
+	// file once it has been compressed, or with the error if it
+	// couldn't be. It is called in the background, and must not
+	// log to the FileReporter.
+	OnRotate func(path string, err error)
+	// Formatter formats the logs. If nil, they are written as
+	// the Reporter made by NewLogReporter writes them.
+	Formatter Formatter
+	// RotateEvery is how often the file is rotated, as well as when
+	// it gets too big, e.g. time.Hour or 24*time.Hour. The file is
+	// rotated on the first write after each multiple of RotateEvery
+	// from midnight in Location. Zero means it is only rotated by
+	// size.
+	RotateEvery time.Duration
+	// Location is the time zone of RotateEvery and BackupLayout,
+	// defaulting to time.Local.
+	Location *time.Location
+	// BackupLayout, if set, names rotated files by the time they
+	// were started instead of numbering them. It is a time layout
+	// for the name of the file in the directory of path, e.g.
+	// "app-2006-01-02.log". With BackupLayout, zero MaxBackups
+	// keeps them all.
+	BackupLayout string
+	// MaxAge is how long rotated files are kept for after they
+	// were last written. Zero keeps them until MaxBackups removes
+	// them. Old files are removed after each rotation, and every
+	// hour.
+	MaxAge time.Duration
+}
+
+// Compressor compresses rotated files.
+type Compressor struct {
+	// Ext is added to the names of compressed files, e.g. ".gz".
+	Ext string
+	// NewWriter gets a writer that compresses what is written to
+	// it to w.
+	NewWriter func(w io.Writer) (io.WriteCloser, error)
+}
+
+// GzipCompressor compresses rotated files with gzip.
+var GzipCompressor = &Compressor{
+	Ext: ".gz",
+	NewWriter: func(w io.Writer) (io.WriteCloser, error) {
+		return gzip.NewWriter(w), nil
+	},
+}
+
+// FileReporter is a Reporter that writes logs to a file,
+// rotating it when it gets too big.
+type FileReporter struct {
+	m      sync.Mutex
+	path   string
+	opts   FileOptions
+	f      *os.File
+	size   int64
+	closed bool      // whether Close has been called
+	start  time.Time // when the current file was started
+	next   time.Time // when the file is next rotated by time
+	r      Reporter
+	clock  Clock
+	stop   chan struct{}
+	once   sync.Once
+	busy   sync.WaitGroup // rotated files being compressed
+}
+
+var _ ErrReporter = (*FileReporter)(nil)
+var _ Syncer = (*FileReporter)(nil)
+var _ io.Closer = (*FileReporter)(nil)
+
+type writerFunc func(p []byte) (int, error)
+
+func (w writerFunc) Write(p []byte) (int, error) {
+	return w(p)
+}
+
+// NewFileReporter makes a FileReporter that appends logs to
+// the file at path, creating it if needed.
+func NewFileReporter(path string, opts FileOptions) (*FileReporter, error) {
+	if opts.Location == nil {
+		opts.Location = time.Local
+	}
+	if opts.Compress && opts.Compressor == nil {
+		opts.Compressor = GzipCompressor
+	}
+	f := &FileReporter{path: path, opts: opts, clock: SystemClock, stop: make(chan struct{})}
+	if err := f.open(); err != nil {
+		return nil, err
+	}
+	if opts.MaxAge > 0 {
+		go f.clean()
+	}
+	if opts.Formatter != nil {
+		f.r = NewWriterReporter(writerFunc(f.write), opts.Formatter)
+	} else {
+		f.r = NewLogReporter(log.New(writerFunc(f.write), "", log.LstdFlags), false)
+	}
+	return f, nil
+}
+
+// Log writes the log to the file.
+func (f *FileReporter) Log(l *Log) {
+	f.r.Log(l)
+}
+
+// Report writes the log to the file, returning an error if it
+// couldn't be written.
+func (f *FileReporter) Report(l *Log) error {
+	return report(f.r, l)
+}
+
+// Sync commits the logs written so far to disk.
+func (f *FileReporter) Sync() error {
+	f.m.Lock()
+	defer f.m.Unlock()
+	if f.f == nil {
+		return os.ErrClosed
+	}
+	return f.f.Sync()
+}
+
+// SetClock sets the Clock used for RotateEvery and MaxAge, which
+// is SystemClock by default.
+func (f *FileReporter) SetClock(c Clock) {
+	f.m.Lock()
+	f.clock = c
+	if f.opts.RotateEvery > 0 {
+		f.start = f.period(c.Now())
+		f.next = f.start.Add(f.opts.RotateEvery)
+	}
+	f.m.Unlock()
+}
+
+// Reopen closes the file and opens path again, so logs go to a
+// new file after something else, such as logrotate(8), has moved
+// the old one away, and the size is right after it has been
+// truncated. If the file can't be opened, it is tried again on the
+// next write.
+func (f *FileReporter) Reopen() error {
+	f.m.Lock()
+	defer f.m.Unlock()
+	if f.closed {
+		return os.ErrClosed
+	}
+	if f.f != nil {
+		err := f.f.Close()
+		f.f = nil
+		if err != nil {
+			return err
+		}
+	}
+	return f.open()
+}
+
+// Close closes the file, waiting for rotated files to be
+// compressed.
+func (f *FileReporter) Close() error {
+	f.once.Do(func() { close(f.stop) })
+	defer f.busy.Wait()
+	f.m.Lock()
+	defer f.m.Unlock()
+	f.closed = true
+	if f.f == nil {
+		return nil
+	}
+	err := f.f.Close()
+	f.f = nil
+	return err
+}
+
+func (f *FileReporter) open() error {
+	file, err := os.OpenFile(f.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return err
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return err
+	}
+	f.f = file
+	f.size = info.Size()
+	now := f.clock.Now()
+	f.start = now
+	if every := f.opts.RotateEvery; every > 0 {
+		f.start = f.period(now)
+		f.next = f.start.Add(every)
+	}
+	return nil
+}
+
+// period gets the start of the rotation period t is in.
+func (f *FileReporter) period(t time.Time) time.Time {
+	t = t.In(f.opts.Location)
+	midnight := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, f.opts.Location)
+	return midnight.Add(t.Sub(midnight) / f.opts.RotateEvery * f.opts.RotateEvery)
+}
+
+func (f *FileReporter) write(p []byte) (int, error) {
+	f.m.Lock()
+	defer f.m.Unlock()
+	if f.closed {
+		return 0, os.ErrClosed
+	}
+	if f.f == nil {
+		if err := f.open(); err != nil {
+			return 0, err
+		}
+	}
+	tooBig := f.opts.MaxSize > 0 && f.size > 0 && f.size+int64(len(p)) > f.opts.MaxSize
+	expired := f.opts.RotateEvery > 0 && !f.clock.Now().Before(f.next)
+	if tooBig || expired {
+		if err := f.rotate(); err != nil {
+			return 0, err
+		}
+	}
+	n, err := f.f.Write(p)
+	f.size += int64(n)
+	return n, err
+}
+
+// rotate moves the current file to path.1, shifting older files
+// along, or to the name made from BackupLayout, and opens a new
+// file.
+// If the current file can't be closed, it is dropped, so the next
+// write opens it again rather than failing for good.
+func (f *FileReporter) rotate() error {
+	err := f.f.Close()
+	f.f = nil
+	if err != nil {
+		return err
+	}
+	// the files can't be moved while the last one is compressed
+	f.busy.Wait()
+	if f.opts.BackupLayout != "" {
+		return f.rotateByTime()
+	}
+	if f.opts.MaxBackups == 0 {
+		os.Remove(f.path)
+		return f.open()
+	}
+	// backups that failed to compress are shifted along too, so
+	// they aren't overwritten
+	exts := []string{""}
+	if ext := f.ext(); ext != "" {
+		exts = append(exts, ext)
+	}
+	for _, ext := range exts {
+		os.Remove(f.backup(f.opts.MaxBackups) + ext)
+		for n := f.opts.MaxBackups - 1; n > 0; n-- {
+			os.Rename(f.backup(n)+ext, f.backup(n+1)+ext)
+		}
+	}
+	if err := os.Rename(f.path, f.backup(1)); err != nil {
+		return err
+	}
+	f.rotated(f.backup(1))
+	return f.open()
+}
+
+// ext gets the extension added to compressed files.
+func (f *FileReporter) ext() string {
+	if f.opts.Compressor == nil {
+		return ""
+	}
+	return f.opts.Compressor.Ext
+}
+
+// rotated compresses the rotated file, removes old backups, and
+// calls OnRotate, in the background.
+func (f *FileReporter) rotated(path string) {
+	now := f.clock.Now()
+	f.busy.Add(1)
+	go func() {
+		defer f.busy.Done()
+		var err error
+		if c := f.opts.Compressor; c != nil {
+			if err = compressFile(path, c); err == nil {
+				path += c.Ext
+			}
+		}
+		f.removeOld(now)
+		if f.opts.OnRotate != nil {
+			f.opts.OnRotate(path, err)
+		}
+	}()
+}
+
+func (f *FileReporter) backup(n int) string {
+	return fmt.Sprintf("%s.%d", f.path, n)
+}
+
+// rotateByTime moves the current file to the name made from
+// BackupLayout and the time it was started, removes old backups,
+// and opens a new file.
+func (f *FileReporter) rotateByTime() error {
+	name := filepath.Join(filepath.Dir(f.path), f.start.In(f.opts.Location).Format(f.opts.BackupLayout))
+	// more than one file may be started in the same period when
+	// they are also rotated by size
+	backup := name
+	for n := 1; f.exists(backup); n++ {
+		backup = fmt.Sprintf("%s.%d", name, n)
+	}
+	if err := os.Rename(f.path, backup); err != nil {
+		return err
+	}
+	f.rotated(backup)
+	return f.open()
+}
+
+// exists gets whether the backup exists, compressed or not.
+func (f *FileReporter) exists(backup string) bool {
+	for _, name := range []string{backup, backup + f.ext()} {
+		if _, err := os.Stat(name); err == nil {
+			return true
+		}
+	}
+	return false
+}
+
+// backupFile is a rotated file.
+type backupFile struct {
+	path    string
+	modTime time.Time
+}
+
+// backups gets the rotated files, newest first.
+func (f *FileReporter) backups() []backupFile {
+	dir := filepath.Dir(f.path)
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil
+	}
+	base := filepath.Base(f.path)
+	var backups []backupFile
+	for _, e := range entries {
+		name := e.Name()
+		if name == base || !f.isBackup(name) {
+			continue
+		}
+		info, err := e.Info()
+		if err != nil {
+			continue
+		}
+		backups = append(backups, backupFile{path: filepath.Join(dir, name), modTime: info.ModTime()})
+	}
+	sort.Slice(backups, func(i, j int) bool {
+		return backups[i].modTime.After(backups[j].modTime)
+	})
+	return backups
+}
+
+// isBackup gets whether the file name in the directory of path is
+// a rotated file.
+func (f *FileReporter) isBackup(name string) bool {
+	name = strings.TrimSuffix(name, f.ext())
+	if f.opts.BackupLayout == "" {
+		prefix := filepath.Base(f.path) + "."
+		if !strings.HasPrefix(name, prefix) {
+			return false
+		}
+		n, err := strconv.Atoi(name[len(prefix):])
+		return err == nil && n > 0
+	}
+	// strip the number added to backups started in the same period
+	if i := strings.LastIndexByte(name, '.'); i >= 0 {
+		if _, err := strconv.Atoi(name[i+1:]); err == nil {
+			if _, err := time.ParseInLocation(f.opts.BackupLayout, name[:i], f.opts.Location); err == nil {
+				return true
+			}
+		}
+	}
+	_, err := time.ParseInLocation(f.opts.BackupLayout, name, f.opts.Location)
+	return err == nil
+}
+
+// removeOld removes the rotated files older than MaxAge at now,
+// and, with BackupLayout, those beyond MaxBackups.
+func (f *FileReporter) removeOld(now time.Time) {
+	if f.opts.MaxAge == 0 && (f.opts.BackupLayout == "" || f.opts.MaxBackups == 0) {
+		return
+	}
+	oldest := now.Add(-f.opts.MaxAge)
+	for n, b := range f.backups() {
+		tooMany := f.opts.BackupLayout != "" && f.opts.MaxBackups > 0 && n >= f.opts.MaxBackups
+		tooOld := f.opts.MaxAge > 0 && b.modTime.Before(oldest)
+		if tooMany || tooOld {
+			os.Remove(b.path)
+		}
+	}
+}
+
+// clean removes old rotated files every hour until the
+// FileReporter is closed.
+func (f *FileReporter) clean() {
+	t := time.NewTicker(time.Hour)
+	defer t.Stop()
+	for {
+		select {
+		case <-t.C:
+			f.m.Lock()
+			f.busy.Wait()
+			f.removeOld(f.clock.Now())
+			f.m.Unlock()
+		case <-f.stop:
+			return
+		}
+	}
+}
+
+// compressFile compresses the file at path to path with the
+// extension of the Compressor, and removes the original.
+func compressFile(path string, c *Compressor) error {
+	in, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	out, err := os.Create(path + c.Ext)
+	if err != nil {
+		return err
+	}
+	// a half written file is removed, leaving the original
+	fail := func(err error) error {
+		out.Close()
+		os.Remove(path + c.Ext)
+		return err
+	}
+	w, err := c.NewWriter(out)
+	if err != nil {
+		return fail(err)
+	}
+	if _, err := io.Copy(w, in); err != nil {
+		return fail(err)
+	}
+	if err := w.Close(); err != nil {
+		return fail(err)
+	}
+	if err := out.Close(); err != nil {
+		os.Remove(path + c.Ext)
+		return err
+	}
+	return os.Remove(path)
+}
@@ -0,0 +1,303 @@
+package slog_test
+
+import (
+	"compress/gzip"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/slog"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileReporter(t *testing.T) {
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "app.log")
+
+	r, err := slog.NewFileReporter(path, slog.FileOptions{})
+	require.NoError(t, err)
+	r.Log(&slog.Log{Source: []string{"parent"}, Data: []interface{}{"one"}})
+	r.Log(&slog.Log{Source: []string{"parent"}, Data: []interface{}{"two"}})
+	require.NoError(t, r.Close())
+
+	b, err := os.ReadFile(path)
+	require.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(string(b)), "\n")
+	require.Equal(t, 2, len(lines))
+	require.True(t, strings.HasSuffix(lines[0], "parent: one"))
+	require.True(t, strings.HasSuffix(lines[1], "parent: two"))
+
+}
+
+func TestFileReporterReopen(t *testing.T) {
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "app.log")
+
+	r, err := slog.NewFileReporter(path, slog.FileOptions{})
+	require.NoError(t, err)
+	r.Log(&slog.Log{Source: []string{"parent"}, Data: []interface{}{"1"}})
+	require.NoError(t, os.Rename(path, path+".old"))
+	r.Log(&slog.Log{Source: []string{"parent"}, Data: []interface{}{"2"}})
+	require.NoError(t, r.Reopen())
+	r.Log(&slog.Log{Source: []string{"parent"}, Data: []interface{}{"3"}})
+	require.NoError(t, r.Close())
+	require.Equal(t, os.ErrClosed, r.Reopen())
+
+	require.Equal(t, []string{"1", "2"}, fileLogs(t, path+".old"))
+	require.Equal(t, []string{"3"}, fileLogs(t, path))
+
+}
+
+func TestFileReporterRotation(t *testing.T) {
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "app.log")
+
+	// each line is 30 bytes, so two fit in each file
+	r, err := slog.NewFileReporter(path, slog.FileOptions{MaxSize: 60, MaxBackups: 2})
+	require.NoError(t, err)
+	for _, d := range []string{"1", "2", "3", "4", "5", "6", "7"} {
+		r.Log(&slog.Log{Source: []string{"parent"}, Data: []interface{}{d}})
+	}
+	require.NoError(t, r.Close())
+
+	files, err := filepath.Glob(path + "*")
+	require.NoError(t, err)
+	require.Equal(t, []string{path, path + ".1", path + ".2"}, files)
+	require.Equal(t, []string{"7"}, fileLogs(t, path))
+	require.Equal(t, []string{"5", "6"}, fileLogs(t, path+".1"))
+	require.Equal(t, []string{"3", "4"}, fileLogs(t, path+".2"))
+
+}
+
+func TestFileReporterCompress(t *testing.T) {
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "app.log")
+
+	r, err := slog.NewFileReporter(path, slog.FileOptions{MaxSize: 60, MaxBackups: 1, Compress: true})
+	require.NoError(t, err)
+	for _, d := range []string{"1", "2", "3", "4", "5"} {
+		r.Log(&slog.Log{Source: []string{"parent"}, Data: []interface{}{d}})
+	}
+	require.NoError(t, r.Close())
+
+	files, err := filepath.Glob(path + "*")
+	require.NoError(t, err)
+	require.Equal(t, []string{path, path + ".1.gz"}, files)
+
+	f, err := os.Open(path + ".1.gz")
+	require.NoError(t, err)
+	defer f.Close()
+	gz, err := gzip.NewReader(f)
+	require.NoError(t, err)
+	b, err := io.ReadAll(gz)
+	require.NoError(t, err)
+	require.Contains(t, string(b), "parent: 3\n")
+	require.Contains(t, string(b), "parent: 4\n")
+
+}
+
+func TestFileReporterCompressFails(t *testing.T) {
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "app.log")
+	broken := &slog.Compressor{Ext: ".z", NewWriter: func(io.Writer) (io.WriteCloser, error) {
+		return nil, errors.New("broken")
+	}}
+
+	r, err := slog.NewFileReporter(path, slog.FileOptions{MaxSize: 60, MaxBackups: 2, Compressor: broken})
+	require.NoError(t, err)
+	for _, d := range []string{"1", "2", "3", "4", "5"} {
+		r.Log(&slog.Log{Source: []string{"parent"}, Data: []interface{}{d}})
+	}
+	require.NoError(t, r.Close())
+
+	// the backups that couldn't be compressed are kept
+	files, err := filepath.Glob(path + "*")
+	require.NoError(t, err)
+	require.Equal(t, []string{path, path + ".1", path + ".2"}, files)
+	b, err := os.ReadFile(path + ".2")
+	require.NoError(t, err)
+	require.Contains(t, string(b), "parent: 1\n")
+	b, err = os.ReadFile(path + ".1")
+	require.NoError(t, err)
+	require.Contains(t, string(b), "parent: 3\n")
+
+}
+
+func TestFileReporterOnRotate(t *testing.T) {
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "app.log")
+
+	var m sync.Mutex
+	var rotated []string
+	var errs []error
+	r, err := slog.NewFileReporter(path, slog.FileOptions{
+		MaxSize:    60,
+		MaxBackups: 2,
+		Compressor: &slog.Compressor{
+			Ext: ".upper",
+			NewWriter: func(w io.Writer) (io.WriteCloser, error) {
+				return upperWriter{w}, nil
+			},
+		},
+		OnRotate: func(path string, err error) {
+			m.Lock()
+			rotated = append(rotated, filepath.Base(path))
+			errs = append(errs, err)
+			m.Unlock()
+		},
+	})
+	require.NoError(t, err)
+	for _, d := range []string{"a", "b", "c", "d", "e"} {
+		r.Log(&slog.Log{Source: []string{"parent"}, Data: []interface{}{d}})
+	}
+	require.NoError(t, r.Close())
+
+	require.Equal(t, []string{"app.log.1.upper", "app.log.1.upper"}, rotated)
+	require.Equal(t, []error{nil, nil}, errs)
+	files, err := filepath.Glob(path + "*")
+	require.NoError(t, err)
+	require.Equal(t, []string{path, path + ".1.upper", path + ".2.upper"}, files)
+	require.Equal(t, []string{"C", "D"}, fileLogs(t, path+".1.upper"))
+
+}
+
+// upperWriter is a Compressor writer that upper cases what is
+// written to it.
+type upperWriter struct {
+	w io.Writer
+}
+
+func (u upperWriter) Write(p []byte) (int, error) {
+	return u.w.Write([]byte(strings.ToUpper(string(p))))
+}
+
+func (u upperWriter) Close() error {
+	return nil
+}
+
+func TestFileReporterRotateEvery(t *testing.T) {
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "app.log")
+
+	clock := &testClock{now: time.Date(2015, 1, 1, 22, 30, 0, 0, time.UTC)}
+	r, err := slog.NewFileReporter(path, slog.FileOptions{
+		RotateEvery:  time.Hour,
+		Location:     time.UTC,
+		BackupLayout: "app-2006-01-02T15.log",
+	})
+	require.NoError(t, err)
+	r.SetClock(clock)
+	r.Log(&slog.Log{Source: []string{"parent"}, Data: []interface{}{"1"}})
+	clock.Add(40 * time.Minute)
+	r.Log(&slog.Log{Source: []string{"parent"}, Data: []interface{}{"2"}})
+	clock.Add(time.Hour)
+	r.Log(&slog.Log{Source: []string{"parent"}, Data: []interface{}{"3"}})
+	require.NoError(t, r.Close())
+
+	files, err := filepath.Glob(filepath.Join(dir, "*"))
+	require.NoError(t, err)
+	require.Equal(t, []string{
+		filepath.Join(dir, "app-2015-01-01T22.log"),
+		filepath.Join(dir, "app-2015-01-01T23.log"),
+		path,
+	}, files)
+	require.Equal(t, []string{"1"}, fileLogs(t, filepath.Join(dir, "app-2015-01-01T22.log")))
+	require.Equal(t, []string{"2"}, fileLogs(t, filepath.Join(dir, "app-2015-01-01T23.log")))
+	require.Equal(t, []string{"3"}, fileLogs(t, path))
+
+}
+
+func TestFileReporterMaxAge(t *testing.T) {
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "app.log")
+
+	old := filepath.Join(dir, "app-2014-12-01.log")
+	recent := filepath.Join(dir, "app-2014-12-31.log")
+	other := filepath.Join(dir, "other.log")
+	for _, name := range []string{old, recent, other} {
+		require.NoError(t, os.WriteFile(name, []byte("old\n"), 0644))
+		require.NoError(t, os.Chtimes(name, time.Now().Add(-30*24*time.Hour), time.Now().Add(-30*24*time.Hour)))
+	}
+	require.NoError(t, os.Chtimes(recent, time.Now(), time.Now()))
+
+	r, err := slog.NewFileReporter(path, slog.FileOptions{
+		RotateEvery:  24 * time.Hour,
+		BackupLayout: "app-2006-01-02.log",
+		MaxAge:       7 * 24 * time.Hour,
+	})
+	require.NoError(t, err)
+	clock := &testClock{now: time.Now()}
+	r.SetClock(clock)
+	r.Log(&slog.Log{Source: []string{"parent"}, Data: []interface{}{"1"}})
+	clock.Add(24 * time.Hour)
+	r.Log(&slog.Log{Source: []string{"parent"}, Data: []interface{}{"2"}})
+	require.NoError(t, r.Close())
+
+	_, err = os.Stat(old)
+	require.True(t, os.IsNotExist(err))
+	_, err = os.Stat(recent)
+	require.NoError(t, err)
+	_, err = os.Stat(other)
+	require.NoError(t, err)
+	require.Equal(t, []string{"2"}, fileLogs(t, path))
+
+}
+
+func TestFileReporterMaxAgeNumbered(t *testing.T) {
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "app.log")
+
+	old := filepath.Join(dir, "app.log.1")
+	numbered := filepath.Join(dir, "5")
+	for _, name := range []string{old, numbered} {
+		require.NoError(t, os.WriteFile(name, []byte("old\n"), 0644))
+		require.NoError(t, os.Chtimes(name, time.Now().Add(-30*24*time.Hour), time.Now().Add(-30*24*time.Hour)))
+	}
+
+	r, err := slog.NewFileReporter(path, slog.FileOptions{
+		RotateEvery: 24 * time.Hour,
+		MaxBackups:  3,
+		MaxAge:      7 * 24 * time.Hour,
+	})
+	require.NoError(t, err)
+	clock := &testClock{now: time.Now()}
+	r.SetClock(clock)
+	r.Log(&slog.Log{Source: []string{"parent"}, Data: []interface{}{"1"}})
+	clock.Add(24 * time.Hour)
+	r.Log(&slog.Log{Source: []string{"parent"}, Data: []interface{}{"2"}})
+	require.NoError(t, r.Close())
+
+	// the old backup is removed, but not files that only look like
+	// backup numbers
+	_, err = os.Stat(filepath.Join(dir, "app.log.2"))
+	require.True(t, os.IsNotExist(err))
+	_, err = os.Stat(numbered)
+	require.NoError(t, err)
+	require.Equal(t, []string{"1"}, fileLogs(t, filepath.Join(dir, "app.log.1")))
+
+}
+
+// fileLogs gets the data of each log in the file.
+func fileLogs(t *testing.T, path string) []string {
+	b, err := os.ReadFile(path)
+	require.NoError(t, err)
+	var logs []string
+	for _, line := range strings.Split(strings.TrimSpace(string(b)), "\n") {
+		logs = append(logs, line[strings.LastIndex(line, " ")+1:])
+	}
+	return logs
+}
@@ -0,0 +1,151 @@
+package slog
+
+import (
+	"fmt"
+	"hash/fnv"
+	"io"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// SuppressedKey is the field a Suppressor puts the number of logs
+// it suppressed since the last one with the same fingerprint in.
+const SuppressedKey = "suppressed"
+
+// Fingerprint is a Hook that sets the Fingerprint of logs to a
+// hash of their level, source and message template, which is the
+// Template of logs made by the f methods, or the Data of others.
+// The file and line of the log aren't part of it, so it stays the
+// same as code moves around.
+func Fingerprint(l *Log) *Log {
+	l.Fingerprint = fingerprint(l)
+	return l
+}
+
+// fingerprint gets the fingerprint of the log.
+func fingerprint(l *Log) string {
+	h := fnv.New64a()
+	fmt.Fprintf(h, "%d\x00%s\x00", l.Level, l.Source.String())
+	if l.Template != "" {
+		io.WriteString(h, l.Template)
+	} else {
+		io.WriteString(h, l.Message())
+	}
+	return strconv.FormatUint(h.Sum64(), 16)
+}
+
+// Suppressor is a Reporter that reports the first of the logs with
+// the same fingerprint in each window to another Reporter, and
+// suppresses the rest, wherever they were logged from.
+// Logs without a Fingerprint are fingerprinted as the Fingerprint
+// hook would.
+type Suppressor struct {
+	m          sync.Mutex
+	r          Reporter
+	window     time.Duration
+	clock      Clock
+	seen       map[string]*suppression
+	pruned     time.Time
+	suppressed uint64
+}
+
+type suppression struct {
+	start time.Time // when the window started
+	n     int       // number of logs suppressed in it
+}
+
+var _ Reporter = (*Suppressor)(nil)
+var _ io.Closer = (*Suppressor)(nil)
+
+// NewSuppressor makes a Suppressor that reports to r, suppressing
+// logs with the same fingerprint as one reported within window.
+// The next log reported with that fingerprint has the number of
+// logs suppressed before it under SuppressedKey.
+func NewSuppressor(r Reporter, window time.Duration) *Suppressor {
+	return &Suppressor{
+		r:      r,
+		window: window,
+		clock:  SystemClock,
+		seen:   make(map[string]*suppression),
+	}
+}
+
+// Log reports the log, unless one with the same fingerprint was
+// reported within the window.
+func (s *Suppressor) Log(l *Log) {
+	key := l.Fingerprint
+	if key == "" {
+		key = fingerprint(l)
+	}
+	s.m.Lock()
+	now := s.clock.Now()
+	e := s.seen[key]
+	if e != nil && now.Sub(e.start) < s.window {
+		e.n++
+		s.suppressed++
+		s.m.Unlock()
+		return
+	}
+	n := 0
+	if e != nil {
+		n = e.n
+	}
+	s.prune(now)
+	s.seen[key] = &suppression{start: now}
+	s.m.Unlock()
+	if n > 0 {
+		summary := *l
+		summary.Fields = l.Fields.merge(Fields{SuppressedKey: n})
+		l = &summary
+	}
+	s.r.Log(l)
+}
+
+// prune forgets the fingerprints whose window has passed, at most
+// once each window, so the Suppressor doesn't keep growing.
+// Must be called with m held.
+func (s *Suppressor) prune(now time.Time) {
+	if now.Sub(s.pruned) < s.window {
+		return
+	}
+	s.pruned = now
+	for key, e := range s.seen {
+		if now.Sub(e.start) >= s.window {
+			delete(s.seen, key)
+		}
+	}
+}
+
+// SetClock sets the Clock used to tell when windows start, which
+// is SystemClock by default.
+func (s *Suppressor) SetClock(c Clock) {
+	s.m.Lock()
+	s.clock = c
+	s.m.Unlock()
+}
+
+// Suppressed gets the number of logs that have been suppressed.
+func (s *Suppressor) Suppressed() uint64 {
+	s.m.Lock()
+	defer s.m.Unlock()
+	return s.suppressed
+}
+
+// Flush flushes the Reporter, if it is a Flusher.
+func (s *Suppressor) Flush() {
+	reporters{s.r}.Flush()
+}
+
+// Start starts the Reporter, if it is a Starter.
+func (s *Suppressor) Start() {
+	reporters{s.r}.Start()
+}
+
+// Close closes the Reporter, if it is an io.Closer.
+func (s *Suppressor) Close() error {
+	if c, ok := s.r.(io.Closer); ok {
+		return c.Close()
+	}
+	return nil
+}
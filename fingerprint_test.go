@@ -0,0 +1,74 @@
+package slog_test
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/slog"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFingerprint(t *testing.T) {
+
+	l := slog.New("parent", slog.LevelDebug)
+	l.SetSync(true)
+	r := NewTestReporter()
+	l.SetReporter(r)
+	l.AddHook(slog.Fingerprint)
+
+	l.Infof("user %d logged in", 1)
+	l.Infof("user %d logged in", 2)
+	l.Warnf("user %d logged in", 3)
+	l.New("child").Infof("user %d logged in", 4)
+	l.Info("done")
+	l.Info("done")
+
+	require.Len(t, r.logs, 6)
+	require.Equal(t, "user %d logged in", r.logs[0].Template)
+	require.NotEmpty(t, r.logs[0].Fingerprint)
+	require.Equal(t, r.logs[0].Fingerprint, r.logs[1].Fingerprint)
+	require.NotEqual(t, r.logs[0].Fingerprint, r.logs[2].Fingerprint)
+	require.NotEqual(t, r.logs[0].Fingerprint, r.logs[3].Fingerprint)
+	require.Empty(t, r.logs[4].Template)
+	require.Equal(t, r.logs[4].Fingerprint, r.logs[5].Fingerprint)
+	require.NotEqual(t, r.logs[0].Fingerprint, r.logs[4].Fingerprint)
+
+}
+
+func TestSuppressor(t *testing.T) {
+
+	r := NewTestReporter()
+	s := slog.NewSuppressor(r, time.Minute)
+	clock := &testClock{now: time.Date(2015, 1, 1, 0, 0, 0, 0, time.UTC)}
+	s.SetClock(clock)
+
+	l := slog.New("parent", slog.LevelInfo)
+	l.SetSync(true)
+	l.SetReporter(s)
+	l.AddHook(slog.Fingerprint)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			l.Errf("request %d failed", i)
+		}(i)
+	}
+	wg.Wait()
+	l.Info("different")
+	require.Len(t, r.logs, 2)
+	require.Equal(t, uint64(9), s.Suppressed())
+
+	// logs without a fingerprint are fingerprinted too
+	s.Log(&slog.Log{Level: slog.LevelInfo, Source: slog.Source{"parent"}, Data: []interface{}{"different"}})
+	require.Len(t, r.logs, 2)
+
+	clock.Add(time.Minute)
+	l.Errf("request %d failed", 10)
+	require.Len(t, r.logs, 3)
+	require.Equal(t, "request 10 failed", r.logs[2].Data[1])
+	require.Equal(t, 9, r.logs[2].Fields[slog.SuppressedKey])
+
+}
@@ -0,0 +1,244 @@
+// Package gelf provides a slog.Reporter that sends logs to Graylog
+// in the GELF format, over UDP or TCP.
+package gelf
+
+import (
+	"bytes"
+	"compress/gzip"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/stretchr/slog"
+)
+
+// DefaultChunkSize is the size of the UDP chunks used when
+// Options.ChunkSize is zero, which fits in a typical MTU.
+const DefaultChunkSize = 1420
+
+// maxChunks is the most chunks a GELF message may be split into.
+const maxChunks = 128
+
+// levels maps levels to the syslog severities used by GELF.
+var levels = map[slog.Level]int{
+	slog.LevelErr:   3,
+	slog.LevelWarn:  4,
+	slog.LevelInfo:  6,
+	slog.LevelDebug: 7,
+	slog.LevelTrace: 7,
+}
+
+// reserved are the names of the additional fields the Reporter sets
+// itself, along with id, which GELF doesn't allow.
+var reserved = map[string]bool{
+	"id":       true,
+	"source":   true,
+	"file":     true,
+	"line":     true,
+	"function": true,
+	"error":    true,
+}
+
+// Options represents the options for a Reporter.
+type Options struct {
+	// Network is "udp" (the default) or "tcp".
+	Network string
+	// Addr is the address of the GELF input, e.g.
+	// "graylog:12201".
+	Addr string
+	// Host is the host sent with each message, defaulting to
+	// os.Hostname.
+	Host string
+	// Compress is whether UDP messages are gzip compressed.
+	Compress bool
+	// ChunkSize is the largest UDP datagram sent, defaulting to
+	// DefaultChunkSize. Larger messages are chunked.
+	ChunkSize int
+}
+
+// Reporter is a slog.Reporter that sends logs as GELF messages.
+// The source, caller, error and Fields of each log become
+// additional fields, and its stack the full message. Fields named
+// like the others, or id, get a trailing underscore, e.g. _error_.
+type Reporter struct {
+	m    sync.Mutex
+	opts Options
+	conn net.Conn
+}
+
+var _ slog.ErrReporter = (*Reporter)(nil)
+var _ io.Closer = (*Reporter)(nil)
+
+// New makes a Reporter and connects it to the GELF input.
+func New(opts Options) (*Reporter, error) {
+	if opts.Network == "" {
+		opts.Network = "udp"
+	}
+	if opts.Network != "udp" && opts.Network != "tcp" {
+		return nil, fmt.Errorf("gelf: unsupported network %q", opts.Network)
+	}
+	if opts.Host == "" {
+		opts.Host, _ = os.Hostname()
+	}
+	if opts.ChunkSize == 0 {
+		opts.ChunkSize = DefaultChunkSize
+	}
+	conn, err := net.Dial(opts.Network, opts.Addr)
+	if err != nil {
+		return nil, err
+	}
+	return &Reporter{opts: opts, conn: conn}, nil
+}
+
+// Log sends the log.
+func (r *Reporter) Log(l *slog.Log) {
+	r.Report(l)
+}
+
+// Report sends the log, returning an error if it couldn't be sent.
+// Over TCP it reconnects once if sending fails.
+func (r *Reporter) Report(l *slog.Log) error {
+	msg, err := r.message(l)
+	if err != nil {
+		return err
+	}
+	r.m.Lock()
+	defer r.m.Unlock()
+	if r.opts.Network == "udp" {
+		return r.sendUDP(msg)
+	}
+	msg = append(msg, 0)
+	if r.conn != nil {
+		if _, err := r.conn.Write(msg); err == nil {
+			return nil
+		}
+		r.conn.Close()
+		r.conn = nil
+	}
+	conn, err := net.Dial(r.opts.Network, r.opts.Addr)
+	if err != nil {
+		return err
+	}
+	r.conn = conn
+	_, err = conn.Write(msg)
+	return err
+}
+
+// Close closes the connection.
+func (r *Reporter) Close() error {
+	r.m.Lock()
+	defer r.m.Unlock()
+	if r.conn == nil {
+		return nil
+	}
+	err := r.conn.Close()
+	r.conn = nil
+	return err
+}
+
+// message makes the GELF message for the log.
+func (r *Reporter) message(l *slog.Log) ([]byte, error) {
+	level, ok := levels[l.Level]
+	if !ok {
+		level = levels[slog.LevelInfo]
+	}
+	m := map[string]interface{}{
+		"version":       "1.1",
+		"host":          r.opts.Host,
+		"short_message": l.Message(),
+		"timestamp":     float64(l.When.UnixNano()/1e6) / 1e3,
+		"level":         level,
+		"_source":       l.SourceString(),
+	}
+	for k, v := range l.Fields {
+		k = fieldName(k)
+		if k == "" {
+			continue
+		}
+		if _, err := json.Marshal(v); err != nil {
+			v = fmt.Sprint(v)
+		}
+		m["_"+k] = v
+	}
+	if l.Caller != nil {
+		m["_file"] = l.Caller.File
+		m["_line"] = l.Caller.Line
+		m["_function"] = l.Caller.Function
+	}
+	if l.Err != nil {
+		m["_error"] = l.Err.Error()
+	}
+	if l.Stack != "" {
+		m["full_message"] = l.Stack
+	}
+	return json.Marshal(m)
+}
+
+// fieldName gets the name of the additional field for the key of a
+// field, without the leading underscore. Characters GELF doesn't
+// allow in names become underscores, and reserved names get a
+// trailing one, so fields can't replace those the Reporter sets.
+func fieldName(k string) string {
+	k = strings.Map(func(r rune) rune {
+		if r == '.' || r == '-' || r == '_' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' {
+			return r
+		}
+		return '_'
+	}, k)
+	if reserved[k] {
+		k += "_"
+	}
+	return k
+}
+
+// errTooBig is the error for messages too big to chunk.
+var errTooBig = errors.New("gelf: message too big")
+
+// sendUDP sends the message as a datagram, or as chunks if it is
+// too big for one.
+func (r *Reporter) sendUDP(msg []byte) error {
+	if r.opts.Compress {
+		var buf bytes.Buffer
+		zw := gzip.NewWriter(&buf)
+		zw.Write(msg)
+		if err := zw.Close(); err != nil {
+			return err
+		}
+		msg = buf.Bytes()
+	}
+	if len(msg) <= r.opts.ChunkSize {
+		_, err := r.conn.Write(msg)
+		return err
+	}
+	const header = 12
+	size := r.opts.ChunkSize - header
+	count := (len(msg) + size - 1) / size
+	if count > maxChunks {
+		return errTooBig
+	}
+	id := make([]byte, 8)
+	if _, err := rand.Read(id); err != nil {
+		return err
+	}
+	chunk := make([]byte, 0, r.opts.ChunkSize)
+	for i := 0; i < count; i++ {
+		end := (i + 1) * size
+		if end > len(msg) {
+			end = len(msg)
+		}
+		chunk = append(chunk[:0], 0x1e, 0x0f)
+		chunk = append(chunk, id...)
+		chunk = append(chunk, byte(i), byte(count))
+		chunk = append(chunk, msg[i*size:end]...)
+		if _, err := r.conn.Write(chunk); err != nil {
+			return err
+		}
+	}
+	return nil
+}
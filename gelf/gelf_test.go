@@ -0,0 +1,111 @@
+package gelf_test
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/slog"
+	"github.com/stretchr/slog/gelf"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReporterUDP(t *testing.T) {
+
+	pc, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer pc.Close()
+
+	r, err := gelf.New(gelf.Options{Addr: pc.LocalAddr().String(), Host: "web-1", ChunkSize: 200})
+	require.NoError(t, err)
+	defer r.Close()
+
+	when := time.Date(2015, 1, 2, 3, 4, 5, 250000000, time.UTC)
+	require.NoError(t, r.Report(&slog.Log{
+		Level:  slog.LevelErr,
+		When:   when,
+		Source: []string{"parent", "child"},
+		Data:   []interface{}{"save", "failed"},
+		Fields: slog.Fields{"id": 1, "user": "mat"},
+		Err:    errors.New("disk full"),
+	}))
+	buf := make([]byte, 65536)
+	n, _, err := pc.ReadFrom(buf)
+	require.NoError(t, err)
+	require.JSONEq(t, `{
+		"version": "1.1",
+		"host": "web-1",
+		"short_message": "save failed",
+		"timestamp": 1420167845.25,
+		"level": 3,
+		"_source": "parent>child",
+		"_id_": 1,
+		"_user": "mat",
+		"_error": "disk full"
+	}`, string(buf[:n]))
+
+	// big messages are chunked
+	long := strings.Repeat("x", 1000)
+	require.NoError(t, r.Report(&slog.Log{Level: slog.LevelInfo, When: when, Data: []interface{}{long}}))
+	var msg []byte
+	var count int
+	for {
+		n, _, err := pc.ReadFrom(buf)
+		require.NoError(t, err)
+		require.True(t, n <= 200)
+		require.Equal(t, []byte{0x1e, 0x0f}, buf[:2])
+		require.Equal(t, count, int(buf[10]))
+		msg = append(msg, buf[12:n]...)
+		count++
+		if count == int(buf[11]) {
+			break
+		}
+	}
+	var m map[string]interface{}
+	require.NoError(t, json.Unmarshal(msg, &m))
+	require.Equal(t, long, m["short_message"])
+
+}
+
+func TestReporterTCP(t *testing.T) {
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer ln.Close()
+	messages := make(chan []byte)
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		br := bufio.NewReader(conn)
+		for {
+			msg, err := br.ReadBytes(0)
+			if err != nil {
+				return
+			}
+			messages <- bytes.TrimSuffix(msg, []byte{0})
+		}
+	}()
+
+	r, err := gelf.New(gelf.Options{Network: "tcp", Addr: ln.Addr().String(), Host: "web-1"})
+	require.NoError(t, err)
+	defer r.Close()
+
+	r.Log(&slog.Log{Level: slog.LevelWarn, When: time.Unix(1, 0), Source: []string{"parent"}, Data: []interface{}{"slow"}})
+	require.JSONEq(t, `{"version":"1.1","host":"web-1","short_message":"slow","timestamp":1,"level":4,"_source":"parent"}`, string(<-messages))
+
+	// fields can't replace those set by the reporter
+	r.Log(&slog.Log{Level: slog.LevelWarn, When: time.Unix(1, 0), Source: []string{"parent"}, Data: []interface{}{"slow"}, Fields: slog.Fields{"source": "api", "error": "mine", "the key": true, "": 2}, Err: errors.New("timeout")})
+	require.JSONEq(t, `{"version":"1.1","host":"web-1","short_message":"slow","timestamp":1,"level":4,"_source":"parent","_source_":"api","_error_":"mine","_the_key":true,"_error":"timeout"}`, string(<-messages))
+
+	_, err = gelf.New(gelf.Options{Network: "unix"})
+	require.Error(t, err)
+
+}
@@ -0,0 +1,34 @@
+package slog
+
+// Hints are given to the log methods like Fields, and tell the
+// Reporters that understand them how to deliver the log, e.g.
+//
+//	l.Err("payment failed", slog.Hints{"alert": true})
+//
+// Other Reporters ignore them, and formatters don't write them, so
+// they can change where a log goes, or how it is grouped, without a
+// new level.
+type Hints map[string]interface{}
+
+// Hint gets the value of the hint with the key, and whether the
+// log has it.
+func (l *Log) Hint(key string) (interface{}, bool) {
+	v, ok := l.Hints[key]
+	return v, ok
+}
+
+// merge gets the hints with more added, replacing any with the
+// same key.
+func (h Hints) merge(more Hints) Hints {
+	if h == nil {
+		return more
+	}
+	merged := make(Hints, len(h)+len(more))
+	for k, v := range h {
+		merged[k] = v
+	}
+	for k, v := range more {
+		merged[k] = v
+	}
+	return merged
+}
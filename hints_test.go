@@ -0,0 +1,50 @@
+package slog_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/slog"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHints(t *testing.T) {
+
+	l := slog.New("parent", slog.LevelInfo)
+	l.SetSync(true)
+	r := NewTestReporter()
+	l.SetReporter(r)
+
+	l.Err("payment failed", slog.Hints{"alert": true}, slog.Fields{"id": 1}, slog.Hints{"team": "billing"})
+	l.Info("paid")
+
+	require.Len(t, r.logs, 2)
+	require.Equal(t, slog.Hints{"alert": true, "team": "billing"}, r.logs[0].Hints)
+	require.Equal(t, slog.Fields{"id": 1}, r.logs[0].Fields)
+	require.Equal(t, []interface{}{"payment failed"}, r.logs[0].Data[1:])
+	v, ok := r.logs[0].Hint("alert")
+	require.True(t, ok)
+	require.Equal(t, true, v)
+	_, ok = r.logs[1].Hint("alert")
+	require.False(t, ok)
+
+	b := slog.TextFormatter.Format(r.logs[0])
+	require.NotContains(t, string(b), "alert")
+
+}
+
+func TestRulesHints(t *testing.T) {
+
+	alerts := NewTestReporter()
+	rest := NewTestReporter()
+	l := slog.New("parent", slog.LevelInfo)
+	l.SetSync(true)
+	l.SetReporter(slog.Rules(rest, slog.Rule{Hints: slog.Hints{"alert": true}, Reporter: alerts}))
+
+	l.Err("page someone", slog.Hints{"alert": true})
+	l.Err("don't", slog.Hints{"alert": false})
+	l.Err("plain")
+
+	require.Len(t, alerts.logs, 1)
+	require.Len(t, rest.logs, 2)
+
+}
@@ -0,0 +1,26 @@
+package slog
+
+// Hook is run on each log before it is reported, and may change
+// it, or return nil to drop it.
+// Hooks are run one log at a time, from the goroutine that
+// reports them.
+// The Fields of a log may be shared with other logs, so hooks
+// should replace them rather than change them.
+type Hook func(l *Log) *Log
+
+func (l *logger) AddHook(h Hook) {
+	root := l.root
+	root.rm.Lock()
+	root.hooks = append(root.hooks[:len(root.hooks):len(root.hooks)], h)
+	root.rm.Unlock()
+}
+
+// runHooks runs the hooks on the log, stopping if one drops it.
+func runHooks(hooks []Hook, l *Log) *Log {
+	for _, h := range hooks {
+		if l = h(l); l == nil {
+			return nil
+		}
+	}
+	return l
+}
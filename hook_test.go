@@ -0,0 +1,40 @@
+package slog_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/slog"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAddHook(t *testing.T) {
+
+	l := slog.New("parent", slog.LevelInfo)
+	r := NewTestReporter()
+	l.SetReporter(r)
+
+	var order []string
+	l.AddHook(func(log *slog.Log) *slog.Log {
+		order = append(order, "first")
+		log.Fields = slog.Fields{"version": "1.0"}
+		return log
+	})
+	l.AddHook(func(log *slog.Log) *slog.Log {
+		order = append(order, "second")
+		if log.Data[1] == "secret" {
+			return nil
+		}
+		return log
+	})
+
+	l.Info("public")
+	l.Info("secret")
+	require.NoError(t, l.StopContext(context.Background()))
+
+	require.Equal(t, []string{"first", "second", "first", "second"}, order)
+	require.Equal(t, 1, len(r.logs))
+	require.Equal(t, "public", r.logs[0].Data[1])
+	require.Equal(t, slog.Fields{"version": "1.0"}, r.logs[0].Fields)
+
+}
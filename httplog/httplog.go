@@ -0,0 +1,117 @@
+// Package httplog provides HTTP middleware that gives each request
+// its own child slog.Logger.
+package httplog
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/stretchr/slog"
+)
+
+// RequestIDHeader is the header holding the ID of a request.
+// If present, it is added to the logs of the request as
+// RequestIDKey.
+const RequestIDHeader = "X-Request-ID"
+
+// RequestIDKey is the field holding the ID of the request a log
+// was made for, from the RequestIDHeader.
+const RequestIDKey = "request_id"
+
+// Source is the source of the child loggers made for requests.
+const Source = "http"
+
+// CorrelationIDHeader is the header holding the correlation ID of
+// a request, which is added to its logs (see slog.CorrelationIDKey).
+// A new one is made for requests without one, and it is set on
+// every response.
+const CorrelationIDHeader = "X-Correlation-ID"
+
+// Handler makes an http.Handler that creates a child of l for
+// each request, stores it in the request context (see
+// slog.FromContext), with the correlation ID of the request, and
+// logs when the request starts and finishes.
+// The source of the child is Source, and the method, path and
+// request ID of the request are fields of its logs. The child is
+// released once the request is done.
+func Handler(l slog.Logger, h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		child := l.Acquire(Source)
+		defer child.Release()
+		request := slog.Fields{
+			"method": r.Method,
+			"path":   r.URL.Path,
+		}
+		if rid := r.Header.Get(RequestIDHeader); rid != "" {
+			request[RequestIDKey] = rid
+		}
+		rl := child.WithFields(request)
+		id := r.Header.Get(CorrelationIDHeader)
+		if id == "" {
+			rl, id = slog.WithCorrelationID(rl)
+		} else {
+			rl = rl.With(slog.CorrelationIDKey, id)
+		}
+		w.Header().Set(CorrelationIDHeader, id)
+		rl.Info("started", slog.Fields{"remote": r.RemoteAddr})
+
+		start := time.Now()
+		rw := &responseWriter{ResponseWriter: w, status: http.StatusOK}
+		h.ServeHTTP(rw, r.WithContext(slog.NewContext(r.Context(), rl)))
+
+		fields := slog.Fields{
+			"status":   rw.status,
+			"size":     rw.size,
+			"duration": time.Since(start),
+		}
+		if rw.status >= http.StatusInternalServerError {
+			rl.Err("finished", fields)
+		} else {
+			rl.Info("finished", fields)
+		}
+	})
+}
+
+// Middleware is like Handler, but in the shape used by most
+// routers.
+func Middleware(l slog.Logger) func(http.Handler) http.Handler {
+	return func(h http.Handler) http.Handler {
+		return Handler(l, h)
+	}
+}
+
+// responseWriter records the status and size of a response.
+type responseWriter struct {
+	http.ResponseWriter
+	status      int
+	size        int64
+	wroteHeader bool
+}
+
+func (w *responseWriter) WriteHeader(status int) {
+	if !w.wroteHeader {
+		w.status = status
+		w.wroteHeader = true
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *responseWriter) Write(b []byte) (int, error) {
+	w.wroteHeader = true
+	n, err := w.ResponseWriter.Write(b)
+	w.size += int64(n)
+	return n, err
+}
+
+// Flush flushes the response, if the http.ResponseWriter
+// supports it.
+func (w *responseWriter) Flush() {
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Unwrap gets the http.ResponseWriter, for http.ResponseController.
+func (w *responseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
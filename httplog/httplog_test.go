@@ -0,0 +1,96 @@
+package httplog_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/pat/stop"
+	"github.com/stretchr/slog"
+	"github.com/stretchr/slog/httplog"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandler(t *testing.T) {
+
+	var m sync.Mutex
+	var logs []*slog.Log
+	l := slog.New("server", slog.LevelInfo)
+	defer func() {
+		l.Stop(stop.NoWait)
+		<-l.StopChan()
+	}()
+	l.SetReporterFunc(func(log *slog.Log) {
+		m.Lock()
+		logs = append(logs, log)
+		m.Unlock()
+	})
+
+	h := httplog.Handler(l, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		slog.FromContext(r.Context()).Info("handling")
+		w.WriteHeader(http.StatusTeapot)
+		io.WriteString(w, "short and stout")
+	}))
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest("GET", "/tea", nil))
+	require.NoError(t, l.StopContext(context.Background()))
+
+	require.Equal(t, http.StatusTeapot, rec.Code)
+	id := rec.Header().Get(httplog.CorrelationIDHeader)
+	require.NotEmpty(t, id)
+	require.Equal(t, 3, len(logs))
+	for _, log := range logs {
+		require.Equal(t, slog.Source{"server", "http"}, log.Source)
+		require.Equal(t, id, log.Fields[slog.CorrelationIDKey])
+		require.Equal(t, "GET", log.Fields["method"])
+		require.Equal(t, "/tea", log.Fields["path"])
+	}
+	require.Equal(t, "started", logs[0].Data[1])
+	require.Nil(t, logs[0].Fields[httplog.RequestIDKey])
+	require.Equal(t, "handling", logs[1].Data[1])
+	require.Equal(t, "finished", logs[2].Data[1])
+	require.Equal(t, slog.LevelInfo, logs[2].Level)
+	require.Equal(t, http.StatusTeapot, logs[2].Fields["status"])
+	require.Equal(t, int64(15), logs[2].Fields["size"])
+	require.NotNil(t, logs[2].Fields["duration"])
+
+	// the request loggers don't pile up
+	require.Equal(t, int64(0), l.Stats().Children)
+
+}
+
+func TestMiddlewareRequestID(t *testing.T) {
+
+	var m sync.Mutex
+	var logs []*slog.Log
+	l := slog.New("server", slog.LevelInfo)
+	l.SetReporterFunc(func(log *slog.Log) {
+		m.Lock()
+		logs = append(logs, log)
+		m.Unlock()
+	})
+
+	h := httplog.Middleware(l)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "oops", http.StatusInternalServerError)
+	}))
+
+	req := httptest.NewRequest("POST", "/", nil)
+	req.Header.Set(httplog.RequestIDHeader, "abc123")
+	req.Header.Set(httplog.CorrelationIDHeader, "def456")
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	require.NoError(t, l.StopContext(context.Background()))
+	require.Equal(t, "def456", rec.Header().Get(httplog.CorrelationIDHeader))
+
+	require.Equal(t, 2, len(logs))
+	require.Equal(t, slog.Source{"server", "http"}, logs[1].Source)
+	require.Equal(t, "abc123", logs[1].Fields[httplog.RequestIDKey])
+	require.Equal(t, slog.LevelErr, logs[1].Level)
+	require.Equal(t, "def456", logs[1].Fields[slog.CorrelationIDKey])
+	require.Equal(t, http.StatusInternalServerError, logs[1].Fields["status"])
+
+}
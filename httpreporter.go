@@ -0,0 +1,243 @@
+package slog
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// HTTPOptions represents the options for an HTTPReporter.
+// Zero values get the defaults.
+type HTTPOptions struct {
+	// URL is where the logs are posted.
+	URL string
+	// Header holds headers sent with each request, e.g. auth
+	// tokens.
+	Header http.Header
+	// Gzip is whether request bodies are gzip compressed.
+	Gzip bool
+	// Encode makes the body of a request from a batch of logs,
+	// defaulting to a JSON array of the objects written by
+	// NewJSONReporter. ContentType is the type of the body,
+	// defaulting to "application/json".
+	Encode      func(logs []*Log) ([]byte, error)
+	ContentType string
+	// MaxBatch is the most logs posted in one request, defaulting
+	// to 100.
+	MaxBatch int
+	// MaxDelay is the longest a log waits to be posted, defaulting
+	// to a second.
+	MaxDelay time.Duration
+	// MaxAttempts is the number of times each request is tried
+	// before its logs are dropped, defaulting to 3. Only network
+	// errors, 5xx and 429 responses are retried. The delay
+	// between attempts starts at RetryDelay, defaulting to
+	// 100ms, and doubles each time.
+	MaxAttempts int
+	RetryDelay  time.Duration
+	// QueueSize is the number of batches that may be waiting to
+	// be posted before further ones are dropped, defaulting to 16.
+	QueueSize int
+	// Client is the client used to post logs, defaulting to
+	// one whose requests time out after ten seconds, so Flush
+	// and Close can't wait forever.
+	Client *http.Client
+}
+
+// HTTPReporter is a Reporter that posts batches of logs to an
+// HTTP endpoint.
+// Batches are posted from their own goroutine, so logging never
+// waits for the endpoint.
+type HTTPReporter struct {
+	opts    HTTPOptions
+	b       *Batcher
+	m       sync.RWMutex
+	closed  bool
+	batches chan []*Log
+	stopped chan struct{}
+	dropped uint64
+	pm      sync.Mutex
+	posted  *sync.Cond // broadcast when a batch has been posted
+	pending int        // number of batches queued but not posted
+}
+
+var _ Reporter = (*HTTPReporter)(nil)
+var _ io.Closer = (*HTTPReporter)(nil)
+
+// NewHTTPReporter makes an HTTPReporter that posts logs as the
+// options say.
+func NewHTTPReporter(opts HTTPOptions) *HTTPReporter {
+	if opts.MaxBatch == 0 {
+		opts.MaxBatch = 100
+	}
+	if opts.MaxDelay == 0 {
+		opts.MaxDelay = time.Second
+	}
+	if opts.MaxAttempts == 0 {
+		opts.MaxAttempts = 3
+	}
+	if opts.RetryDelay == 0 {
+		opts.RetryDelay = 100 * time.Millisecond
+	}
+	if opts.QueueSize == 0 {
+		opts.QueueSize = 16
+	}
+	if opts.Client == nil {
+		opts.Client = &http.Client{Timeout: 10 * time.Second}
+	}
+	if opts.Encode == nil {
+		opts.Encode = encodeJSON
+	}
+	if opts.ContentType == "" {
+		opts.ContentType = "application/json"
+	}
+	h := &HTTPReporter{
+		opts:    opts,
+		batches: make(chan []*Log, opts.QueueSize),
+		stopped: make(chan struct{}),
+	}
+	h.posted = sync.NewCond(&h.pm)
+	h.b = Batch(BatchReporterFunc(h.queue), opts.MaxBatch, opts.MaxDelay)
+	go h.run()
+	return h
+}
+
+// Log adds the log to the batch to be posted.
+func (h *HTTPReporter) Log(l *Log) {
+	h.b.Log(l)
+}
+
+// Dropped gets the number of logs dropped, because the queue was
+// full or they couldn't be posted.
+func (h *HTTPReporter) Dropped() uint64 {
+	return atomic.LoadUint64(&h.dropped)
+}
+
+// Flush posts the logs in the batch now, and waits for the
+// batches queued so far to be posted.
+func (h *HTTPReporter) Flush() {
+	h.b.Flush()
+	h.pm.Lock()
+	for h.pending > 0 {
+		h.posted.Wait()
+	}
+	h.pm.Unlock()
+}
+
+// Close posts the remaining logs, and stops the HTTPReporter.
+func (h *HTTPReporter) Close() error {
+	h.b.Close()
+	h.m.Lock()
+	if !h.closed {
+		h.closed = true
+		close(h.batches)
+	}
+	h.m.Unlock()
+	<-h.stopped
+	return nil
+}
+
+func (h *HTTPReporter) queue(logs []*Log) {
+	h.m.RLock()
+	defer h.m.RUnlock()
+	if h.closed {
+		atomic.AddUint64(&h.dropped, uint64(len(logs)))
+		return
+	}
+	h.pm.Lock()
+	h.pending++
+	h.pm.Unlock()
+	select {
+	case h.batches <- logs:
+	default:
+		atomic.AddUint64(&h.dropped, uint64(len(logs)))
+		h.donePosting()
+	}
+}
+
+func (h *HTTPReporter) run() {
+	defer close(h.stopped)
+	for logs := range h.batches {
+		if err := h.post(logs); err != nil {
+			atomic.AddUint64(&h.dropped, uint64(len(logs)))
+		}
+		h.donePosting()
+	}
+}
+
+// donePosting records that a queued batch has been posted, or
+// dropped.
+func (h *HTTPReporter) donePosting() {
+	h.pm.Lock()
+	h.pending--
+	h.posted.Broadcast()
+	h.pm.Unlock()
+}
+
+// post posts the logs, retrying as the options say.
+func (h *HTTPReporter) post(logs []*Log) error {
+	body, err := h.opts.Encode(logs)
+	if err != nil {
+		return err
+	}
+	if h.opts.Gzip {
+		var buf bytes.Buffer
+		zw := gzip.NewWriter(&buf)
+		zw.Write(body)
+		if err := zw.Close(); err != nil {
+			return err
+		}
+		body = buf.Bytes()
+	}
+	delay := h.opts.RetryDelay
+	for attempt := 1; ; attempt++ {
+		retry, err := h.send(body)
+		if !retry || attempt == h.opts.MaxAttempts {
+			return err
+		}
+		time.Sleep(delay)
+		delay *= 2
+	}
+}
+
+// encodeJSON encodes the logs as a JSON array.
+func encodeJSON(logs []*Log) ([]byte, error) {
+	items := make([]*jsonLog, len(logs))
+	for i, l := range logs {
+		items[i] = newJSONLog(l, TimeFormat{})
+	}
+	return json.Marshal(items)
+}
+
+// send posts the body, getting whether it is worth retrying if
+// it fails.
+func (h *HTTPReporter) send(body []byte) (retry bool, err error) {
+	req, err := http.NewRequest("POST", h.opts.URL, bytes.NewReader(body))
+	if err != nil {
+		return false, err
+	}
+	for k, vs := range h.opts.Header {
+		req.Header[k] = vs
+	}
+	req.Header.Set("Content-Type", h.opts.ContentType)
+	if h.opts.Gzip {
+		req.Header.Set("Content-Encoding", "gzip")
+	}
+	res, err := h.opts.Client.Do(req)
+	if err != nil {
+		return true, err
+	}
+	io.Copy(io.Discard, res.Body)
+	res.Body.Close()
+	if res.StatusCode >= 300 {
+		retry := res.StatusCode >= 500 || res.StatusCode == http.StatusTooManyRequests
+		return retry, fmt.Errorf("slog: %s posting logs", res.Status)
+	}
+	return false, nil
+}
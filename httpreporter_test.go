@@ -0,0 +1,110 @@
+package slog_test
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/slog"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHTTPReporter(t *testing.T) {
+
+	var m sync.Mutex
+	var requests int
+	var batches [][]map[string]interface{}
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		m.Lock()
+		defer m.Unlock()
+		requests++
+		if requests == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		if r.Header.Get("Authorization") != "Bearer abc" || r.Header.Get("Content-Encoding") != "gzip" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		zr, err := gzip.NewReader(r.Body)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		body, _ := io.ReadAll(zr)
+		var batch []map[string]interface{}
+		if json.Unmarshal(body, &batch) != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		batches = append(batches, batch)
+	}))
+	defer s.Close()
+
+	r := slog.NewHTTPReporter(slog.HTTPOptions{
+		URL:        s.URL,
+		Header:     http.Header{"Authorization": {"Bearer abc"}},
+		Gzip:       true,
+		MaxBatch:   2,
+		MaxDelay:   time.Hour,
+		RetryDelay: time.Millisecond,
+	})
+	when := time.Date(2015, 1, 2, 3, 4, 5, 0, time.UTC)
+	for _, msg := range []string{"one", "two", "three"} {
+		r.Log(&slog.Log{Level: slog.LevelInfo, When: when, Source: []string{"parent"}, Data: []interface{}{msg}})
+	}
+	require.NoError(t, r.Close())
+
+	require.Equal(t, 3, requests)
+	require.Equal(t, 2, len(batches))
+	require.Equal(t, 2, len(batches[0]))
+	require.Equal(t, "one", batches[0][0]["data"].([]interface{})[0])
+	require.Equal(t, "three", batches[1][0]["data"].([]interface{})[0])
+	require.Equal(t, uint64(0), r.Dropped())
+
+}
+
+func TestHTTPReporterRejected(t *testing.T) {
+
+	var requests int
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer s.Close()
+
+	r := slog.NewHTTPReporter(slog.HTTPOptions{URL: s.URL, RetryDelay: time.Millisecond})
+	r.Log(&slog.Log{})
+	require.NoError(t, r.Close())
+
+	require.Equal(t, 1, requests)
+	require.Equal(t, uint64(1), r.Dropped())
+
+}
+
+func TestHTTPReporterFlush(t *testing.T) {
+
+	var m sync.Mutex
+	var posted int
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		m.Lock()
+		posted++
+		m.Unlock()
+	}))
+	defer s.Close()
+
+	r := slog.NewHTTPReporter(slog.HTTPOptions{URL: s.URL, MaxDelay: time.Hour})
+	defer r.Close()
+	r.Log(&slog.Log{Level: slog.LevelInfo, Source: []string{"parent"}, Data: []interface{}{"one"}})
+	r.Flush()
+
+	m.Lock()
+	defer m.Unlock()
+	require.Equal(t, 1, posted)
+
+}
@@ -0,0 +1,159 @@
+package slog
+
+import (
+	"io"
+	"sync"
+	"sync/atomic"
+)
+
+// IsolatedQueueSize is the number of logs each member of an
+// IsolatedReporter will queue before dropping logs.
+const IsolatedQueueSize = 1024
+
+// IsolatedReporter is a Reporter that reports to multiple
+// reporters, each with its own queue and goroutine, so that
+// a slow or panicking reporter doesn't affect the others.
+// Each reporter still gets its logs in order.
+type IsolatedReporter struct {
+	m       sync.RWMutex
+	closed  bool
+	sinks   []*isolatedSink
+	stopped sync.WaitGroup
+}
+
+type isolatedSink struct {
+	panics uint64 // first, to be aligned for atomic use
+	r      Reporter
+	q      *queue
+}
+
+var _ Reporter = (*IsolatedReporter)(nil)
+var _ io.Closer = (*IsolatedReporter)(nil)
+
+// IsolatedReporters makes an IsolatedReporter that reports to
+// each of the reporters.
+// If a reporter falls more than IsolatedQueueSize logs behind,
+// further logs to it are dropped until it catches up.
+func IsolatedReporters(rs ...Reporter) *IsolatedReporter {
+	sinks := make([]Sink, len(rs))
+	for n, r := range rs {
+		sinks[n] = Sink{Reporter: r, Policy: DropNewest}
+	}
+	return Tee(sinks...)
+}
+
+// Sink represents a reporter given to Tee, and how its logs are
+// queued.
+type Sink struct {
+	Reporter Reporter
+	// Size is the number of logs that may be queued for the
+	// reporter, defaulting to IsolatedQueueSize.
+	Size int
+	// Policy is what happens to logs while the queue is full.
+	Policy DropPolicy
+}
+
+// Tee makes an IsolatedReporter that reports to each of the
+// sinks, with its own queue of the size and drop policy of the
+// sink, so a slow network sink can't delay the others.
+func Tee(sinks ...Sink) *IsolatedReporter {
+	i := &IsolatedReporter{}
+	for _, sink := range sinks {
+		size := sink.Size
+		if size == 0 {
+			size = IsolatedQueueSize
+		}
+		s := &isolatedSink{r: sink.Reporter, q: newQueue()}
+		s.q.setSize(size, sink.Policy)
+		i.sinks = append(i.sinks, s)
+		i.stopped.Add(1)
+		go func() {
+			defer i.stopped.Done()
+			for {
+				l, ok := s.q.take()
+				if !ok {
+					return
+				}
+				s.report(l)
+				s.q.finish()
+			}
+		}()
+	}
+	return i
+}
+
+// report reports the log, counting it as dropped if the
+// Reporter panics.
+func (s *isolatedSink) report(l *Log) {
+	defer func() {
+		if recover() != nil {
+			atomic.AddUint64(&s.panics, 1)
+		}
+	}()
+	s.r.Log(l)
+}
+
+// Log queues the log for each of the reporters.
+// Logs are dropped once the IsolatedReporter is closed.
+func (i *IsolatedReporter) Log(l *Log) {
+	i.m.RLock()
+	defer i.m.RUnlock()
+	if i.closed {
+		return
+	}
+	for _, s := range i.sinks {
+		s.q.put(l)
+	}
+}
+
+// Dropped gets the number of logs dropped by each reporter,
+// in the order they were given to IsolatedReporters or Tee.
+// Logs that a reporter panics on are also counted as dropped.
+func (i *IsolatedReporter) Dropped() []uint64 {
+	dropped := make([]uint64, len(i.sinks))
+	for n, s := range i.sinks {
+		dropped[n] = s.q.droppedCount() + atomic.LoadUint64(&s.panics)
+	}
+	return dropped
+}
+
+// Flush waits for each reporter to report the logs queued so far,
+// and then flushes each of the reporters that is a Flusher.
+func (i *IsolatedReporter) Flush() {
+	for _, s := range i.sinks {
+		s.q.flush()
+	}
+	i.reporters().Flush()
+}
+
+// Start starts each of the reporters that is a Starter.
+func (i *IsolatedReporter) Start() {
+	i.reporters().Start()
+}
+
+// reporters gets the reporters of the sinks.
+func (i *IsolatedReporter) reporters() reporters {
+	rs := make(reporters, len(i.sinks))
+	for n, s := range i.sinks {
+		rs[n] = s.r
+	}
+	return rs
+}
+
+// Close waits for each reporter to report its queued logs,
+// and then closes each of the reporters that is an io.Closer,
+// returning the first error.
+func (i *IsolatedReporter) Close() error {
+	i.m.Lock()
+	if i.closed {
+		i.m.Unlock()
+		return nil
+	}
+	i.closed = true
+	for _, s := range i.sinks {
+		s.q.close()
+	}
+	i.m.Unlock()
+	i.stopped.Wait()
+	return i.reporters().Close()
+}
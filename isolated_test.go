@@ -0,0 +1,127 @@
+package slog_test
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/slog"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsolatedReporters(t *testing.T) {
+
+	block := make(chan struct{})
+	blocked := slog.ReporterFunc(func(*slog.Log) {
+		<-block
+	})
+	var m sync.Mutex
+	var logs []*slog.Log
+	received := make(chan struct{}, 10)
+	r := slog.ReporterFunc(func(l *slog.Log) {
+		m.Lock()
+		logs = append(logs, l)
+		m.Unlock()
+		received <- struct{}{}
+	})
+
+	i := slog.IsolatedReporters(blocked, r)
+	l1, l2 := &slog.Log{}, &slog.Log{}
+	i.Log(l1)
+	i.Log(l2)
+
+	for n := 0; n < 2; n++ {
+		select {
+		case <-received:
+		case <-time.After(time.Second):
+			require.Fail(t, "blocked reporter delayed the other")
+		}
+	}
+
+	close(block)
+	require.NoError(t, i.Close())
+	require.Equal(t, []*slog.Log{l1, l2}, logs)
+	require.Equal(t, []uint64{0, 0}, i.Dropped())
+
+	// logs after Close are ignored
+	i.Log(&slog.Log{})
+
+}
+
+func TestIsolatedReportersDropped(t *testing.T) {
+
+	block := make(chan struct{})
+	blocked := slog.ReporterFunc(func(*slog.Log) {
+		<-block
+	})
+	panicky := slog.ReporterFunc(func(*slog.Log) {
+		panic("oops")
+	})
+
+	i := slog.IsolatedReporters(blocked, panicky)
+	for n := 0; n < slog.IsolatedQueueSize+10; n++ {
+		i.Log(&slog.Log{})
+	}
+	close(block)
+	require.NoError(t, i.Close())
+
+	dropped := i.Dropped()
+	// the first log is taken off the queue before it fills up
+	require.True(t, dropped[0] == 9 || dropped[0] == 10)
+	require.Equal(t, uint64(slog.IsolatedQueueSize+10), dropped[1])
+
+}
+
+func TestTee(t *testing.T) {
+
+	block := make(chan struct{})
+	slow := NewTestReporter()
+	slow.logFunc = func(*slog.Log) {
+		<-block
+	}
+	fast := NewTestReporter()
+	var mu sync.Mutex
+	var got []string
+	fast.logFunc = func(l *slog.Log) {
+		mu.Lock()
+		got = append(got, l.Data[0].(string))
+		mu.Unlock()
+	}
+
+	r := slog.Tee(
+		slog.Sink{Reporter: slow, Size: 1, Policy: slog.DropOldest},
+		slog.Sink{Reporter: fast, Size: 10, Policy: slog.BlockWhenFull},
+	)
+	for _, msg := range []string{"one", "two", "three", "four"} {
+		r.Log(&slog.Log{Data: []interface{}{msg}})
+	}
+	require.Eventually(t, func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return len(got) == 4
+	}, time.Second, time.Millisecond)
+
+	close(block)
+	require.NoError(t, r.Close())
+	require.Equal(t, []string{"one", "two", "three", "four"}, got)
+	dropped := r.Dropped()
+	require.Equal(t, uint64(0), dropped[1])
+	require.True(t, dropped[0] >= 2)
+
+}
+
+func TestTeeFlush(t *testing.T) {
+
+	a, b := &lifecycleReporter{}, &lifecycleReporter{}
+	r := slog.Tee(slog.Sink{Reporter: a}, slog.Sink{Reporter: b})
+	r.Start()
+	r.Log(&slog.Log{Data: []interface{}{"one"}})
+	r.Log(&slog.Log{Data: []interface{}{"two"}})
+	r.Flush()
+
+	// the logs are reported before the reporters are flushed
+	require.Equal(t, []string{"start", "log", "log", "flush"}, a.calls)
+	require.Equal(t, []string{"start", "log", "log", "flush"}, b.calls)
+	require.NoError(t, r.Close())
+
+}
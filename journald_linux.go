@@ -0,0 +1,164 @@
+package slog
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// journalSocket is the socket of the systemd journal.
+var journalSocket = "/run/systemd/journal/socket"
+
+// journalPriorities maps levels to the syslog priorities used by
+// the journal.
+var journalPriorities = map[Level]int{
+	LevelErr:   3,
+	LevelWarn:  4,
+	LevelInfo:  6,
+	LevelDebug: 7,
+	LevelTrace: 7,
+}
+
+// journalReserved are the journal fields the JournaldReporter sets
+// itself, or that the journal gives a meaning to, which Fields
+// aren't sent as.
+var journalReserved = map[string]bool{
+	"MESSAGE":           true,
+	"PRIORITY":          true,
+	"SYSLOG_IDENTIFIER": true,
+	"SYSLOG_FACILITY":   true,
+	"SYSLOG_PID":        true,
+	"SYSLOG_TIMESTAMP":  true,
+	"SLOG_SOURCE":       true,
+	"CODE_FILE":         true,
+	"CODE_LINE":         true,
+	"CODE_FUNC":         true,
+	"ERRNO":             true,
+	"ERROR":             true,
+	"STACK":             true,
+}
+
+// JournaldReporter is a Reporter that sends logs to the systemd
+// journal with its native protocol, so the source, caller, error
+// and Fields of each log are kept as journal fields.
+// It is only available on Linux.
+type JournaldReporter struct {
+	m    sync.Mutex
+	conn net.Conn
+}
+
+var _ ErrReporter = (*JournaldReporter)(nil)
+var _ io.Closer = (*JournaldReporter)(nil)
+
+// NewJournaldReporter makes a JournaldReporter and connects it to
+// the journal.
+// The first part of the source of each log is its
+// SYSLOG_IDENTIFIER, and the whole source is SLOG_SOURCE.
+// Fields are sent with their keys in upper case, and characters
+// the journal doesn't allow replaced with "_". Leading underscores
+// are dropped, as those fields are set by the journal, and keys
+// like MESSAGE or PRIORITY, or the other fields set by the
+// reporter, get a "SLOG_" prefix.
+func NewJournaldReporter() (*JournaldReporter, error) {
+	conn, err := net.Dial("unixgram", journalSocket)
+	if err != nil {
+		return nil, err
+	}
+	return &JournaldReporter{conn: conn}, nil
+}
+
+// Log sends the log to the journal.
+func (j *JournaldReporter) Log(l *Log) {
+	j.Report(l)
+}
+
+// Report sends the log to the journal, returning an error if it
+// couldn't be sent.
+// Logs bigger than the socket allows can't be sent.
+func (j *JournaldReporter) Report(l *Log) error {
+	priority, ok := journalPriorities[l.Level]
+	if !ok {
+		priority = journalPriorities[LevelInfo]
+	}
+	var buf bytes.Buffer
+	writeJournalField(&buf, "MESSAGE", sprint(l.text()))
+	writeJournalField(&buf, "PRIORITY", strconv.Itoa(priority))
+	if len(l.Source) > 0 {
+		writeJournalField(&buf, "SYSLOG_IDENTIFIER", l.Source[0])
+	}
+	writeJournalField(&buf, "SLOG_SOURCE", l.SourceString())
+	if l.Caller != nil {
+		writeJournalField(&buf, "CODE_FILE", l.Caller.File)
+		writeJournalField(&buf, "CODE_LINE", strconv.Itoa(l.Caller.Line))
+		writeJournalField(&buf, "CODE_FUNC", l.Caller.Function)
+	}
+	if l.Err != nil {
+		writeJournalField(&buf, "ERROR", l.Err.Error())
+	}
+	if l.Stack != "" {
+		writeJournalField(&buf, "STACK", l.Stack)
+	}
+	for _, k := range l.Fields.keys() {
+		writeJournalField(&buf, journalKey(k), fmt.Sprint(l.Fields[k]))
+	}
+	j.m.Lock()
+	defer j.m.Unlock()
+	if j.conn == nil {
+		return net.ErrClosed
+	}
+	_, err := j.conn.Write(buf.Bytes())
+	return err
+}
+
+// Close closes the connection to the journal.
+func (j *JournaldReporter) Close() error {
+	j.m.Lock()
+	defer j.m.Unlock()
+	if j.conn == nil {
+		return nil
+	}
+	err := j.conn.Close()
+	j.conn = nil
+	return err
+}
+
+// writeJournalField writes the field in the journal's native
+// protocol, using the binary form for values with newlines.
+func writeJournalField(buf *bytes.Buffer, key, value string) {
+	buf.WriteString(key)
+	if !strings.Contains(value, "\n") {
+		buf.WriteByte('=')
+		buf.WriteString(value)
+		buf.WriteByte('\n')
+		return
+	}
+	buf.WriteByte('\n')
+	binary.Write(buf, binary.LittleEndian, uint64(len(value)))
+	buf.WriteString(value)
+	buf.WriteByte('\n')
+}
+
+// journalKey makes k a valid journal field name, which is upper
+// case letters, digits and underscores, not starting with an
+// underscore or digit, and not one of journalReserved.
+func journalKey(k string) string {
+	b := []byte(strings.ToUpper(k))
+	for i, c := range b {
+		if !(c >= 'A' && c <= 'Z' || c >= '0' && c <= '9') {
+			b[i] = '_'
+		}
+	}
+	key := strings.TrimLeft(string(b), "_0123456789")
+	if key == "" {
+		return "FIELD"
+	}
+	if journalReserved[key] {
+		return "SLOG_" + key
+	}
+	return key
+}
@@ -0,0 +1,57 @@
+package slog_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"net"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/slog"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJournaldReporter(t *testing.T) {
+
+	path := filepath.Join(t.TempDir(), "journal.socket")
+	pc, err := net.ListenPacket("unixgram", path)
+	require.NoError(t, err)
+	defer pc.Close()
+	defer slog.SetJournalSocket(path)()
+
+	r, err := slog.NewJournaldReporter()
+	require.NoError(t, err)
+	defer r.Close()
+
+	require.NoError(t, r.Report(&slog.Log{
+		Level:  slog.LevelErr,
+		Source: []string{"parent", "child"},
+		Data:   []interface{}{"( main.go:12 )", "failed"},
+		Fields: slog.Fields{"request-id": "abc", "message": "mine", "_pid": 1},
+		Err:    errors.New("disk full"),
+		Stack:  "main.main()\n\t/src/main.go:12\n",
+	}))
+	buf := make([]byte, 65536)
+	n, _, err := pc.ReadFrom(buf)
+	require.NoError(t, err)
+
+	stack := "main.main()\n\t/src/main.go:12\n"
+	var want bytes.Buffer
+	want.WriteString("MESSAGE=( main.go:12 ) failed disk full _pid=1 message=mine request-id=abc\n")
+	want.WriteString("PRIORITY=3\n")
+	want.WriteString("SYSLOG_IDENTIFIER=parent\n")
+	want.WriteString("SLOG_SOURCE=parent>child\n")
+	want.WriteString("ERROR=disk full\n")
+	want.WriteString("STACK\n")
+	binary.Write(&want, binary.LittleEndian, uint64(len(stack)))
+	want.WriteString(stack + "\n")
+	want.WriteString("PID=1\n")
+	want.WriteString("SLOG_MESSAGE=mine\n")
+	want.WriteString("REQUEST_ID=abc\n")
+	require.Equal(t, want.String(), string(buf[:n]))
+
+	require.NoError(t, r.Close())
+	require.Error(t, r.Report(&slog.Log{}))
+
+}
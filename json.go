@@ -0,0 +1,110 @@
+package slog
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+)
+
+type jsonLog struct {
+	Level       string                 `json:"level"`
+	Time        string                 `json:"time"`
+	Source      string                 `json:"source"`
+	Location    string                 `json:"location,omitempty"`
+	Data        []interface{}          `json:"data,omitempty"`
+	Fields      map[string]interface{} `json:"fields,omitempty"`
+	Caller      *Caller                `json:"caller,omitempty"`
+	Error       *ErrorInfo             `json:"error,omitempty"`
+	Stack       string                 `json:"stack,omitempty"`
+	Fingerprint string                 `json:"fingerprint,omitempty"`
+}
+
+// NewJSONReporter gets a Reporter that writes each log to w
+// as a JSON object on its own line, with the Data of the log,
+// without its Location, as "data", and the Location as "location".
+func NewJSONReporter(w io.Writer) Reporter {
+	return NewWriterReporter(w, JSONFormatter)
+}
+
+// JSONFormatter formats logs as JSON objects on their own line.
+// It is an ErrFormatter, failing if the log can't be marshaled.
+var JSONFormatter = NewJSONFormatter(TimeFormat{})
+
+// NewJSONFormatter makes a Formatter like JSONFormatter that
+// writes times as tf says, RFC 3339 by default.
+func NewJSONFormatter(tf TimeFormat) Formatter {
+	return jsonFormatter{tf: tf}
+}
+
+type jsonFormatter struct {
+	tf TimeFormat
+}
+
+func (f jsonFormatter) Format(l *Log) []byte {
+	b, _ := f.FormatErr(l)
+	return b
+}
+
+func (f jsonFormatter) FormatErr(l *Log) ([]byte, error) {
+	b, err := json.Marshal(newJSONLog(l, f.tf))
+	if err != nil {
+		return nil, fmt.Errorf("slog: marshaling log: %w", err)
+	}
+	return append(b, '\n'), nil
+}
+
+// newJSONLog makes the JSON representation of the log, with
+// the time as tf says.
+func newJSONLog(l *Log, tf TimeFormat) *jsonLog {
+	item := &jsonLog{
+		Level:       l.Level.String(),
+		Time:        tf.format(l.When, time.RFC3339Nano),
+		Source:      l.SourceString(),
+		Location:    l.Location(),
+		Caller:      l.Caller,
+		Error:       NewErrorInfo(l.Err),
+		Stack:       l.Stack,
+		Fingerprint: l.Fingerprint,
+	}
+	for _, d := range l.message() {
+		item.Data = append(item.Data, jsonValue(d))
+	}
+	if len(l.Fields) > 0 {
+		item.Fields = make(map[string]interface{}, len(l.Fields))
+		for k, v := range l.Fields {
+			item.Fields[k] = jsonValue(v)
+		}
+	}
+	return item
+}
+
+// jsonValue gets a value that will marshal sensibly, with structs
+// and maps expanded into objects, and values that don't marshal
+// written with fmt.
+func jsonValue(v interface{}) interface{} {
+	return expand(v, jsonLeaf)
+}
+
+// jsonLeaf gets a value that will marshal sensibly, falling back
+// to the fmt representation of v.
+func jsonLeaf(v interface{}) interface{} {
+	switch v := v.(type) {
+	case nil, string, bool, int, int8, int16, int32, int64,
+		uint, uint8, uint16, uint32, uint64, float32, float64:
+		return v
+	case error:
+		return v.Error()
+	case json.Marshaler:
+		if _, err := json.Marshal(v); err == nil {
+			return v
+		}
+	case fmt.Stringer:
+		return v.String()
+	default:
+		if _, err := json.Marshal(v); err == nil {
+			return v
+		}
+	}
+	return fmt.Sprint(v)
+}
@@ -0,0 +1,81 @@
+package slog_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/slog"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJSONReporter(t *testing.T) {
+
+	var buf bytes.Buffer
+	r := slog.NewJSONReporter(&buf)
+	when := time.Date(2015, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	r.Log(&slog.Log{
+		Level:  slog.LevelErr,
+		When:   when,
+		Source: []string{"parent", "child"},
+		Data:   []interface{}{"failed:", errors.New("oops"), 1},
+		Fields: slog.Fields{"status": 500, "c": complex(1, 2)},
+	})
+	r.Log(&slog.Log{Level: slog.LevelInfo, When: when, Source: []string{"parent"}})
+
+	lines := bytes.Split(bytes.TrimSpace(buf.Bytes()), []byte("\n"))
+	require.Equal(t, 2, len(lines))
+	require.JSONEq(t, `{
+		"level": "error",
+		"time": "2015-01-02T03:04:05Z",
+		"source": "parent>child",
+		"data": ["failed:", "oops", 1],
+		"fields": {"status": 500, "c": "(1+2i)"}
+	}`, string(lines[0]))
+	require.JSONEq(t, `{"level":"info","time":"2015-01-02T03:04:05Z","source":"parent"}`, string(lines[1]))
+
+}
+
+func TestJSONReporterLogger(t *testing.T) {
+
+	var buf bytes.Buffer
+	l := slog.New("parent", slog.LevelInfo)
+	l.SetSync(true)
+	l.SetReporter(slog.NewJSONReporter(&buf))
+	l.Info("hello", 1)
+
+	var item struct {
+		Location string        `json:"location"`
+		Data     []interface{} `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &item))
+	require.True(t, strings.HasPrefix(item.Location, "json_test.go:"), item.Location)
+	require.Equal(t, []interface{}{"hello", 1.0}, item.Data)
+
+}
+
+func TestJSONReporterCaller(t *testing.T) {
+
+	var buf bytes.Buffer
+	r := slog.NewJSONReporter(&buf)
+	when := time.Date(2015, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	r.Log(&slog.Log{
+		Level:  slog.LevelInfo,
+		When:   when,
+		Source: []string{"parent"},
+		Caller: &slog.Caller{File: "/src/main.go", Line: 12, Function: "main.main"},
+	})
+
+	require.JSONEq(t, `{
+		"level": "info",
+		"time": "2015-01-02T03:04:05Z",
+		"source": "parent",
+		"caller": {"file": "/src/main.go", "line": 12, "function": "main.main"}
+	}`, buf.String())
+
+}
@@ -0,0 +1,72 @@
+package slog
+
+import "fmt"
+
+// lazyValue is a value made by Lazy.
+type lazyValue func() interface{}
+
+// Lazy gets a value for the Data or Fields of a log that is only
+// worked out by calling f if the log is reported, for values that
+// are expensive to make:
+//
+//	l.Debug("plan", slog.Lazy(func() interface{} { return explain(query) }))
+//
+// f is called in the goroutine that reports logs, before the
+// hooks are run, so it must be safe to call from there.
+// Lazy values in Fields given to WithFields are worked out again
+// for each log.
+func Lazy(f func() interface{}) interface{} {
+	return lazyValue(f)
+}
+
+// String works out the value, for when a Reporter is given a log
+// with a Lazy value in it directly.
+func (v lazyValue) String() string {
+	return fmt.Sprint(v())
+}
+
+// resolvable gets whether v is a Lazy value or a LogValuer.
+func resolvable(v interface{}) bool {
+	switch v.(type) {
+	case lazyValue, LogValuer:
+		return true
+	}
+	return false
+}
+
+// resolveLazy gets the log with its Lazy values worked out, and
+// LogValue called on its LogValuers, copying it if it has any.
+func resolveLazy(l *Log) *Log {
+	var data []interface{}
+	for i, d := range l.Data {
+		if resolvable(d) {
+			if data == nil {
+				data = append([]interface{}(nil), l.Data...)
+			}
+			data[i] = logValue(d)
+		}
+	}
+	var fields Fields
+	for k, f := range l.Fields {
+		if resolvable(f) {
+			if fields == nil {
+				fields = make(Fields, len(l.Fields))
+				for k, f := range l.Fields {
+					fields[k] = f
+				}
+			}
+			fields[k] = logValue(f)
+		}
+	}
+	if data == nil && fields == nil {
+		return l
+	}
+	resolved := *l
+	if data != nil {
+		resolved.Data = data
+	}
+	if fields != nil {
+		resolved.Fields = fields
+	}
+	return &resolved
+}
@@ -0,0 +1,38 @@
+package slog_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/slog"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLazy(t *testing.T) {
+
+	l := slog.New("parent", slog.LevelInfo)
+	r := NewTestReporter()
+	l.SetReporter(r)
+
+	calls := 0
+	expensive := slog.Lazy(func() interface{} {
+		calls++
+		return calls
+	})
+	l.Debug("skipped", expensive)
+	require.Equal(t, 0, calls)
+
+	child := l.With("n", expensive)
+	child.Info("first", expensive)
+	child.Info("second")
+	require.NoError(t, l.StopContext(context.Background()))
+
+	require.Equal(t, 3, calls)
+	require.Equal(t, 2, len(r.logs))
+	require.Equal(t, 1, r.logs[0].Data[2])
+	require.Equal(t, 2, r.logs[0].Fields["n"])
+	require.Equal(t, 3, r.logs[1].Fields["n"])
+	require.Equal(t, "4", slog.Lazy(func() interface{} { return 4 }).(fmt.Stringer).String())
+
+}
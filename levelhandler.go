@@ -0,0 +1,85 @@
+package slog
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+// levelState is the JSON body of a LevelHandler.
+type levelState struct {
+	Level   string            `json:"level,omitempty"`
+	Sources map[string]string `json:"sources,omitempty"`
+}
+
+type levelHandler struct {
+	l RootLogger
+}
+
+// LevelHandler gets an http.Handler for changing the levels of l
+// while it runs, e.g. from an admin mux.
+// GET gets the level and the source levels as JSON:
+//
+//	{"level":"info","sources":{"parent>http":"error"}}
+//
+// PUT sets any levels in a body of the same shape, and an empty
+// source level removes it, then responds like GET.
+func LevelHandler(l RootLogger) http.Handler {
+	return &levelHandler{l: l}
+}
+
+func (h *levelHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case "GET", "HEAD":
+	case "PUT":
+		var state levelState
+		if err := json.NewDecoder(r.Body).Decode(&state); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if err := h.set(state); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	default:
+		w.Header().Set("Allow", "GET, HEAD, PUT")
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+	state := levelState{Level: h.l.Level().String()}
+	if levels := h.l.SourceLevels(); len(levels) > 0 {
+		state.Sources = make(map[string]string, len(levels))
+		for source, level := range levels {
+			state.Sources[source] = level.String()
+		}
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(state)
+}
+
+// set sets the levels in state, checking them all first so none
+// are set if any are invalid.
+func (h *levelHandler) set(state levelState) error {
+	var level Level
+	if state.Level != "" {
+		if level = ParseLevel(state.Level); level == LevelInvalid {
+			return unknownLevel(state.Level)
+		}
+	}
+	sources := make(map[string]Level, len(state.Sources))
+	for source, s := range state.Sources {
+		if s == "" {
+			sources[source] = LevelInvalid
+			continue
+		}
+		if sources[source] = ParseLevel(s); sources[source] == LevelInvalid {
+			return unknownLevel(s)
+		}
+	}
+	if level != LevelInvalid {
+		h.l.SetLevel(level)
+	}
+	for source, level := range sources {
+		h.l.SetSourceLevel(source, level)
+	}
+	return nil
+}
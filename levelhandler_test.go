@@ -0,0 +1,50 @@
+package slog_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/pat/stop"
+	"github.com/stretchr/slog"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLevelHandler(t *testing.T) {
+
+	l := slog.New("parent", slog.LevelInfo)
+	defer func() {
+		l.Stop(stop.NoWait)
+		<-l.StopChan()
+	}()
+	h := slog.LevelHandler(l)
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest("GET", "/", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+	require.JSONEq(t, `{"level":"info"}`, w.Body.String())
+
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest("PUT", "/", strings.NewReader(`{"level":"debug","sources":{"parent>http":"error","parent>db":"warn"}}`)))
+	require.Equal(t, http.StatusOK, w.Code)
+	require.JSONEq(t, `{"level":"debug","sources":{"parent>http":"error","parent>db":"warning"}}`, w.Body.String())
+	require.Equal(t, slog.LevelDebug, l.Level())
+	require.Equal(t, map[string]slog.Level{"parent>http": slog.LevelErr, "parent>db": slog.LevelWarn}, l.SourceLevels())
+
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest("PUT", "/", strings.NewReader(`{"sources":{"parent>http":""}}`)))
+	require.Equal(t, http.StatusOK, w.Code)
+	require.JSONEq(t, `{"level":"debug","sources":{"parent>db":"warning"}}`, w.Body.String())
+
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest("PUT", "/", strings.NewReader(`{"level":"info","sources":{"parent>db":"loud"}}`)))
+	require.Equal(t, http.StatusBadRequest, w.Code)
+	require.Equal(t, slog.LevelDebug, l.Level())
+
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest("POST", "/", nil))
+	require.Equal(t, http.StatusMethodNotAllowed, w.Code)
+	require.Equal(t, "GET, HEAD, PUT", w.Header().Get("Allow"))
+
+}
@@ -0,0 +1,42 @@
+package slog
+
+// Flusher represents reporters that hold logs back, such as to
+// send them in batches, and can be told to report them now.
+// The RootLogger flushes its Reporters when it stops, before
+// closing those that are an io.Closer.
+type Flusher interface {
+	// Flush reports the logs held back so far.
+	Flush()
+}
+
+// Starter represents reporters that need to start, such as to
+// connect or start goroutines, before they are given logs.
+// The RootLogger starts each Reporter when it is set, so Start
+// may be called more than once if it is set again.
+type Starter interface {
+	// Start starts the reporter.
+	Start()
+}
+
+// Flush flushes each of the reporters that is a Flusher.
+func (rs reporters) Flush() {
+	for _, r := range rs {
+		if f, ok := r.(Flusher); ok {
+			f.Flush()
+		}
+	}
+}
+
+// Start starts each of the reporters that is a Starter.
+func (rs reporters) Start() {
+	for _, r := range rs {
+		if s, ok := r.(Starter); ok {
+			s.Start()
+		}
+	}
+}
+
+// startReporter starts r if it is a Starter.
+func startReporter(r Reporter) {
+	reporters{r}.Start()
+}
@@ -0,0 +1,86 @@
+package slog_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/slog"
+	"github.com/stretchr/testify/require"
+)
+
+// lifecycleReporter records the lifecycle calls made to it.
+type lifecycleReporter struct {
+	m     sync.Mutex
+	calls []string
+}
+
+func (r *lifecycleReporter) call(name string) {
+	r.m.Lock()
+	r.calls = append(r.calls, name)
+	r.m.Unlock()
+}
+
+func (r *lifecycleReporter) Log(*slog.Log) { r.call("log") }
+func (r *lifecycleReporter) Start()        { r.call("start") }
+func (r *lifecycleReporter) Flush()        { r.call("flush") }
+func (r *lifecycleReporter) Close() error  { r.call("close"); return nil }
+
+func TestReporterLifecycle(t *testing.T) {
+
+	r := &lifecycleReporter{}
+	l := slog.New("parent", slog.LevelInfo)
+	l.SetReporter(slog.AtLevel(slog.LevelInfo, r))
+	l.Info("one")
+	require.NoError(t, l.StopContext(context.Background()))
+
+	require.Equal(t, []string{"start", "log", "flush", "close"}, r.calls)
+
+}
+
+func TestReportersLifecycle(t *testing.T) {
+
+	a, b := &lifecycleReporter{}, &lifecycleReporter{}
+	rs := slog.Reporters(a, slog.NewSampler(b, 0, nil))
+	rs.(slog.Starter).Start()
+	rs.(slog.Flusher).Flush()
+
+	require.Equal(t, []string{"start", "flush"}, a.calls)
+	require.Equal(t, []string{"start", "flush"}, b.calls)
+
+}
+
+func TestFlush(t *testing.T) {
+
+	r := &lifecycleReporter{}
+	l := slog.New("parent", slog.LevelInfo)
+	defer l.StopContext(context.Background())
+	l.SetBuffer(10, slog.BlockWhenFull)
+	l.SetReporter(r)
+	l.Info("one")
+	l.Info("two")
+	require.NoError(t, l.Flush(context.Background()))
+
+	r.m.Lock()
+	require.Equal(t, []string{"start", "log", "log", "flush"}, r.calls)
+	r.m.Unlock()
+
+}
+
+func TestFlushTimeout(t *testing.T) {
+
+	release := make(chan struct{})
+	l := slog.New("parent", slog.LevelInfo)
+	defer l.StopContext(context.Background())
+	l.SetBuffer(10, slog.BlockWhenFull)
+	l.SetReporterFunc(func(*slog.Log) { <-release })
+	l.Info("stuck")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	require.Equal(t, context.DeadlineExceeded, l.Flush(ctx))
+	close(release)
+	require.NoError(t, l.Flush(context.Background()))
+
+}
@@ -0,0 +1,102 @@
+package slog
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"time"
+	"unicode"
+)
+
+// NewLogfmtReporter gets a Reporter that writes each log to w
+// as a logfmt line, e.g.
+//
+//	ts=2015-01-02T03:04:05Z level=info source=parent>child caller=main.go:12 msg="something happened" key=value
+func NewLogfmtReporter(w io.Writer) Reporter {
+	return NewWriterReporter(w, LogfmtFormatter)
+}
+
+// LogfmtFormatter formats logs as logfmt lines.
+var LogfmtFormatter = NewLogfmtFormatter(TimeFormat{})
+
+// NewLogfmtFormatter makes a Formatter like LogfmtFormatter that
+// writes times as tf says, RFC 3339 by default.
+func NewLogfmtFormatter(tf TimeFormat) Formatter {
+	return FormatterFunc(func(l *Log) []byte {
+		return formatLogfmt(l, tf)
+	})
+}
+
+func formatLogfmt(l *Log, tf TimeFormat) []byte {
+	var buf bytes.Buffer
+	writeLogfmt(&buf, "ts", tf.format(l.When, time.RFC3339Nano))
+	buf.WriteByte(' ')
+	writeLogfmt(&buf, "level", l.Level.String())
+	buf.WriteByte(' ')
+	writeLogfmt(&buf, "source", l.SourceString())
+	if l.Caller != nil {
+		buf.WriteByte(' ')
+		writeLogfmt(&buf, "caller", l.Caller.String())
+		buf.WriteByte(' ')
+		writeLogfmt(&buf, "func", l.Caller.Function)
+	} else if loc := l.Location(); loc != "" {
+		buf.WriteByte(' ')
+		writeLogfmt(&buf, "caller", loc)
+	}
+	buf.WriteByte(' ')
+	writeLogfmt(&buf, "msg", l.Message())
+	if l.Err != nil {
+		buf.WriteByte(' ')
+		writeLogfmt(&buf, "err", l.Err.Error())
+	}
+	for _, k := range l.Fields.keys() {
+		writeLogfmtField(&buf, k, expand(l.Fields[k], func(v interface{}) interface{} { return v }))
+	}
+	buf.WriteByte('\n')
+	return buf.Bytes()
+}
+
+// writeLogfmtField writes the field, preceded by a space, with
+// each of the fields of expanded structs and maps written as
+// key.field=value.
+func writeLogfmtField(buf *bytes.Buffer, key string, v interface{}) {
+	m, ok := v.(map[string]interface{})
+	if !ok || len(m) == 0 {
+		buf.WriteByte(' ')
+		writeLogfmt(buf, key, fmt.Sprint(v))
+		return
+	}
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		writeLogfmtField(buf, key+"."+k, m[k])
+	}
+}
+
+// writeLogfmt writes key=value, quoting the value if needed.
+func writeLogfmt(buf *bytes.Buffer, key, value string) {
+	buf.WriteString(key)
+	buf.WriteByte('=')
+	if needsLogfmtQuote(value) {
+		buf.WriteString(strconv.Quote(value))
+	} else {
+		buf.WriteString(value)
+	}
+}
+
+func needsLogfmtQuote(s string) bool {
+	if s == "" {
+		return true
+	}
+	for _, r := range s {
+		if r == '=' || r == '"' || unicode.IsSpace(r) || !unicode.IsPrint(r) {
+			return true
+		}
+	}
+	return false
+}
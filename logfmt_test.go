@@ -0,0 +1,40 @@
+package slog_test
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/slog"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLogfmtReporter(t *testing.T) {
+
+	var buf bytes.Buffer
+	r := slog.NewLogfmtReporter(&buf)
+	when := time.Date(2015, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	r.Log(&slog.Log{
+		Level:  slog.LevelWarn,
+		When:   when,
+		Source: []string{"parent", "child"},
+		Data:   []interface{}{"something", "happened"},
+		Fields: slog.Fields{"status": 200, "path": "/a b", "empty": "", "q": `say "hi"`},
+	})
+	r.Log(&slog.Log{Level: slog.LevelInfo, When: when, Source: []string{"parent"}, Data: []interface{}{"ok"}})
+
+	require.Equal(t, `ts=2015-01-02T03:04:05Z level=warning source=parent>child msg="something happened" empty="" path="/a b" q="say \"hi\"" status=200
+ts=2015-01-02T03:04:05Z level=info source=parent msg=ok
+`, buf.String())
+
+	// the location of logs from a Logger is the caller
+	buf.Reset()
+	l := slog.New("parent", slog.LevelInfo)
+	l.SetSync(true)
+	l.SetReporter(r)
+	l.Info("something", "happened")
+	require.Contains(t, buf.String(), " source=parent caller=logfmt_test.go:")
+	require.Contains(t, buf.String(), ` msg="something happened"`)
+
+}
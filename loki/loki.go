@@ -0,0 +1,117 @@
+// Package loki provides a slog.Reporter that pushes logs to
+// Grafana Loki.
+package loki
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/stretchr/slog"
+)
+
+// Options represents the options for a Loki reporter.
+type Options struct {
+	// URL is the base URL of Loki, e.g. "http://loki:3100".
+	URL string
+	// Labels are added to the labels of every stream.
+	Labels map[string]string
+	// Tenant is the tenant ID sent in the X-Scope-OrgID header,
+	// if set.
+	Tenant string
+	// Header holds more headers sent with each request, e.g. auth.
+	Header http.Header
+	// MaxBatch and MaxDelay control the batching of logs, as they
+	// do for slog.HTTPOptions.
+	MaxBatch int
+	MaxDelay time.Duration
+	// Client is the client used to push logs, defaulting to
+	// http.DefaultClient.
+	Client *http.Client
+}
+
+// New makes a Reporter that pushes batches of logs to Loki
+// through its push API, as logfmt lines.
+// Each log is labelled with the first part of its source as
+// "app", its whole source as "source", and its level as "level".
+func New(opts Options) *slog.HTTPReporter {
+	header := http.Header{}
+	for k, vs := range opts.Header {
+		header[k] = vs
+	}
+	if opts.Tenant != "" {
+		header.Set("X-Scope-OrgID", opts.Tenant)
+	}
+	return slog.NewHTTPReporter(slog.HTTPOptions{
+		URL:      strings.TrimSuffix(opts.URL, "/") + "/loki/api/v1/push",
+		Header:   header,
+		Gzip:     true,
+		MaxBatch: opts.MaxBatch,
+		MaxDelay: opts.MaxDelay,
+		Client:   opts.Client,
+		Encode: func(logs []*slog.Log) ([]byte, error) {
+			return encode(opts.Labels, logs)
+		},
+	})
+}
+
+type push struct {
+	Streams []*stream `json:"streams"`
+}
+
+type stream struct {
+	Stream map[string]string `json:"stream"`
+	Values [][2]string       `json:"values"`
+}
+
+// encode encodes the logs as a push request, with a stream for each
+// set of labels.
+func encode(labels map[string]string, logs []*slog.Log) ([]byte, error) {
+	streams := make(map[string]*stream)
+	var keys []string
+	for _, l := range logs {
+		ls := make(map[string]string, len(labels)+3)
+		for k, v := range labels {
+			ls[k] = v
+		}
+		if len(l.Source) > 0 {
+			ls["app"] = l.Source[0]
+		}
+		ls["source"] = l.SourceString()
+		ls["level"] = l.Level.String()
+		key := labelKey(ls)
+		s, ok := streams[key]
+		if !ok {
+			s = &stream{Stream: ls}
+			streams[key] = s
+			keys = append(keys, key)
+		}
+		line := strings.TrimSuffix(string(slog.LogfmtFormatter.Format(l)), "\n")
+		s.Values = append(s.Values, [2]string{strconv.FormatInt(l.When.UnixNano(), 10), line})
+	}
+	p := &push{}
+	for _, key := range keys {
+		p.Streams = append(p.Streams, streams[key])
+	}
+	return json.Marshal(p)
+}
+
+// labelKey gets a string identifying the labels.
+func labelKey(labels map[string]string) string {
+	keys := make([]string, 0, len(labels))
+	for k := range labels {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	var b strings.Builder
+	for _, k := range keys {
+		b.WriteString(strconv.Quote(k))
+		b.WriteByte('=')
+		b.WriteString(strconv.Quote(labels[k]))
+		b.WriteByte(',')
+	}
+	return b.String()
+}
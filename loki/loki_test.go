@@ -0,0 +1,60 @@
+package loki_test
+
+import (
+	"compress/gzip"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/slog"
+	"github.com/stretchr/slog/loki"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReporter(t *testing.T) {
+
+	var m sync.Mutex
+	var paths, tenants, bodies []string
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		zr, err := gzip.NewReader(r.Body)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		body, _ := io.ReadAll(zr)
+		m.Lock()
+		defer m.Unlock()
+		paths = append(paths, r.URL.Path)
+		tenants = append(tenants, r.Header.Get("X-Scope-OrgID"))
+		bodies = append(bodies, string(body))
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer s.Close()
+
+	r := loki.New(loki.Options{URL: s.URL + "/", Labels: map[string]string{"env": "test"}, Tenant: "team"})
+	when := time.Date(2015, 1, 2, 3, 4, 5, 0, time.UTC)
+	r.Log(&slog.Log{Level: slog.LevelInfo, When: when, Source: []string{"parent", "child"}, Data: []interface{}{"one"}})
+	r.Log(&slog.Log{Level: slog.LevelErr, When: when, Source: []string{"parent"}, Data: []interface{}{"two"}})
+	r.Log(&slog.Log{Level: slog.LevelInfo, When: when.Add(time.Second), Source: []string{"parent", "child"}, Data: []interface{}{"three"}})
+	require.NoError(t, r.Close())
+
+	require.Equal(t, []string{"/loki/api/v1/push"}, paths)
+	require.Equal(t, []string{"team"}, tenants)
+	require.JSONEq(t, `{"streams": [
+		{
+			"stream": {"env": "test", "app": "parent", "source": "parent>child", "level": "info"},
+			"values": [
+				["1420167845000000000", "ts=2015-01-02T03:04:05Z level=info source=parent>child msg=one"],
+				["1420167846000000000", "ts=2015-01-02T03:04:06Z level=info source=parent>child msg=three"]
+			]
+		},
+		{
+			"stream": {"env": "test", "app": "parent", "source": "parent", "level": "error"},
+			"values": [["1420167845000000000", "ts=2015-01-02T03:04:05Z level=error source=parent msg=two"]]
+		}
+	]}`, bodies[0])
+
+}
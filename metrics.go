@@ -0,0 +1,155 @@
+package slog
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// metricsSourceSep is the separator used when joining the
+// source of a log into a metrics label.
+const metricsSourceSep = "/"
+
+// MetricsOtherSource is the source label used for logs whose
+// source is over the MetricsReporter source limit.
+const MetricsOtherSource = "other"
+
+// metricsLevels are the level label values, named after the
+// Level constants, e.g. "err" for LevelErr.
+var metricsLevels = map[Level]string{
+	LevelErr:   "err",
+	LevelWarn:  "warn",
+	LevelInfo:  "info",
+	LevelDebug: "debug",
+	LevelTrace: "trace",
+}
+
+// metricsLevel gets the level label value of the level.
+func metricsLevel(l Level) string {
+	if s, ok := metricsLevels[l]; ok {
+		return s
+	}
+	return l.String()
+}
+
+// Count is a single counter held by a MetricsReporter.
+type Count struct {
+	Level  Level
+	Source string
+	N      uint64
+}
+
+type countKey struct {
+	level  Level
+	source string
+}
+
+// MetricsReporter is a Reporter that counts logs by level
+// and, optionally, by source.
+// It does not report the logs anywhere, so is normally used inside
+// Reporters alongside the real Reporter.
+type MetricsReporter struct {
+	m       sync.Mutex
+	limit   int
+	counts  map[countKey]uint64
+	sources map[string]struct{}
+}
+
+var _ Reporter = (*MetricsReporter)(nil)
+var _ http.Handler = (*MetricsReporter)(nil)
+var _ io.WriterTo = (*MetricsReporter)(nil)
+
+// NewMetricsReporter makes a new MetricsReporter that counts
+// logs by level only.
+// Use SetSourceLimit to also count by source.
+func NewMetricsReporter() *MetricsReporter {
+	return &MetricsReporter{
+		counts:  make(map[countKey]uint64),
+		sources: make(map[string]struct{}),
+	}
+}
+
+// SetSourceLimit sets the number of distinct sources that will
+// be counted. Logs from any further sources are counted under
+// MetricsOtherSource.
+// A limit of zero (the default) disables counting by source.
+func (m *MetricsReporter) SetSourceLimit(n int) {
+	m.m.Lock()
+	m.limit = n
+	m.m.Unlock()
+}
+
+// Log counts the log.
+func (m *MetricsReporter) Log(l *Log) {
+	m.m.Lock()
+	key := countKey{level: l.Level}
+	if m.limit > 0 {
+		key.source = strings.Join(l.Source, metricsSourceSep)
+		if _, ok := m.sources[key.source]; !ok {
+			if len(m.sources) < m.limit {
+				m.sources[key.source] = struct{}{}
+			} else {
+				key.source = MetricsOtherSource
+			}
+		}
+	}
+	m.counts[key]++
+	m.m.Unlock()
+}
+
+// Counts gets a snapshot of the counters, ordered by level
+// and then source.
+func (m *MetricsReporter) Counts() []Count {
+	m.m.Lock()
+	counts := make([]Count, 0, len(m.counts))
+	for k, n := range m.counts {
+		counts = append(counts, Count{Level: k.level, Source: k.source, N: n})
+	}
+	m.m.Unlock()
+	sort.Slice(counts, func(i, j int) bool {
+		if counts[i].Level != counts[j].Level {
+			return counts[i].Level < counts[j].Level
+		}
+		return counts[i].Source < counts[j].Source
+	})
+	return counts
+}
+
+// WriteTo writes the counters to w in the Prometheus text
+// exposition format, as the log_messages_total counter with
+// level labels named after the Level constants, e.g.
+// log_messages_total{level="err",source="parent/db"}.
+// It returns the number of bytes written as well as any error,
+// so the MetricsReporter is an io.WriterTo.
+func (m *MetricsReporter) WriteTo(w io.Writer) (int64, error) {
+	var buf bytes.Buffer
+	buf.WriteString("# HELP log_messages_total Number of log messages reported.\n")
+	buf.WriteString("# TYPE log_messages_total counter\n")
+	for _, c := range m.Counts() {
+		if c.Source == "" {
+			fmt.Fprintf(&buf, "log_messages_total{level=\"%s\"} %d\n", metricsLevel(c.Level), c.N)
+			continue
+		}
+		fmt.Fprintf(&buf, "log_messages_total{level=\"%s\",source=\"%s\"} %d\n", metricsLevel(c.Level), escapeLabel(c.Source), c.N)
+	}
+	return buf.WriteTo(w)
+}
+
+// ServeHTTP writes the counters in the Prometheus text
+// exposition format, so the MetricsReporter can be mounted
+// as a scrape endpoint.
+func (m *MetricsReporter) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+	m.WriteTo(w)
+}
+
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// escapeLabel escapes a Prometheus label value.
+func escapeLabel(s string) string {
+	return labelEscaper.Replace(s)
+}
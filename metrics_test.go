@@ -0,0 +1,80 @@
+package slog_test
+
+import (
+	"bytes"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/slog"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMetricsReporter(t *testing.T) {
+
+	m := slog.NewMetricsReporter()
+	m.Log(&slog.Log{Level: slog.LevelErr, Source: []string{"parent", "db"}})
+	m.Log(&slog.Log{Level: slog.LevelErr, Source: []string{"parent"}})
+	m.Log(&slog.Log{Level: slog.LevelInfo, Source: []string{"parent"}})
+
+	require.Equal(t, []slog.Count{
+		{Level: slog.LevelErr, N: 2},
+		{Level: slog.LevelInfo, N: 1},
+	}, m.Counts())
+
+	var buf bytes.Buffer
+	_, err := m.WriteTo(&buf)
+	require.NoError(t, err)
+	require.Equal(t, `# HELP log_messages_total Number of log messages reported.
+# TYPE log_messages_total counter
+log_messages_total{level="err"} 2
+log_messages_total{level="info"} 1
+`, buf.String())
+
+}
+
+func TestMetricsReporterSources(t *testing.T) {
+
+	m := slog.NewMetricsReporter()
+	m.SetSourceLimit(2)
+	m.Log(&slog.Log{Level: slog.LevelErr, Source: []string{"parent", "db"}})
+	m.Log(&slog.Log{Level: slog.LevelErr, Source: []string{"parent", "db"}})
+	m.Log(&slog.Log{Level: slog.LevelWarn, Source: []string{`pa"rent`}})
+	m.Log(&slog.Log{Level: slog.LevelWarn, Source: []string{"parent", "http"}})
+	m.Log(&slog.Log{Level: slog.LevelWarn, Source: []string{"parent", "cache"}})
+
+	var buf bytes.Buffer
+	_, err := m.WriteTo(&buf)
+	require.NoError(t, err)
+	require.Equal(t, `# HELP log_messages_total Number of log messages reported.
+# TYPE log_messages_total counter
+log_messages_total{level="err",source="parent/db"} 2
+log_messages_total{level="warn",source="other"} 2
+log_messages_total{level="warn",source="pa\"rent"} 1
+`, buf.String())
+
+	rec := httptest.NewRecorder()
+	m.ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+	require.Equal(t, buf.String(), rec.Body.String())
+	require.Contains(t, rec.Header().Get("Content-Type"), "text/plain")
+
+}
+
+func TestMetricsReporterConcurrent(t *testing.T) {
+
+	m := slog.NewMetricsReporter()
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				m.Log(&slog.Log{Level: slog.LevelInfo})
+			}
+		}()
+	}
+	wg.Wait()
+
+	require.Equal(t, []slog.Count{{Level: slog.LevelInfo, N: 1000}}, m.Counts())
+
+}
@@ -0,0 +1,89 @@
+package slog
+
+import "sync/atomic"
+
+// OnceLimit is the number of keys a RootLogger remembers for the
+// Once methods. Once there are more, they are all forgotten, so
+// each may be logged once more.
+const OnceLimit = 10000
+
+func (l *logger) InfoOnce(key string, a ...interface{}) bool {
+	return l.once(LevelInfo, key, a)
+}
+
+func (l *logger) WarnOnce(key string, a ...interface{}) bool {
+	return l.once(LevelWarn, key, a)
+}
+
+func (l *logger) ErrOnce(key string, a ...interface{}) bool {
+	return l.once(LevelErr, key, a)
+}
+
+func (l *logger) InfoEvery(n int, a ...interface{}) bool {
+	return l.every(LevelInfo, n, a)
+}
+
+func (l *logger) WarnEvery(n int, a ...interface{}) bool {
+	return l.every(LevelWarn, n, a)
+}
+
+func (l *logger) ErrEvery(n int, a ...interface{}) bool {
+	return l.every(LevelErr, n, a)
+}
+
+// once logs a at the level if nothing has been logged with the
+// key yet, and gets whether it was logged.
+// It must be called directly from the Logger method so the
+// caller's file and line are recorded.
+func (l *logger) once(level Level, key string, a []interface{}) bool {
+	if l.skip(level) || !l.root.first(key) {
+		return false
+	}
+	return l.emit(level, callerPC(2), a)
+}
+
+// every logs a at the level the first time, and every nth time
+// after that, it is called from the same place, and gets whether
+// it was logged.
+// It must be called directly from the Logger method so the
+// caller's file and line are recorded.
+func (l *logger) every(level Level, n int, a []interface{}) bool {
+	if l.skip(level) {
+		return false
+	}
+	pc := callerPC(2)
+	if !l.root.nth(pc, n) {
+		return false
+	}
+	return l.emit(level, pc, a)
+}
+
+// first records the key, and gets whether it's the first time it
+// has been.
+// Must only be called on the root logger.
+func (l *logger) first(key string) bool {
+	l.om.Lock()
+	defer l.om.Unlock()
+	if _, ok := l.onceKeys[key]; ok {
+		return false
+	}
+	if l.onceKeys == nil || len(l.onceKeys) >= OnceLimit {
+		l.onceKeys = make(map[string]struct{})
+	}
+	l.onceKeys[key] = struct{}{}
+	return true
+}
+
+// nth counts a call from pc, and gets whether it's the first, or
+// a multiple of n after it.
+// There are only as many counts as places that log, so they
+// aren't limited.
+// Must only be called on the root logger.
+func (l *logger) nth(pc uintptr, n int) bool {
+	c, ok := l.nths.Load(pc)
+	if !ok {
+		c, _ = l.nths.LoadOrStore(pc, new(uint64))
+	}
+	count := atomic.AddUint64(c.(*uint64), 1)
+	return n <= 1 || (count-1)%uint64(n) == 0
+}
@@ -0,0 +1,65 @@
+package slog_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/slog"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOnce(t *testing.T) {
+
+	l := slog.New("parent", slog.LevelWarn)
+	r := NewTestReporter()
+	l.SetReporter(r)
+	child := l.New("child")
+
+	require.False(t, l.InfoOnce("miss", "cache miss"))
+	require.True(t, l.WarnOnce("miss", "cache miss"))
+	require.False(t, child.WarnOnce("miss", "cache miss"))
+	require.True(t, child.ErrOnce("other", "failed"))
+
+	l.SetLevel(slog.LevelInfo)
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			l.InfoOnce("start", "started")
+		}()
+	}
+	wg.Wait()
+	require.NoError(t, l.StopContext(context.Background()))
+
+	require.Equal(t, 3, len(r.logs))
+	require.Equal(t, slog.LevelWarn, r.logs[0].Level)
+	require.Equal(t, slog.LevelErr, r.logs[1].Level)
+	require.Equal(t, "started", r.logs[2].Data[1])
+
+}
+
+func TestEvery(t *testing.T) {
+
+	l := slog.New("parent", slog.LevelInfo)
+	r := NewTestReporter()
+	l.SetReporter(r)
+
+	for i := 0; i < 10; i++ {
+		l.WarnEvery(4, "cache miss", i)
+		l.InfoEvery(1, "always", i)
+		l.ErrEvery(0, "always", i)
+	}
+	require.NoError(t, l.StopContext(context.Background()))
+
+	var misses []interface{}
+	for _, log := range r.logs {
+		if log.Data[1] == "cache miss" {
+			misses = append(misses, log.Data[2])
+		}
+	}
+	require.Equal(t, []interface{}{0, 4, 8}, misses)
+	require.Equal(t, 23, len(r.logs))
+
+}
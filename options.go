@@ -0,0 +1,127 @@
+package slog
+
+// options holds the settings made by the Options given to
+// NewWithOptions.
+type options struct {
+	level    Level
+	reporter Reporter
+	buffer   int
+	policy   DropPolicy
+	caller   bool
+	clock    Clock
+	sep      string
+	sync     bool
+	hooks    []Hook
+}
+
+// Option configures a RootLogger made with NewWithOptions.
+type Option func(o *options)
+
+// WithLevel sets the level of the RootLogger, which is LevelInfo
+// by default.
+func WithLevel(level Level) Option {
+	return func(o *options) {
+		o.level = level
+	}
+}
+
+// WithReporter sets the Reporter of the RootLogger, which is
+// Stdout by default.
+func WithReporter(r Reporter) Option {
+	return func(o *options) {
+		o.reporter = r
+	}
+}
+
+// WithBuffer sets the number of logs that may be waiting for
+// the Reporter, as SetBuffer does.
+func WithBuffer(n int) Option {
+	return func(o *options) {
+		o.buffer = n
+	}
+}
+
+// WithDropPolicy sets what happens to logs made while the buffer
+// is full, as SetBuffer does.
+func WithDropPolicy(policy DropPolicy) Option {
+	return func(o *options) {
+		o.policy = policy
+	}
+}
+
+// WithCaptureCaller sets whether logs have their Caller captured,
+// as SetCaptureCaller does.
+func WithCaptureCaller(capture bool) Option {
+	return func(o *options) {
+		o.caller = capture
+	}
+}
+
+// WithClock sets the Clock of the RootLogger, as SetClock does.
+func WithClock(c Clock) Option {
+	return func(o *options) {
+		o.clock = c
+	}
+}
+
+// WithSourceSep sets the SourceSep of logs, which separates the
+// names of their sources when reporters write them, e.g. "/" or
+// ".". Sources given to SetSourceLevel, Boost, SourceFilter,
+// Rules and TailHandler can then be written with it too, as well
+// as with DefaultSourceSep, and SourceLevels and Tree write
+// sources with it.
+func WithSourceSep(sep string) Option {
+	return func(o *options) {
+		o.sep = sep
+	}
+}
+
+// WithSync sets whether logs are reported before the log methods
+// return, as SetSync does.
+func WithSync(sync bool) Option {
+	return func(o *options) {
+		o.sync = sync
+	}
+}
+
+// WithProcess adds a Process hook for the current process, as
+// CurrentProcess gets it, so every log says which host and program
+// made it. If version isn't empty it is used as the Version.
+func WithProcess(version string) Option {
+	return func(o *options) {
+		p := CurrentProcess()
+		if version != "" {
+			p.Version = version
+		}
+		o.hooks = append(o.hooks, Process(p))
+	}
+}
+
+// NewWithOptions creates a new RootLogger, like New, configured
+// by the options.
+func NewWithOptions(source string, opts ...Option) RootLogger {
+	o := &options{
+		level:    LevelInfo,
+		reporter: Stdout,
+	}
+	for _, opt := range opts {
+		opt(o)
+	}
+	l := &logger{
+		level: int32(o.level),
+		sep:   o.sep,
+	}
+	l.path.Store(&sourcePath{src: Source{source}, name: source})
+	l.root = l // use this one as the root one
+	l.node.Store(&treeNode{})
+	l.SetReporter(o.reporter)
+	l.SetClock(o.clock)
+	l.start()
+	l.q.setSize(o.buffer, o.policy)
+	l.SetCaptureCaller(o.caller)
+	l.SetSync(o.sync)
+	for _, h := range o.hooks {
+		l.AddHook(h)
+	}
+	return l
+}
@@ -0,0 +1,44 @@
+package slog_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/slog"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewWithOptions(t *testing.T) {
+
+	when := time.Date(2015, 1, 2, 3, 4, 5, 0, time.UTC)
+	r := NewTestReporter()
+	l := slog.NewWithOptions("parent",
+		slog.WithLevel(slog.LevelWarn),
+		slog.WithReporter(r),
+		slog.WithBuffer(1),
+		slog.WithDropPolicy(slog.DropNewest),
+		slog.WithCaptureCaller(true),
+		slog.WithClock(slog.ClockFunc(func() time.Time { return when })),
+	)
+
+	require.Equal(t, slog.LevelWarn, l.Level())
+	require.False(t, l.Info("skipped"))
+	require.True(t, l.Warn("reported"))
+	require.NoError(t, l.StopContext(context.Background()))
+
+	require.Equal(t, 1, len(r.logs))
+	require.Equal(t, when, r.logs[0].When)
+	require.Equal(t, "github.com/stretchr/slog_test.TestNewWithOptions", r.logs[0].Caller.Function)
+
+}
+
+func TestNewWithOptionsDefaults(t *testing.T) {
+
+	l := slog.NewWithOptions("parent")
+	defer l.StopContext(context.Background())
+
+	require.Equal(t, slog.LevelInfo, l.Level())
+	require.False(t, l.Debug())
+
+}
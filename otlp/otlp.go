@@ -0,0 +1,219 @@
+// Package otlp provides a slog.Reporter that exports logs to an
+// OpenTelemetry collector as OTLP log records, over HTTP in the
+// JSON encoding.
+package otlp
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/stretchr/slog"
+)
+
+// Options represents the options for an OTLP reporter.
+type Options struct {
+	// Endpoint is the base URL of the OTLP/HTTP receiver, e.g.
+	// "http://collector:4318". Logs are posted to /v1/logs.
+	Endpoint string
+	// Resource holds the attributes of the resource the logs come
+	// from, e.g. "service.name".
+	Resource map[string]string
+	// Header holds more headers sent with each request, e.g. auth.
+	Header http.Header
+	// MaxBatch and MaxDelay control the batching of logs, as they
+	// do for slog.HTTPOptions.
+	MaxBatch int
+	MaxDelay time.Duration
+	// Client is the client used to export logs, defaulting to
+	// http.DefaultClient.
+	Client *http.Client
+}
+
+// severity is an OpenTelemetry severity.
+type severity struct {
+	number int
+	text   string
+}
+
+// severities maps levels to OpenTelemetry severities.
+var severities = map[slog.Level]severity{
+	slog.LevelErr:   {17, "ERROR"},
+	slog.LevelWarn:  {13, "WARN"},
+	slog.LevelInfo:  {9, "INFO"},
+	slog.LevelDebug: {5, "DEBUG"},
+	slog.LevelTrace: {1, "TRACE"},
+}
+
+// The fields holding trace and span IDs, as added by slogotel.
+const (
+	traceIDKey = "trace_id"
+	spanIDKey  = "span_id"
+)
+
+// New makes a Reporter that exports batches of logs over OTLP.
+// The source of each log becomes the name of its instrumentation
+// scope, its level the severity, and its Data the body.
+// Fields become attributes, except trace_id and span_id, which link
+// the record to its span. Err, Stack and Caller become the
+// exception.* and code.* attributes of the OpenTelemetry semantic
+// conventions.
+func New(opts Options) *slog.HTTPReporter {
+	return slog.NewHTTPReporter(slog.HTTPOptions{
+		URL:      strings.TrimSuffix(opts.Endpoint, "/") + "/v1/logs",
+		Header:   opts.Header,
+		Gzip:     true,
+		MaxBatch: opts.MaxBatch,
+		MaxDelay: opts.MaxDelay,
+		Client:   opts.Client,
+		Encode: func(logs []*slog.Log) ([]byte, error) {
+			return encode(opts.Resource, logs)
+		},
+	})
+}
+
+type exportRequest struct {
+	ResourceLogs []resourceLogs `json:"resourceLogs"`
+}
+
+type resourceLogs struct {
+	Resource  resource     `json:"resource"`
+	ScopeLogs []*scopeLogs `json:"scopeLogs"`
+}
+
+type resource struct {
+	Attributes []attribute `json:"attributes,omitempty"`
+}
+
+type scopeLogs struct {
+	Scope      scope       `json:"scope"`
+	LogRecords []logRecord `json:"logRecords"`
+}
+
+type scope struct {
+	Name string `json:"name"`
+}
+
+type logRecord struct {
+	TimeUnixNano   string      `json:"timeUnixNano"`
+	SeverityNumber int         `json:"severityNumber"`
+	SeverityText   string      `json:"severityText"`
+	Body           anyValue    `json:"body"`
+	Attributes     []attribute `json:"attributes,omitempty"`
+	TraceID        string      `json:"traceId,omitempty"`
+	SpanID         string      `json:"spanId,omitempty"`
+}
+
+type attribute struct {
+	Key   string   `json:"key"`
+	Value anyValue `json:"value"`
+}
+
+type anyValue struct {
+	StringValue *string  `json:"stringValue,omitempty"`
+	BoolValue   *bool    `json:"boolValue,omitempty"`
+	IntValue    *string  `json:"intValue,omitempty"`
+	DoubleValue *float64 `json:"doubleValue,omitempty"`
+}
+
+// encode encodes the logs as an export request, with a scope for
+// each source.
+func encode(res map[string]string, logs []*slog.Log) ([]byte, error) {
+	rl := resourceLogs{}
+	keys := make([]string, 0, len(res))
+	for k := range res {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		rl.Resource.Attributes = append(rl.Resource.Attributes, attribute{Key: k, Value: value(res[k])})
+	}
+	scopes := make(map[string]*scopeLogs)
+	for _, l := range logs {
+		source := l.SourceString()
+		s, ok := scopes[source]
+		if !ok {
+			s = &scopeLogs{Scope: scope{Name: source}}
+			scopes[source] = s
+			rl.ScopeLogs = append(rl.ScopeLogs, s)
+		}
+		s.LogRecords = append(s.LogRecords, record(l))
+	}
+	return json.Marshal(&exportRequest{ResourceLogs: []resourceLogs{rl}})
+}
+
+// record makes the log record for the log.
+func record(l *slog.Log) logRecord {
+	sev, ok := severities[l.Level]
+	if !ok {
+		sev = severities[slog.LevelInfo]
+	}
+	r := logRecord{
+		TimeUnixNano:   strconv.FormatInt(l.When.UnixNano(), 10),
+		SeverityNumber: sev.number,
+		SeverityText:   sev.text,
+		Body:           value(l.Message()),
+	}
+	keys := make([]string, 0, len(l.Fields))
+	for k := range l.Fields {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		v := l.Fields[k]
+		if id, ok := v.(string); ok && k == traceIDKey {
+			r.TraceID = id
+			continue
+		}
+		if id, ok := v.(string); ok && k == spanIDKey {
+			r.SpanID = id
+			continue
+		}
+		r.Attributes = append(r.Attributes, attribute{Key: k, Value: value(v)})
+	}
+	if l.Err != nil {
+		r.Attributes = append(r.Attributes,
+			attribute{Key: "exception.type", Value: value(fmt.Sprintf("%T", l.Err))},
+			attribute{Key: "exception.message", Value: value(l.Err.Error())},
+		)
+	}
+	if l.Stack != "" {
+		r.Attributes = append(r.Attributes, attribute{Key: "exception.stacktrace", Value: value(l.Stack)})
+	}
+	if l.Caller != nil {
+		r.Attributes = append(r.Attributes,
+			attribute{Key: "code.filepath", Value: value(l.Caller.File)},
+			attribute{Key: "code.lineno", Value: value(l.Caller.Line)},
+			attribute{Key: "code.function", Value: value(l.Caller.Function)},
+		)
+	}
+	return r
+}
+
+// value gets the OTLP value for v, falling back to the fmt
+// representation of it.
+func value(v interface{}) anyValue {
+	switch v := v.(type) {
+	case bool:
+		return anyValue{BoolValue: &v}
+	case int, int8, int16, int32, int64:
+		s := fmt.Sprint(v)
+		return anyValue{IntValue: &s}
+	case uint8, uint16, uint32:
+		s := fmt.Sprint(v)
+		return anyValue{IntValue: &s}
+	case float32:
+		f := float64(v)
+		return anyValue{DoubleValue: &f}
+	case float64:
+		return anyValue{DoubleValue: &v}
+	case string:
+		return anyValue{StringValue: &v}
+	}
+	s := fmt.Sprint(v)
+	return anyValue{StringValue: &s}
+}
@@ -0,0 +1,93 @@
+package otlp_test
+
+import (
+	"compress/gzip"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/slog"
+	"github.com/stretchr/slog/otlp"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReporter(t *testing.T) {
+
+	var m sync.Mutex
+	var paths, bodies []string
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		zr, err := gzip.NewReader(r.Body)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		body, _ := io.ReadAll(zr)
+		m.Lock()
+		defer m.Unlock()
+		paths = append(paths, r.URL.Path)
+		bodies = append(bodies, string(body))
+	}))
+	defer s.Close()
+
+	r := otlp.New(otlp.Options{Endpoint: s.URL, Resource: map[string]string{"service.name": "app"}})
+	when := time.Date(2015, 1, 2, 3, 4, 5, 0, time.UTC)
+	r.Log(&slog.Log{
+		Level:  slog.LevelInfo,
+		When:   when,
+		Source: []string{"parent", "child"},
+		Data:   []interface{}{"saved", 2},
+		Fields: slog.Fields{"n": 1, "ok": true, "trace_id": "4bf92f3577b34da6a3ce929d0e0e4736", "span_id": "00f067aa0ba902b7"},
+	})
+	r.Log(&slog.Log{
+		Level:  slog.LevelErr,
+		When:   when,
+		Source: []string{"parent"},
+		Data:   []interface{}{"failed"},
+		Err:    errors.New("disk full"),
+		Caller: &slog.Caller{File: "/src/main.go", Line: 2, Function: "main.main"},
+	})
+	require.NoError(t, r.Close())
+
+	require.Equal(t, []string{"/v1/logs"}, paths)
+	require.JSONEq(t, `{"resourceLogs": [{
+		"resource": {"attributes": [{"key": "service.name", "value": {"stringValue": "app"}}]},
+		"scopeLogs": [
+			{
+				"scope": {"name": "parent>child"},
+				"logRecords": [{
+					"timeUnixNano": "1420167845000000000",
+					"severityNumber": 9,
+					"severityText": "INFO",
+					"body": {"stringValue": "saved 2"},
+					"attributes": [
+						{"key": "n", "value": {"intValue": "1"}},
+						{"key": "ok", "value": {"boolValue": true}}
+					],
+					"traceId": "4bf92f3577b34da6a3ce929d0e0e4736",
+					"spanId": "00f067aa0ba902b7"
+				}]
+			},
+			{
+				"scope": {"name": "parent"},
+				"logRecords": [{
+					"timeUnixNano": "1420167845000000000",
+					"severityNumber": 17,
+					"severityText": "ERROR",
+					"body": {"stringValue": "failed"},
+					"attributes": [
+						{"key": "exception.type", "value": {"stringValue": "*errors.errorString"}},
+						{"key": "exception.message", "value": {"stringValue": "disk full"}},
+						{"key": "code.filepath", "value": {"stringValue": "/src/main.go"}},
+						{"key": "code.lineno", "value": {"intValue": "2"}},
+						{"key": "code.function", "value": {"stringValue": "main.main"}}
+					]
+				}]
+			}
+		]
+	}]}`, bodies[0])
+
+}
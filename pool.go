@@ -0,0 +1,50 @@
+package slog
+
+import "sync"
+
+// loggerPool holds the loggers given back by Release.
+var loggerPool = sync.Pool{
+	New: func() interface{} { return new(logger) },
+}
+
+// nodePath is the path of the source of a node in the tree, made
+// from the path of its parent.
+type nodePath struct {
+	parent *sourcePath
+	path   *sourcePath
+}
+
+// childPath gets the path of the child source of parent, whose
+// node is n. The path is kept in the node, so making the same child
+// again doesn't allocate.
+func childPath(n *treeNode, parent *sourcePath, name string) *sourcePath {
+	if n == nil {
+		return newSourcePath(parent.src, name)
+	}
+	if p, ok := n.path.Load().(*nodePath); ok && p.parent == parent {
+		return p.path
+	}
+	path := newSourcePath(parent.src, name)
+	n.path.Store(&nodePath{parent: parent, path: path})
+	return path
+}
+
+func (l *logger) Acquire(source string) Logger {
+	child := loggerPool.Get().(*logger)
+	child.fields = l.fields
+	child.err = l.err
+	child.subs = l.subs
+	child.root = l.root
+	child.pooled = true
+	l.initChild(child, source)
+	return child
+}
+
+func (l *logger) Release() {
+	l.Close()
+	if !l.pooled {
+		return
+	}
+	*l = logger{}
+	loggerPool.Put(l)
+}
@@ -0,0 +1,39 @@
+package slog_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/slog"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAcquire(t *testing.T) {
+
+	l := slog.New("parent", slog.LevelInfo)
+	l.SetSync(true)
+	r := NewTestReporter()
+	l.SetReporter(r)
+
+	child := l.With("id", 1).Acquire("http")
+	require.Equal(t, int64(1), l.Stats().Children)
+	require.True(t, child.Info("request"))
+	child.Release()
+	require.Equal(t, int64(0), l.Stats().Children)
+
+	child = l.Acquire("db")
+	require.True(t, child.Warn("query"))
+	child.Release()
+
+	require.Len(t, r.logs, 2)
+	require.Equal(t, slog.Source{"parent", "http"}, r.logs[0].Source)
+	require.Equal(t, slog.Fields{"id": 1}, r.logs[0].Fields)
+	require.Equal(t, slog.Source{"parent", "db"}, r.logs[1].Source)
+	require.Empty(t, r.logs[1].Fields)
+
+	// releasing loggers from New just closes them
+	child = l.New("job")
+	child.Release()
+	require.False(t, child.Info("closed"))
+	slog.NilLogger.Acquire("nil").Release()
+
+}
@@ -0,0 +1,75 @@
+package slog
+
+import (
+	"os"
+	"path/filepath"
+	"runtime/debug"
+)
+
+// The fields a Process hook adds to logs.
+const (
+	HostKey    = "host"
+	PIDKey     = "pid"
+	BinaryKey  = "bin"
+	VersionKey = "version"
+)
+
+// ProcessInfo represents the host and process making logs.
+type ProcessInfo struct {
+	// Hostname is the name of the host.
+	Hostname string
+	// PID is the process ID.
+	PID int
+	// Binary is the name of the program.
+	Binary string
+	// Version is the version of the program.
+	Version string
+}
+
+// CurrentProcess gets the ProcessInfo of this process, with the
+// version of the main module as the Version, if it was built with
+// one.
+func CurrentProcess() ProcessInfo {
+	p := ProcessInfo{PID: os.Getpid()}
+	p.Hostname, _ = os.Hostname()
+	if exe, err := os.Executable(); err == nil {
+		p.Binary = filepath.Base(exe)
+	} else if len(os.Args) > 0 {
+		p.Binary = filepath.Base(os.Args[0])
+	}
+	if info, ok := debug.ReadBuildInfo(); ok && info.Main.Version != "(devel)" {
+		p.Version = info.Main.Version
+	}
+	return p
+}
+
+// Fields gets the info as Fields, keyed by HostKey, PIDKey,
+// BinaryKey and VersionKey, leaving out the empty ones.
+func (p ProcessInfo) Fields() Fields {
+	fields := make(Fields, 4)
+	if p.Hostname != "" {
+		fields[HostKey] = p.Hostname
+	}
+	if p.PID != 0 {
+		fields[PIDKey] = p.PID
+	}
+	if p.Binary != "" {
+		fields[BinaryKey] = p.Binary
+	}
+	if p.Version != "" {
+		fields[VersionKey] = p.Version
+	}
+	return fields
+}
+
+// Process gets a Hook that adds the fields of the ProcessInfo to
+// logs, so logs gathered from many hosts can be told apart.
+// Fields already in the log are kept.
+func Process(p ProcessInfo) Hook {
+	fields := p.Fields()
+	return func(l *Log) *Log {
+		enriched := *l
+		enriched.Fields = fields.merge(l.Fields)
+		return &enriched
+	}
+}
@@ -0,0 +1,43 @@
+package slog_test
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/stretchr/slog"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProcess(t *testing.T) {
+
+	hook := slog.Process(slog.ProcessInfo{Hostname: "web1", PID: 42, Binary: "app"})
+	fields := slog.Fields{"pid": "mine", "user": "mat"}
+	l := &slog.Log{Fields: fields}
+	enriched := hook(l)
+	require.Equal(t, slog.Fields{"host": "web1", "pid": "mine", "bin": "app", "user": "mat"}, enriched.Fields)
+
+	// the log is not changed
+	require.Equal(t, slog.Fields{"pid": "mine", "user": "mat"}, l.Fields)
+
+	enriched = hook(&slog.Log{})
+	require.Equal(t, slog.Fields{"host": "web1", "pid": 42, "bin": "app"}, enriched.Fields)
+
+}
+
+func TestWithProcess(t *testing.T) {
+
+	r := NewTestReporter()
+	l := slog.NewWithOptions("parent", slog.WithReporter(r), slog.WithProcess("v1.2.3"))
+	l.Info("started")
+	require.NoError(t, l.StopContext(context.Background()))
+
+	p := slog.CurrentProcess()
+	require.Equal(t, os.Getpid(), p.PID)
+	require.NotEmpty(t, p.Binary)
+	require.Equal(t, 1, len(r.logs))
+	require.Equal(t, os.Getpid(), r.logs[0].Fields[slog.PIDKey])
+	require.Equal(t, p.Binary, r.logs[0].Fields[slog.BinaryKey])
+	require.Equal(t, "v1.2.3", r.logs[0].Fields[slog.VersionKey])
+
+}
@@ -0,0 +1,204 @@
+package slog
+
+import (
+	"context"
+	"sync"
+)
+
+// DropPolicy decides what happens to logs made while the
+// buffer of a RootLogger is full.
+type DropPolicy uint8
+
+const (
+	// BlockWhenFull makes logging wait until there is room in
+	// the buffer.
+	BlockWhenFull DropPolicy = iota
+	// DropOldest drops the oldest log in the buffer to make room.
+	DropOldest
+	// DropNewest drops the log being made.
+	DropNewest
+)
+
+// queue holds the logs waiting to be reported.
+type queue struct {
+	m        sync.Mutex
+	cond     *sync.Cond // broadcast whenever the queue changes
+	items    []*Log
+	size     int
+	policy   DropPolicy
+	closed   bool
+	added    uint64 // number of logs ever added
+	gone     uint64 // number of logs ever taken or dropped
+	finished uint64 // number of logs ever reported or dropped
+	dropped  uint64
+}
+
+func newQueue() *queue {
+	q := &queue{}
+	q.cond = sync.NewCond(&q.m)
+	return q
+}
+
+// setSize sets the size of the buffer and the policy for when
+// it is full.
+func (q *queue) setSize(n int, policy DropPolicy) {
+	q.m.Lock()
+	q.size = n
+	q.policy = policy
+	q.cond.Broadcast()
+	q.m.Unlock()
+}
+
+// put adds the log to the queue, getting false if the queue is
+// closed, and whether a log was dropped because it was full.
+// If the queue is unbuffered, put waits for the log to be taken.
+func (q *queue) put(l *Log) (ok, dropped bool) {
+	q.m.Lock()
+	defer q.m.Unlock()
+	for q.size > 0 && len(q.items) >= q.size && !q.closed {
+		switch q.policy {
+		case DropNewest:
+			q.dropped++
+			return true, true
+		case DropOldest:
+			q.items = q.items[1:]
+			q.gone++
+			q.finished++
+			q.dropped++
+			dropped = true
+		default:
+			q.cond.Wait()
+		}
+	}
+	if q.closed {
+		return false, dropped
+	}
+	q.items = append(q.items, l)
+	q.added++
+	q.cond.Broadcast()
+	if q.size == 0 {
+		for seq := q.added; q.gone < seq; {
+			q.cond.Wait()
+		}
+	}
+	return true, dropped
+}
+
+// take takes the oldest log from the queue, waiting for one
+// if it is empty.
+// It returns false once the queue is closed and empty.
+func (q *queue) take() (*Log, bool) {
+	q.m.Lock()
+	defer q.m.Unlock()
+	for len(q.items) == 0 {
+		if q.closed {
+			return nil, false
+		}
+		q.cond.Wait()
+	}
+	l := q.items[0]
+	q.items[0] = nil
+	q.items = q.items[1:]
+	q.gone++
+	q.cond.Broadcast()
+	return l, true
+}
+
+// finish records that a log taken from the queue has been
+// reported.
+func (q *queue) finish() {
+	q.m.Lock()
+	q.finished++
+	q.cond.Broadcast()
+	q.m.Unlock()
+}
+
+// flush waits until every log added so far has been reported
+// or dropped.
+func (q *queue) flush() {
+	q.m.Lock()
+	for seq := q.added; q.finished < seq; {
+		q.cond.Wait()
+	}
+	q.m.Unlock()
+}
+
+// flushContext is like flush, but gives up when ctx is done,
+// getting its error.
+func (q *queue) flushContext(ctx context.Context) error {
+	stop := context.AfterFunc(ctx, func() {
+		q.m.Lock()
+		q.cond.Broadcast()
+		q.m.Unlock()
+	})
+	defer stop()
+	q.m.Lock()
+	defer q.m.Unlock()
+	for seq := q.added; q.finished < seq; {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		q.cond.Wait()
+	}
+	return nil
+}
+
+// close stops the queue accepting logs.
+func (q *queue) close() {
+	q.m.Lock()
+	q.closed = true
+	q.cond.Broadcast()
+	q.m.Unlock()
+}
+
+// isClosed gets whether the queue has been closed.
+func (q *queue) isClosed() bool {
+	q.m.Lock()
+	defer q.m.Unlock()
+	return q.closed
+}
+
+// discard empties the queue, returning how many logs it held.
+func (q *queue) discard() int {
+	q.m.Lock()
+	n := len(q.items)
+	q.items = nil
+	q.gone += uint64(n)
+	q.finished += uint64(n)
+	q.cond.Broadcast()
+	q.m.Unlock()
+	return n
+}
+
+// length gets the number of logs waiting in the queue.
+func (q *queue) length() int {
+	q.m.Lock()
+	defer q.m.Unlock()
+	return len(q.items)
+}
+
+// pressure gets how full the buffer is, from 0 when it is empty
+// to 1 when it is full. Without a buffer it is 1 while a log is
+// waiting to be taken.
+func (q *queue) pressure() float64 {
+	q.m.Lock()
+	defer q.m.Unlock()
+	if q.size == 0 {
+		if len(q.items) > 0 {
+			return 1
+		}
+		return 0
+	}
+	if len(q.items) >= q.size {
+		return 1
+	}
+	return float64(len(q.items)) / float64(q.size)
+}
+
+// droppedCount gets the number of logs dropped because the
+// buffer was full.
+func (q *queue) droppedCount() uint64 {
+	q.m.Lock()
+	defer q.m.Unlock()
+	return q.dropped
+}
@@ -0,0 +1,66 @@
+package slog
+
+import (
+	"runtime"
+	"runtime/debug"
+)
+
+// Recover recovers from a panic, if there is one, and logs the
+// value it panicked with at LevelErr, with the stack of the panic.
+// It must be deferred, for example at the top of goroutines:
+//
+//	defer slog.Recover(l)
+func Recover(l Logger) {
+	if v := recover(); v != nil {
+		logPanic(l, v)
+	}
+}
+
+// RecoverAndRepanic is like Recover, but panics again with the
+// value once it has been logged.
+func RecoverAndRepanic(l Logger) {
+	if v := recover(); v != nil {
+		logPanic(l, v)
+		panic(v)
+	}
+}
+
+func (l *logger) CapturePanic() {
+	if v := recover(); v != nil {
+		logPanic(l, v)
+	}
+}
+
+// logPanic logs the value a goroutine panicked with, and the
+// stack. It must be called by the func that recovered.
+func logPanic(l Logger, v interface{}) {
+	stack := string(debug.Stack())
+	pl, ok := l.(*logger)
+	if !ok {
+		l.Err("panic:", v, Fields{"stack": stack})
+		return
+	}
+	if pl.skip(LevelErr) {
+		return
+	}
+	pc := panicPC()
+	if pc == 0 {
+		pc = callerPC(1)
+	}
+	item := pl.makeLog(LevelErr, pc, []interface{}{"panic:", v})
+	item.Stack = stack
+	pl.emitLog(item)
+}
+
+// panicPC gets the program counter of where the goroutine
+// panicked, or zero if it can't be found.
+func panicPC() uintptr {
+	var pcs [32]uintptr
+	n := runtime.Callers(2, pcs[:])
+	for i := 0; i < n-1; i++ {
+		if f := runtime.FuncForPC(pcs[i] - 1); f != nil && f.Name() == "runtime.gopanic" {
+			return pcs[i+1]
+		}
+	}
+	return 0
+}
@@ -0,0 +1,58 @@
+package slog_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/pat/stop"
+	"github.com/stretchr/slog"
+	"github.com/stretchr/testify/require"
+)
+
+func panics(v interface{}) {
+	panic(v)
+}
+
+func TestRecover(t *testing.T) {
+
+	l := slog.New("parent", slog.LevelErr)
+	l.SetSync(true)
+	defer func() {
+		l.Stop(stop.NoWait)
+		<-l.StopChan()
+	}()
+	r := NewTestReporter()
+	l.SetReporter(r)
+
+	func() {
+		defer slog.Recover(l)
+		panics("boom")
+	}()
+	func() {
+		defer l.New("child").CapturePanic()
+		panics("bang")
+	}()
+	func() {
+		defer slog.Recover(l)
+	}()
+
+	require.Equal(t, 2, len(r.logs))
+	require.Contains(t, r.logs[0].Data[0], "recover_test.go:13")
+	require.Equal(t, []interface{}{"panic:", "boom"}, r.logs[0].Data[1:])
+	require.Equal(t, slog.LevelErr, r.logs[0].Level)
+	require.True(t, strings.Contains(r.logs[0].Stack, "slog_test.panics"))
+	require.Equal(t, slog.Source{"parent", "child"}, r.logs[1].Source)
+	require.Equal(t, "bang", r.logs[1].Data[2])
+
+	require.Panics(t, func() {
+		defer slog.RecoverAndRepanic(l)
+		panics("again")
+	})
+	require.Equal(t, 3, len(r.logs))
+
+	func() {
+		defer slog.NilLogger.CapturePanic()
+		panics("ignored")
+	}()
+
+}
@@ -0,0 +1,125 @@
+package slog
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Redacted replaces the values masked by a Redact hook.
+const Redacted = "[REDACTED]"
+
+// DefaultRedactKeys are the field keys masked by a Redact hook
+// when RedactOptions.Keys is nil.
+var DefaultRedactKeys = []string{"password", "passwd", "secret", "token", "authorization", "api_key", "cookie"}
+
+// RedactOptions represents the options for a Redact hook.
+type RedactOptions struct {
+	// Keys are the keys of the fields whose values are masked,
+	// ignoring case. Keys in groups, e.g. "user.password", match
+	// on their last part too.
+	// Nil means DefaultRedactKeys.
+	Keys []string
+	// Patterns are matched in the Data, Fields and Err of logs, as
+	// they are written, and the matches masked.
+	Patterns []*regexp.Regexp
+}
+
+// Redact gets a Hook that masks the values of fields with the
+// keys, and the matches of the patterns, with Redacted.
+// Maps, structs and slices in the Data and Fields are masked
+// inside too, as are the matches in how other values, and the Err
+// of the log, are written. Values with something masked become
+// the strings, maps and slices of what is left.
+func Redact(opts RedactOptions) Hook {
+	keys := opts.Keys
+	if keys == nil {
+		keys = DefaultRedactKeys
+	}
+	redactKeys := make(map[string]bool, len(keys))
+	for _, k := range keys {
+		redactKeys[strings.ToLower(k)] = true
+	}
+	redactString := func(s string) string {
+		for _, p := range opts.Patterns {
+			s = p.ReplaceAllLiteralString(s, Redacted)
+		}
+		return s
+	}
+	redactKey := func(k string) bool {
+		k = strings.ToLower(k)
+		if i := strings.LastIndex(k, "."); i >= 0 && redactKeys[k[i+1:]] {
+			return true
+		}
+		return redactKeys[k]
+	}
+	// redactValue masks the value of the key, if it is one of the
+	// keys, or the values in it that are, or that match a pattern,
+	// looking inside maps, structs and slices, and at the strings
+	// other values are written as. v is got back, unless something
+	// in it was masked.
+	redactValue := func(key string, v interface{}) interface{} {
+		if redactKey(key) {
+			return Redacted
+		}
+		changed := false
+		leaf := func(v interface{}) interface{} {
+			s, ok := v.(string)
+			if !ok {
+				if v == nil || len(opts.Patterns) == 0 {
+					return v
+				}
+				s = fmt.Sprint(v)
+			}
+			if r := redactString(s); r != s {
+				changed = true
+				return r
+			}
+			return v
+		}
+		var redactNested func(v interface{}) interface{}
+		redactNested = func(v interface{}) interface{} {
+			switch v := v.(type) {
+			case map[string]interface{}:
+				for k, nested := range v {
+					if redactKey(k) {
+						v[k] = Redacted
+						changed = true
+					} else {
+						v[k] = redactNested(nested)
+					}
+				}
+			case []interface{}:
+				for i, nested := range v {
+					v[i] = redactNested(nested)
+				}
+			}
+			return v
+		}
+		expanded := redactNested(expand(v, leaf))
+		if !changed {
+			return v
+		}
+		return expanded
+	}
+	return func(l *Log) *Log {
+		redacted := *l
+		redacted.Data = make([]interface{}, len(l.Data))
+		for i, d := range l.Data {
+			redacted.Data[i] = redactValue("", d)
+		}
+		if len(l.Fields) > 0 {
+			redacted.Fields = make(Fields, len(l.Fields))
+			for k, v := range l.Fields {
+				redacted.Fields[k] = redactValue(k, v)
+			}
+		}
+		if l.Err != nil && len(opts.Patterns) > 0 {
+			if s := l.Err.Error(); redactString(s) != s {
+				redacted.Err = errors.New(redactString(s))
+			}
+		}
+		return &redacted
+	}
+}
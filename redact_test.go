@@ -0,0 +1,89 @@
+package slog_test
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/slog"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRedact(t *testing.T) {
+
+	hook := slog.Redact(slog.RedactOptions{
+		Patterns: []*regexp.Regexp{regexp.MustCompile(`\d{4}-\d{4}-\d{4}-\d{4}`)},
+	})
+	fields := slog.Fields{"Password": "hunter2", "user.token": "abc", "user": "mat", "note": "card 1234-5678-9012-3456"}
+	l := &slog.Log{
+		Data:   []interface{}{"( main.go:12 )", "paid with 1234-5678-9012-3456", 42},
+		Fields: fields,
+	}
+
+	redacted := hook(l)
+	require.Equal(t, []interface{}{"( main.go:12 )", "paid with [REDACTED]", 42}, redacted.Data)
+	require.Equal(t, slog.Fields{"Password": "[REDACTED]", "user.token": "[REDACTED]", "user": "mat", "note": "card [REDACTED]"}, redacted.Fields)
+
+	// the log is not changed
+	require.Equal(t, "paid with 1234-5678-9012-3456", l.Data[1])
+	require.Equal(t, "hunter2", fields["Password"])
+
+}
+
+func TestRedactKeys(t *testing.T) {
+
+	hook := slog.Redact(slog.RedactOptions{Keys: []string{"ssn"}})
+	redacted := hook(&slog.Log{Fields: slog.Fields{"ssn": "123", "password": "hunter2"}})
+	require.Equal(t, slog.Fields{"ssn": "[REDACTED]", "password": "hunter2"}, redacted.Fields)
+
+}
+
+type login struct {
+	User     string
+	Password string
+	Card     fmt.Stringer
+}
+
+type card string
+
+func (c card) String() string {
+	return "card " + string(c)
+}
+
+func TestRedactNested(t *testing.T) {
+
+	hook := slog.Redact(slog.RedactOptions{
+		Patterns: []*regexp.Regexp{regexp.MustCompile(`\d{4}-\d{4}-\d{4}-\d{4}`)},
+	})
+	nested := map[string]interface{}{"user": "mat", "auth": map[string]interface{}{"token": "abc", "kind": "bearer"}}
+	l := &slog.Log{
+		Data: []interface{}{"( main.go:12 )", card("1234-5678-9012-3456"), map[string]string{"password": "hunter2"}, 42},
+		Fields: slog.Fields{
+			"request": nested,
+			"login":   login{User: "mat", Password: "hunter2", Card: card("1234-5678-9012-3456")},
+			"cards":   []string{"1234-5678-9012-3456"},
+			"count":   3,
+		},
+		Err: errors.New("charging 1234-5678-9012-3456 failed"),
+	}
+
+	redacted := hook(l)
+	require.Equal(t, []interface{}{"( main.go:12 )", "card [REDACTED]", map[string]interface{}{"password": "[REDACTED]"}, 42}, redacted.Data)
+	require.Equal(t, map[string]interface{}{"user": "mat", "auth": map[string]interface{}{"token": "[REDACTED]", "kind": "bearer"}}, redacted.Fields["request"])
+	require.Equal(t, map[string]interface{}{"User": "mat", "Password": "[REDACTED]", "Card": "card [REDACTED]"}, redacted.Fields["login"])
+	require.Equal(t, []interface{}{"[REDACTED]"}, redacted.Fields["cards"])
+	require.Equal(t, 3, redacted.Fields["count"])
+	require.Equal(t, "charging [REDACTED] failed", redacted.Err.Error())
+
+	// the log is not changed
+	require.Equal(t, "abc", nested["auth"].(map[string]interface{})["token"])
+	require.Equal(t, "charging 1234-5678-9012-3456 failed", l.Err.Error())
+
+	// values without anything to mask are left as they are
+	err := errors.New("declined")
+	redacted = hook(&slog.Log{Fields: slog.Fields{"user": map[string]int{"id": 1}}, Err: err})
+	require.Equal(t, map[string]int{"id": 1}, redacted.Fields["user"])
+	require.Equal(t, err, redacted.Err)
+
+}
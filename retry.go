@@ -0,0 +1,180 @@
+package slog
+
+import (
+	"io"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// RetryPolicy represents how a Retrier retries logs.
+// Zero values get the defaults.
+type RetryPolicy struct {
+	// MaxAttempts is the number of times each log is tried before
+	// it is dropped, defaulting to 5.
+	MaxAttempts int
+	// InitialDelay is the delay before the first retry, defaulting
+	// to 100ms. The delay doubles for each retry after that.
+	InitialDelay time.Duration
+	// MaxDelay is the longest delay between retries, defaulting to
+	// 30s.
+	MaxDelay time.Duration
+	// QueueSize is the number of logs that may be waiting to be
+	// tried before further logs are dropped, defaulting to 1024.
+	QueueSize int
+}
+
+// Retrier is a Reporter that reports logs to an ErrReporter from
+// its own goroutine, retrying them with exponential backoff when
+// it fails. Logs are still reported in order.
+type Retrier struct {
+	m         sync.RWMutex
+	r         Reporter
+	policy    RetryPolicy
+	c         chan *Log
+	closed    bool
+	closing   chan struct{}
+	stopped   chan struct{}
+	dropped   uint64
+	pm        sync.Mutex
+	delivered *sync.Cond // broadcast when a log has been delivered or dropped
+	pending   int        // number of logs queued but not delivered
+}
+
+var _ ErrReporter = (*Retrier)(nil)
+var _ io.Closer = (*Retrier)(nil)
+
+// Retry makes a Retrier that reports to r, retrying as the policy
+// says.
+func Retry(r Reporter, policy RetryPolicy) *Retrier {
+	if policy.MaxAttempts == 0 {
+		policy.MaxAttempts = 5
+	}
+	if policy.InitialDelay == 0 {
+		policy.InitialDelay = 100 * time.Millisecond
+	}
+	if policy.MaxDelay == 0 {
+		policy.MaxDelay = 30 * time.Second
+	}
+	if policy.QueueSize == 0 {
+		policy.QueueSize = 1024
+	}
+	rt := &Retrier{
+		r:       r,
+		policy:  policy,
+		c:       make(chan *Log, policy.QueueSize),
+		closing: make(chan struct{}),
+		stopped: make(chan struct{}),
+	}
+	rt.delivered = sync.NewCond(&rt.pm)
+	go rt.run()
+	return rt
+}
+
+// Log queues the log to be reported, dropping it if the queue is
+// full.
+func (rt *Retrier) Log(l *Log) {
+	rt.Report(l)
+}
+
+// Report queues the log as Log does, getting ErrDropped if it was
+// dropped because the queue is full or the Retrier is closed, so
+// a Fallback can report it elsewhere.
+func (rt *Retrier) Report(l *Log) error {
+	rt.m.RLock()
+	defer rt.m.RUnlock()
+	if rt.closed {
+		atomic.AddUint64(&rt.dropped, 1)
+		return ErrDropped
+	}
+	rt.pm.Lock()
+	rt.pending++
+	rt.pm.Unlock()
+	select {
+	case rt.c <- l:
+		return nil
+	default:
+		atomic.AddUint64(&rt.dropped, 1)
+		rt.done()
+		return ErrDropped
+	}
+}
+
+// done records that a queued log has been delivered or dropped.
+func (rt *Retrier) done() {
+	rt.pm.Lock()
+	rt.pending--
+	rt.delivered.Broadcast()
+	rt.pm.Unlock()
+}
+
+// Flush waits for the logs queued so far to be delivered, or
+// dropped, and then flushes the Reporter, if it is a Flusher.
+func (rt *Retrier) Flush() {
+	rt.pm.Lock()
+	for rt.pending > 0 {
+		rt.delivered.Wait()
+	}
+	rt.pm.Unlock()
+	reporters{rt.r}.Flush()
+}
+
+// Start starts the Reporter, if it is a Starter.
+func (rt *Retrier) Start() {
+	reporters{rt.r}.Start()
+}
+
+// Dropped gets the number of logs dropped, because the queue was
+// full or they failed every attempt.
+func (rt *Retrier) Dropped() uint64 {
+	return atomic.LoadUint64(&rt.dropped)
+}
+
+// Close stops retrying, tries each of the queued logs once more,
+// then closes the Reporter if it is an io.Closer.
+func (rt *Retrier) Close() error {
+	rt.m.Lock()
+	if !rt.closed {
+		rt.closed = true
+		close(rt.closing)
+		close(rt.c)
+	}
+	rt.m.Unlock()
+	<-rt.stopped
+	if c, ok := rt.r.(io.Closer); ok {
+		return c.Close()
+	}
+	return nil
+}
+
+func (rt *Retrier) run() {
+	defer close(rt.stopped)
+	for l := range rt.c {
+		rt.deliver(l)
+		rt.done()
+	}
+}
+
+// deliver reports the log, retrying until it succeeds, it has
+// been tried MaxAttempts times, or the Retrier closes.
+func (rt *Retrier) deliver(l *Log) {
+	delay := rt.policy.InitialDelay
+	for attempt := 1; ; attempt++ {
+		if report(rt.r, l) == nil {
+			return
+		}
+		if attempt == rt.policy.MaxAttempts {
+			break
+		}
+		select {
+		case <-time.After(delay):
+		case <-rt.closing:
+			atomic.AddUint64(&rt.dropped, 1)
+			return
+		}
+		if delay *= 2; delay > rt.policy.MaxDelay {
+			delay = rt.policy.MaxDelay
+		}
+	}
+	atomic.AddUint64(&rt.dropped, 1)
+}
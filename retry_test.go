@@ -0,0 +1,93 @@
+package slog_test
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/slog"
+	"github.com/stretchr/testify/require"
+)
+
+// flakyReporter fails the first failures reports of each log.
+type flakyReporter struct {
+	m        sync.Mutex
+	failures int
+	attempts map[string]int
+	logs     []string
+}
+
+func (r *flakyReporter) Log(l *slog.Log) {
+	r.Report(l)
+}
+
+func (r *flakyReporter) Report(l *slog.Log) error {
+	r.m.Lock()
+	defer r.m.Unlock()
+	msg := l.Data[0].(string)
+	r.attempts[msg]++
+	if r.attempts[msg] <= r.failures {
+		return errors.New("down")
+	}
+	r.logs = append(r.logs, msg)
+	return nil
+}
+
+func TestRetry(t *testing.T) {
+
+	r := &flakyReporter{failures: 2, attempts: make(map[string]int)}
+	rt := slog.Retry(r, slog.RetryPolicy{InitialDelay: time.Millisecond})
+
+	rt.Log(&slog.Log{Data: []interface{}{"one"}})
+	rt.Log(&slog.Log{Data: []interface{}{"two"}})
+	require.Eventually(t, func() bool {
+		r.m.Lock()
+		defer r.m.Unlock()
+		return len(r.logs) == 2
+	}, time.Second, time.Millisecond)
+	require.NoError(t, rt.Close())
+
+	require.Equal(t, []string{"one", "two"}, r.logs)
+	require.Equal(t, map[string]int{"one": 3, "two": 3}, r.attempts)
+	require.Equal(t, uint64(0), rt.Dropped())
+
+}
+
+func TestRetryGivesUp(t *testing.T) {
+
+	r := &flakyReporter{failures: 10, attempts: make(map[string]int)}
+	rt := slog.Retry(r, slog.RetryPolicy{MaxAttempts: 3, InitialDelay: time.Millisecond})
+
+	rt.Log(&slog.Log{Data: []interface{}{"one"}})
+	require.Eventually(t, func() bool {
+		return rt.Dropped() == 1
+	}, time.Second, time.Millisecond)
+	require.NoError(t, rt.Close())
+
+	require.Equal(t, map[string]int{"one": 3}, r.attempts)
+	rt.Log(&slog.Log{Data: []interface{}{"after close"}})
+	require.Equal(t, uint64(2), rt.Dropped())
+
+}
+
+func TestRetryFlush(t *testing.T) {
+
+	r := &flakyReporter{failures: 2, attempts: map[string]int{}}
+	rt := slog.Retry(r, slog.RetryPolicy{InitialDelay: time.Millisecond})
+	rt.Log(&slog.Log{Data: []interface{}{"one"}})
+	rt.Log(&slog.Log{Data: []interface{}{"two"}})
+	rt.Flush()
+	r.m.Lock()
+	require.Equal(t, []string{"one", "two"}, r.logs)
+	r.m.Unlock()
+
+	// logs the Retrier drops go to the fallback
+	secondary := NewTestReporter()
+	f := slog.Fallback(rt, secondary)
+	require.NoError(t, rt.Close())
+	f.Log(&slog.Log{Data: []interface{}{"three"}})
+	require.Equal(t, 1, len(secondary.logs))
+	require.Equal(t, slog.ErrDropped, rt.Report(&slog.Log{}))
+
+}
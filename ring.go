@@ -0,0 +1,72 @@
+package slog
+
+import (
+	"net/http"
+	"sync"
+)
+
+// RingReporter is a Reporter that keeps the most recent logs in
+// memory, so they can be looked at when something goes wrong even
+// if they aren't reported anywhere else.
+type RingReporter struct {
+	m    sync.Mutex
+	logs []*Log
+	next int
+	full bool
+}
+
+var _ Reporter = (*RingReporter)(nil)
+var _ http.Handler = (*RingReporter)(nil)
+
+// NewRingReporter makes a RingReporter that keeps the last n logs.
+func NewRingReporter(n int) *RingReporter {
+	return &RingReporter{logs: make([]*Log, n)}
+}
+
+// Log keeps the log, forgetting the oldest one if there are
+// already n.
+func (r *RingReporter) Log(l *Log) {
+	r.m.Lock()
+	defer r.m.Unlock()
+	if len(r.logs) == 0 {
+		return
+	}
+	r.logs[r.next] = l
+	if r.next++; r.next == len(r.logs) {
+		r.next = 0
+		r.full = true
+	}
+}
+
+// Logs gets the logs kept, oldest first.
+func (r *RingReporter) Logs() []*Log {
+	r.m.Lock()
+	defer r.m.Unlock()
+	if !r.full {
+		return append([]*Log(nil), r.logs[:r.next]...)
+	}
+	logs := make([]*Log, 0, len(r.logs))
+	logs = append(logs, r.logs[r.next:]...)
+	return append(logs, r.logs[:r.next]...)
+}
+
+// ServeHTTP writes the logs kept, oldest first, as lines of text,
+// or as a JSON array of the objects written by NewJSONReporter if
+// the format query parameter is "json".
+func (r *RingReporter) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	logs := r.Logs()
+	if req.URL.Query().Get("format") == "json" {
+		b, err := encodeJSON(logs)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(b)
+		return
+	}
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	for _, l := range logs {
+		w.Write(TextFormatter.Format(l))
+	}
+}
@@ -0,0 +1,41 @@
+package slog_test
+
+import (
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/slog"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRingReporter(t *testing.T) {
+
+	r := slog.NewRingReporter(3)
+	when := time.Date(2015, 1, 2, 3, 4, 5, 0, time.UTC)
+	log := func(msg string) *slog.Log {
+		return &slog.Log{Level: slog.LevelInfo, When: when, Source: []string{"parent"}, Data: []interface{}{msg}}
+	}
+
+	require.Equal(t, 0, len(r.Logs()))
+	r.Log(log("one"))
+	r.Log(log("two"))
+	require.Equal(t, []*slog.Log{log("one"), log("two")}, r.Logs())
+	r.Log(log("three"))
+	r.Log(log("four"))
+	require.Equal(t, []*slog.Log{log("two"), log("three"), log("four")}, r.Logs())
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", "/", nil))
+	require.Equal(t, "2015/01/02 03:04:05 parent: two\n2015/01/02 03:04:05 parent: three\n2015/01/02 03:04:05 parent: four\n", w.Body.String())
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", "/?format=json", nil))
+	require.Equal(t, "application/json", w.Header().Get("Content-Type"))
+	require.JSONEq(t, `[
+		{"level":"info","time":"2015-01-02T03:04:05Z","source":"parent","data":["two"]},
+		{"level":"info","time":"2015-01-02T03:04:05Z","source":"parent","data":["three"]},
+		{"level":"info","time":"2015-01-02T03:04:05Z","source":"parent","data":["four"]}
+	]`, w.Body.String())
+
+}
@@ -0,0 +1,156 @@
+package slog
+
+import (
+	"io"
+	"reflect"
+)
+
+type router struct {
+	f func(*Log) Reporter
+}
+
+// Route makes a Reporter that reports each log to the Reporter f
+// gets for it, or drops it if f gets nil.
+func Route(f func(*Log) Reporter) Reporter {
+	return &router{f: f}
+}
+
+func (r *router) Log(l *Log) {
+	r.Report(l)
+}
+
+func (r *router) Report(l *Log) error {
+	if to := r.f(l); to != nil {
+		return report(to, l)
+	}
+	return nil
+}
+
+// Rule represents which logs are routed to a Reporter by Rules.
+// A log matches the rule if it matches all of Level, Source,
+// Fields and Hints.
+type Rule struct {
+	// Level matches logs at the level, or more severe. LevelInvalid
+	// matches logs at any level.
+	Level Level
+	// Source matches logs from the source, like "parent>billing",
+	// and its children, written with the SourceSep of the logs.
+	// Empty matches logs from any source.
+	Source string
+	// Fields matches logs with each of the fields, with a deeply
+	// equal value.
+	Fields Fields
+	// Hints matches logs with each of the hints, with a deeply
+	// equal value, e.g. Hints{"alert": true}.
+	Hints Hints
+	// Reporter is where the logs that match the rule are reported.
+	Reporter Reporter
+}
+
+type rule struct {
+	Rule
+	source *sourceSplit // nil if Source is empty
+}
+
+func (r *rule) match(l *Log) bool {
+	if r.Level != LevelInvalid && l.Level > r.Level {
+		return false
+	}
+	if r.source != nil {
+		parts := r.source.split(l.SourceSep)
+		if len(l.Source) < len(parts) {
+			return false
+		}
+		for i, part := range parts {
+			if l.Source[i] != part {
+				return false
+			}
+		}
+	}
+	for k, v := range r.Fields {
+		if f, ok := l.Fields[k]; !ok || !reflect.DeepEqual(f, v) {
+			return false
+		}
+	}
+	for k, v := range r.Hints {
+		if h, ok := l.Hints[k]; !ok || !reflect.DeepEqual(h, v) {
+			return false
+		}
+	}
+	return true
+}
+
+type rules struct {
+	rules []rule
+	rest  Reporter
+}
+
+// Rules makes a Reporter that reports each log to the Reporter of
+// the first of the rules it matches, or to rest if it doesn't match
+// any, e.g. to keep the logs of billing in their own sink:
+//
+//	slog.Rules(slog.Stdout, slog.Rule{Source: "parent>billing", Reporter: pciReporter})
+//
+// Rest may be nil to drop them. Closing, flushing or starting the
+// Reporter does the same to each of the Reporters.
+func Rules(rest Reporter, rs ...Rule) Reporter {
+	r := &rules{rules: make([]rule, len(rs)), rest: rest}
+	for i, rl := range rs {
+		r.rules[i] = rule{Rule: rl}
+		if rl.Source != "" {
+			r.rules[i].source = newSourceSplit(rl.Source)
+		}
+	}
+	return r
+}
+
+var _ ErrReporter = (*rules)(nil)
+var _ io.Closer = (*rules)(nil)
+
+func (r *rules) Log(l *Log) {
+	r.Report(l)
+}
+
+func (r *rules) Report(l *Log) error {
+	for i := range r.rules {
+		if r.rules[i].match(l) {
+			return report(r.rules[i].Reporter, l)
+		}
+	}
+	if r.rest != nil {
+		return report(r.rest, l)
+	}
+	return nil
+}
+
+// reporters gets each of the Reporters, once.
+func (r *rules) reporters() reporters {
+	var rs reporters
+	add := func(to Reporter) {
+		for _, other := range rs {
+			if sameReporter(to, other) {
+				return
+			}
+		}
+		rs = append(rs, to)
+	}
+	for _, rl := range r.rules {
+		add(rl.Reporter)
+	}
+	if r.rest != nil {
+		add(r.rest)
+	}
+	return rs
+}
+
+func (r *rules) Close() error {
+	return r.reporters().Close()
+}
+
+func (r *rules) Flush() {
+	r.reporters().Flush()
+}
+
+func (r *rules) Start() {
+	r.reporters().Start()
+}
@@ -0,0 +1,59 @@
+package slog_test
+
+import (
+	"io"
+	"testing"
+
+	"github.com/stretchr/slog"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRoute(t *testing.T) {
+
+	a, b := NewTestReporter(), NewTestReporter()
+	r := slog.Route(func(l *slog.Log) slog.Reporter {
+		switch l.Source.Leaf() {
+		case "a":
+			return a
+		case "b":
+			return b
+		}
+		return nil
+	})
+	r.Log(&slog.Log{Source: []string{"parent", "a"}})
+	r.Log(&slog.Log{Source: []string{"parent", "b"}})
+	r.Log(&slog.Log{Source: []string{"parent", "c"}})
+
+	require.Equal(t, 1, len(a.logs))
+	require.Equal(t, 1, len(b.logs))
+
+}
+
+func TestRules(t *testing.T) {
+
+	billing, severe, rest := NewTestReporter(), NewTestReporter(), NewTestReporter()
+	r := slog.Rules(rest,
+		slog.Rule{Source: "parent>billing", Reporter: billing},
+		slog.Rule{Level: slog.LevelWarn, Fields: slog.Fields{"team": "ops"}, Reporter: severe},
+	)
+	logs := []*slog.Log{
+		{Level: slog.LevelInfo, Source: []string{"parent", "billing"}},
+		{Level: slog.LevelErr, Source: []string{"parent", "billing", "cards"}},
+		{Level: slog.LevelInfo, Source: []string{"parent", "billingx"}},
+		{Level: slog.LevelErr, Source: []string{"parent"}, Fields: slog.Fields{"team": "ops"}},
+		{Level: slog.LevelInfo, Source: []string{"parent"}, Fields: slog.Fields{"team": "ops"}},
+		{Level: slog.LevelErr, Source: []string{"parent"}, Fields: slog.Fields{"team": "dev"}},
+	}
+	for _, l := range logs {
+		r.Log(l)
+	}
+
+	require.Equal(t, []*slog.Log{logs[0], logs[1]}, billing.logs)
+	require.Equal(t, []*slog.Log{logs[3]}, severe.logs)
+	require.Equal(t, []*slog.Log{logs[2], logs[4], logs[5]}, rest.logs)
+
+	c := &closeCounter{TestReporter: NewTestReporter()}
+	require.NoError(t, slog.Rules(c, slog.Rule{Reporter: c}).(io.Closer).Close())
+	require.Equal(t, 1, c.closed)
+
+}
@@ -0,0 +1,122 @@
+package slog
+
+import (
+	"io"
+	"sync"
+	"time"
+)
+
+// Sampling represents how a Sampler samples logs of one level.
+type Sampling struct {
+	// First is the number of logs with the same message that are
+	// reported each tick.
+	First int
+	// Thereafter is how many logs with the same message there are
+	// for each one reported after the first ones. Zero drops them
+	// all.
+	Thereafter int
+}
+
+type sampleKey struct {
+	level Level
+	msg   string
+}
+
+// Sampler is a Reporter that reports a sample of repeated logs
+// to another Reporter, so high volume logs don't overwhelm it.
+// Logs are the same if they have the same level, source and
+// Data.
+type Sampler struct {
+	m       sync.Mutex
+	r       Reporter
+	tick    time.Duration
+	levels  map[Level]Sampling
+	clock   Clock
+	start   time.Time
+	counts  map[sampleKey]int
+	dropped uint64
+}
+
+var _ Reporter = (*Sampler)(nil)
+var _ io.Closer = (*Sampler)(nil)
+
+// NewSampler makes a Sampler that reports to r, sampling each
+// of the levels as they are given, and counting from zero
+// again every tick.
+// Logs of levels that aren't given are all reported.
+func NewSampler(r Reporter, tick time.Duration, levels map[Level]Sampling) *Sampler {
+	return &Sampler{
+		r:      r,
+		tick:   tick,
+		levels: levels,
+		clock:  SystemClock,
+		counts: make(map[sampleKey]int),
+	}
+}
+
+// Log reports the log if it is in the sample.
+func (s *Sampler) Log(l *Log) {
+	if s.sample(l) {
+		s.r.Log(l)
+	}
+}
+
+func (s *Sampler) sample(l *Log) bool {
+	sampling, ok := s.levels[l.Level]
+	if !ok {
+		return true
+	}
+	key := sampleKey{
+		level: l.Level,
+		msg:   l.SourceString() + ":" + sprint(l.Data),
+	}
+	s.m.Lock()
+	defer s.m.Unlock()
+	if now := s.clock.Now(); now.Sub(s.start) >= s.tick {
+		s.start = now
+		s.counts = make(map[sampleKey]int)
+	}
+	n := s.counts[key] + 1
+	s.counts[key] = n
+	if n <= sampling.First {
+		return true
+	}
+	if sampling.Thereafter > 0 && (n-sampling.First)%sampling.Thereafter == 0 {
+		return true
+	}
+	s.dropped++
+	return false
+}
+
+// SetClock sets the Clock used to tell when each tick starts,
+// which is SystemClock by default.
+func (s *Sampler) SetClock(c Clock) {
+	s.m.Lock()
+	s.clock = c
+	s.m.Unlock()
+}
+
+// Dropped gets the number of logs that weren't in the sample.
+func (s *Sampler) Dropped() uint64 {
+	s.m.Lock()
+	defer s.m.Unlock()
+	return s.dropped
+}
+
+// Flush flushes the Reporter, if it is a Flusher.
+func (s *Sampler) Flush() {
+	reporters{s.r}.Flush()
+}
+
+// Start starts the Reporter, if it is a Starter.
+func (s *Sampler) Start() {
+	reporters{s.r}.Start()
+}
+
+// Close closes the Reporter, if it is an io.Closer.
+func (s *Sampler) Close() error {
+	if c, ok := s.r.(io.Closer); ok {
+		return c.Close()
+	}
+	return nil
+}
@@ -0,0 +1,47 @@
+package slog_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/slog"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSampler(t *testing.T) {
+
+	r := NewTestReporter()
+	s := slog.NewSampler(r, time.Second, map[slog.Level]slog.Sampling{
+		slog.LevelInfo: {First: 2, Thereafter: 3},
+		slog.LevelWarn: {First: 1},
+	})
+	clock := &testClock{now: time.Date(2015, 1, 1, 0, 0, 0, 0, time.UTC)}
+	slog.SetSamplerNow(s, clock.Now)
+
+	for n := 0; n < 10; n++ {
+		s.Log(&slog.Log{Level: slog.LevelInfo, Source: []string{"parent"}, Data: []interface{}{"cache miss"}})
+		s.Log(&slog.Log{Level: slog.LevelWarn, Source: []string{"parent"}, Data: []interface{}{"slow"}})
+		s.Log(&slog.Log{Level: slog.LevelErr, Source: []string{"parent"}, Data: []interface{}{"failed"}})
+	}
+	s.Log(&slog.Log{Level: slog.LevelInfo, Source: []string{"parent"}, Data: []interface{}{"cache hit"}})
+	s.Log(&slog.Log{Level: slog.LevelInfo, Source: []string{"other"}, Data: []interface{}{"cache miss"}})
+
+	counts := map[string]int{}
+	for _, l := range r.logs {
+		counts[l.Source[0]+":"+l.Data[0].(string)]++
+	}
+	require.Equal(t, map[string]int{
+		"parent:cache miss": 4, // 1, 2, 5 and 8
+		"parent:slow":       1,
+		"parent:failed":     10,
+		"parent:cache hit":  1,
+		"other:cache miss":  1,
+	}, counts)
+	require.Equal(t, uint64(15), s.Dropped())
+
+	// counting starts again each tick
+	clock.Add(time.Second)
+	s.Log(&slog.Log{Level: slog.LevelWarn, Source: []string{"parent"}, Data: []interface{}{"slow"}})
+	require.Equal(t, "slow", r.logs[len(r.logs)-1].Data[0])
+
+}
@@ -0,0 +1,53 @@
+package slog
+
+import (
+	"fmt"
+	"io"
+)
+
+// secret is a value made by Secret. It is a func so that even
+// printing it with reflection, as fmt does for unexported fields,
+// doesn't show the value.
+type secret func() interface{}
+
+// Secret gets a value for the Data or Fields of a log, or a field of
+// a struct that is logged, that is always written as Redacted, by
+// every Reporter and however it is formatted:
+//
+//	l.Info("calling api", slog.Fields{"key": slog.Secret(apiKey)})
+//
+// For types that should never be logged, implement LogValuer to
+// get Redacted, or a Secret, instead.
+func Secret(v interface{}) interface{} {
+	return secret(func() interface{} { return v })
+}
+
+var (
+	_ LogValuer     = secret(nil)
+	_ fmt.Formatter = secret(nil)
+)
+
+// LogValue gets Redacted.
+func (s secret) LogValue() interface{} {
+	return Redacted
+}
+
+// Format writes Redacted, whatever the verb.
+func (s secret) Format(f fmt.State, verb rune) {
+	io.WriteString(f, Redacted)
+}
+
+// String gets Redacted.
+func (s secret) String() string {
+	return Redacted
+}
+
+// MarshalJSON gets Redacted as a JSON string.
+func (s secret) MarshalJSON() ([]byte, error) {
+	return []byte(`"` + Redacted + `"`), nil
+}
+
+// MarshalText gets Redacted.
+func (s secret) MarshalText() ([]byte, error) {
+	return []byte(Redacted), nil
+}
@@ -0,0 +1,49 @@
+package slog_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/slog"
+	"github.com/stretchr/testify/require"
+)
+
+type credentials struct {
+	User  string
+	Key   interface{}
+	token interface{}
+}
+
+func TestSecret(t *testing.T) {
+
+	for _, f := range []slog.Formatter{slog.TextFormatter, slog.JSONFormatter, slog.LogfmtFormatter} {
+		l := slog.New("parent", slog.LevelInfo)
+		var buf strings.Builder
+		l.SetReporter(slog.NewWriterReporter(&buf, f))
+		l.SetSync(true)
+
+		creds := credentials{User: "mat", Key: slog.Secret("abc123"), token: slog.Secret("def456")}
+		l.Info("calling api", slog.Secret("abc123"), creds, slog.Fields{"key": slog.Secret("abc123")})
+		require.Contains(t, buf.String(), slog.Redacted)
+		require.Contains(t, buf.String(), "mat")
+		require.NotContains(t, buf.String(), "abc123")
+		require.NotContains(t, buf.String(), "def456")
+	}
+
+}
+
+func TestSecretFormat(t *testing.T) {
+
+	s := slog.Secret("abc123")
+	creds := credentials{User: "mat", Key: s, token: s}
+	for _, verb := range []string{"%v", "%+v", "%#v", "%s", "%q", "%x"} {
+		require.NotContains(t, fmt.Sprintf(verb, s), "abc123", verb)
+		require.NotContains(t, fmt.Sprintf(verb, creds), "abc123", verb)
+	}
+	b, err := json.Marshal(creds)
+	require.NoError(t, err)
+	require.Equal(t, `{"User":"mat","Key":"[REDACTED]"}`, string(b))
+
+}
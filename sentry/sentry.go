@@ -0,0 +1,378 @@
+// Package sentry provides a slog.Reporter that sends logs to
+// Sentry as events.
+package sentry
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/stretchr/slog"
+)
+
+// DefaultQueueSize is the number of events a Reporter queues,
+// when Options.QueueSize is zero.
+const DefaultQueueSize = 100
+
+// The slog.Hints a Reporter understands.
+const (
+	// FingerprintHint sets the fingerprint Sentry groups the event
+	// by, as a string or a []string, instead of the Fingerprint of
+	// the log.
+	FingerprintHint = "sentry.fingerprint"
+	// LevelHint sets the slog.Level the log is sent, or not sent,
+	// at, instead of its own.
+	LevelHint = "sentry.level"
+)
+
+// Options represents the options for a Reporter.
+type Options struct {
+	// Level is the least severe level that is sent, defaulting to
+	// slog.LevelErr.
+	Level slog.Level
+	// Environment and Release are sent with each event, if set.
+	Environment string
+	Release     string
+	// QueueSize is the number of events queued before further
+	// logs are dropped, defaulting to DefaultQueueSize.
+	QueueSize int
+	// Client is the client used to send events, defaulting to
+	// one whose requests time out after ten seconds, so Flush
+	// and Close can't wait forever.
+	Client *http.Client
+}
+
+// Reporter is a slog.Reporter that sends logs to Sentry.
+// The source of each log becomes the logger and the "source" tag,
+// the Data becomes the message, the Fields and Data become extra
+// context, and the Err and Stack of the log become the exception.
+// Events are queued and sent from a goroutine, so logging never
+// waits for Sentry.
+type Reporter struct {
+	m        sync.RWMutex
+	opts     Options
+	endpoint string
+	auth     string
+	events   chan []byte
+	closed   bool
+	stopped  chan struct{}
+	dropped  uint64
+	pm       sync.Mutex
+	sent     *sync.Cond // broadcast when an event has been sent
+	pending  int        // number of events queued but not sent
+}
+
+var _ slog.Reporter = (*Reporter)(nil)
+var _ io.Closer = (*Reporter)(nil)
+
+// New makes a Reporter that sends events to the project of the
+// Sentry DSN, e.g. "https://key@o0.ingest.sentry.io/123".
+func New(dsn string, opts Options) (*Reporter, error) {
+	u, err := url.Parse(dsn)
+	if err != nil {
+		return nil, err
+	}
+	if u.User == nil || u.User.Username() == "" {
+		return nil, errors.New("sentry: DSN has no key")
+	}
+	i := strings.LastIndex(u.Path, "/")
+	project := u.Path[i+1:]
+	if project == "" {
+		return nil, errors.New("sentry: DSN has no project")
+	}
+	if opts.Level == slog.LevelInvalid {
+		opts.Level = slog.LevelErr
+	}
+	if opts.QueueSize == 0 {
+		opts.QueueSize = DefaultQueueSize
+	}
+	if opts.Client == nil {
+		opts.Client = &http.Client{Timeout: 10 * time.Second}
+	}
+	r := &Reporter{
+		opts:     opts,
+		endpoint: fmt.Sprintf("%s://%s%s/api/%s/envelope/", u.Scheme, u.Host, u.Path[:i], project),
+		auth:     "Sentry sentry_version=7, sentry_client=slog/1.0, sentry_key=" + u.User.Username(),
+		events:   make(chan []byte, opts.QueueSize),
+		stopped:  make(chan struct{}),
+	}
+	r.sent = sync.NewCond(&r.pm)
+	go r.send()
+	return r, nil
+}
+
+// Log queues the log to be sent, if it is at the level of the
+// Reporter or more severe.
+func (r *Reporter) Log(l *slog.Log) {
+	if levelOf(l) > r.opts.Level {
+		return
+	}
+	envelope, err := r.envelope(l)
+	if err != nil {
+		atomic.AddUint64(&r.dropped, 1)
+		return
+	}
+	r.m.RLock()
+	defer r.m.RUnlock()
+	if r.closed {
+		atomic.AddUint64(&r.dropped, 1)
+		return
+	}
+	r.pm.Lock()
+	r.pending++
+	r.pm.Unlock()
+	select {
+	case r.events <- envelope:
+	default:
+		atomic.AddUint64(&r.dropped, 1)
+		r.doneSending()
+	}
+}
+
+// doneSending records that a queued event has been sent, or
+// dropped.
+func (r *Reporter) doneSending() {
+	r.pm.Lock()
+	r.pending--
+	r.sent.Broadcast()
+	r.pm.Unlock()
+}
+
+// Flush waits for the events queued so far to be sent.
+func (r *Reporter) Flush() {
+	r.pm.Lock()
+	for r.pending > 0 {
+		r.sent.Wait()
+	}
+	r.pm.Unlock()
+}
+
+// Dropped gets the number of logs that couldn't be sent, because
+// the queue was full or Sentry failed.
+func (r *Reporter) Dropped() uint64 {
+	return atomic.LoadUint64(&r.dropped)
+}
+
+// Close sends the queued events, and stops the Reporter.
+func (r *Reporter) Close() error {
+	r.m.Lock()
+	if !r.closed {
+		r.closed = true
+		close(r.events)
+	}
+	r.m.Unlock()
+	<-r.stopped
+	return nil
+}
+
+func (r *Reporter) send() {
+	defer close(r.stopped)
+	for envelope := range r.events {
+		if !r.post(envelope) {
+			atomic.AddUint64(&r.dropped, 1)
+		}
+		r.doneSending()
+	}
+}
+
+// post posts the envelope to Sentry, getting whether it was
+// accepted.
+func (r *Reporter) post(envelope []byte) bool {
+	req, err := http.NewRequest("POST", r.endpoint, bytes.NewReader(envelope))
+	if err != nil {
+		return false
+	}
+	req.Header.Set("Content-Type", "application/x-sentry-envelope")
+	req.Header.Set("X-Sentry-Auth", r.auth)
+	res, err := r.opts.Client.Do(req)
+	if err != nil {
+		return false
+	}
+	io.Copy(io.Discard, res.Body)
+	res.Body.Close()
+	return res.StatusCode < 300
+}
+
+// levels maps levels to Sentry levels.
+var levels = map[slog.Level]string{
+	slog.LevelErr:   "error",
+	slog.LevelWarn:  "warning",
+	slog.LevelInfo:  "info",
+	slog.LevelDebug: "debug",
+	slog.LevelTrace: "debug",
+}
+
+type event struct {
+	EventID     string                 `json:"event_id"`
+	Timestamp   string                 `json:"timestamp"`
+	Level       string                 `json:"level"`
+	Logger      string                 `json:"logger"`
+	Platform    string                 `json:"platform"`
+	Environment string                 `json:"environment,omitempty"`
+	Release     string                 `json:"release,omitempty"`
+	Message     message                `json:"message"`
+	Tags        map[string]string      `json:"tags"`
+	Extra       map[string]interface{} `json:"extra,omitempty"`
+	Exception   *exceptions            `json:"exception,omitempty"`
+	Threads     *threads               `json:"threads,omitempty"`
+	Fingerprint []string               `json:"fingerprint,omitempty"`
+}
+
+type message struct {
+	Formatted string `json:"formatted"`
+}
+
+type exceptions struct {
+	Values []exception `json:"values"`
+}
+
+type exception struct {
+	Type       string      `json:"type"`
+	Value      string      `json:"value"`
+	Stacktrace *stacktrace `json:"stacktrace,omitempty"`
+}
+
+type threads struct {
+	Values []thread `json:"values"`
+}
+
+type thread struct {
+	Current    bool        `json:"current"`
+	Stacktrace *stacktrace `json:"stacktrace"`
+}
+
+type stacktrace struct {
+	Frames []frame `json:"frames"`
+}
+
+type frame struct {
+	Function string `json:"function"`
+	Filename string `json:"filename"`
+	AbsPath  string `json:"abs_path"`
+	Lineno   int    `json:"lineno"`
+}
+
+// levelOf gets the level of the log, or the one given as its
+// LevelHint.
+func levelOf(l *slog.Log) slog.Level {
+	if h, ok := l.Hints[LevelHint].(slog.Level); ok {
+		return h
+	}
+	return l.Level
+}
+
+// envelope makes the Sentry envelope holding the log as an event.
+func (r *Reporter) envelope(l *slog.Log) ([]byte, error) {
+	id := make([]byte, 16)
+	if _, err := rand.Read(id); err != nil {
+		return nil, err
+	}
+	level, ok := levels[levelOf(l)]
+	if !ok {
+		level = "info"
+	}
+	source := l.SourceString()
+	e := &event{
+		EventID:     hex.EncodeToString(id),
+		Timestamp:   l.When.UTC().Format(time.RFC3339Nano),
+		Level:       level,
+		Logger:      source,
+		Platform:    "go",
+		Environment: r.opts.Environment,
+		Release:     r.opts.Release,
+		Tags:        map[string]string{"source": source},
+	}
+	if loc := l.Location(); loc != "" {
+		e.Extra = map[string]interface{}{"location": loc}
+	}
+	e.Message.Formatted = l.Message()
+	for k, v := range l.Fields {
+		if e.Extra == nil {
+			e.Extra = make(map[string]interface{}, len(l.Fields))
+		}
+		e.Extra[k] = extraValue(v)
+	}
+	if l.Fingerprint != "" {
+		e.Fingerprint = []string{l.Fingerprint}
+	}
+	if h, ok := l.Hint(FingerprintHint); ok {
+		switch h := h.(type) {
+		case string:
+			e.Fingerprint = []string{h}
+		case []string:
+			e.Fingerprint = h
+		}
+	}
+	st := parseStack(l.Stack)
+	if l.Err != nil {
+		e.Exception = &exceptions{Values: []exception{{
+			Type:       fmt.Sprintf("%T", l.Err),
+			Value:      l.Err.Error(),
+			Stacktrace: st,
+		}}}
+	} else if st != nil {
+		e.Threads = &threads{Values: []thread{{Current: true, Stacktrace: st}}}
+	}
+	body, err := json.Marshal(e)
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "{\"event_id\":%q}\n", e.EventID)
+	fmt.Fprintf(&buf, "{\"type\":\"event\",\"length\":%d}\n", len(body))
+	buf.Write(body)
+	buf.WriteByte('\n')
+	return buf.Bytes(), nil
+}
+
+// extraValue gets v, or its fmt representation if it won't
+// marshal to JSON.
+func extraValue(v interface{}) interface{} {
+	if err, ok := v.(error); ok {
+		return err.Error()
+	}
+	if _, err := json.Marshal(v); err != nil {
+		return fmt.Sprint(v)
+	}
+	return v
+}
+
+// parseStack parses a slog.Log Stack into Sentry frames, which
+// are ordered from the outermost call.
+func parseStack(s string) *stacktrace {
+	lines := strings.Split(strings.TrimSpace(s), "\n")
+	var frames []frame
+	for i := 0; i+1 < len(lines); i += 2 {
+		location := strings.TrimSpace(lines[i+1])
+		colon := strings.LastIndex(location, ":")
+		if colon < 0 {
+			continue
+		}
+		line, _ := strconv.Atoi(location[colon+1:])
+		file := location[:colon]
+		frames = append(frames, frame{
+			Function: strings.TrimSuffix(lines[i], "()"),
+			Filename: file[strings.LastIndex(file, "/")+1:],
+			AbsPath:  file,
+			Lineno:   line,
+		})
+	}
+	if len(frames) == 0 {
+		return nil
+	}
+	for i, j := 0, len(frames)-1; i < j; i, j = i+1, j-1 {
+		frames[i], frames[j] = frames[j], frames[i]
+	}
+	return &stacktrace{Frames: frames}
+}
@@ -0,0 +1,181 @@
+package sentry_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/slog"
+	"github.com/stretchr/slog/sentry"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReporter(t *testing.T) {
+
+	var m sync.Mutex
+	var paths, auths []string
+	var envelopes [][]byte
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		m.Lock()
+		defer m.Unlock()
+		paths = append(paths, r.URL.Path)
+		auths = append(auths, r.Header.Get("X-Sentry-Auth"))
+		envelopes = append(envelopes, body)
+	}))
+	defer s.Close()
+
+	r, err := sentry.New(strings.Replace(s.URL, "http://", "http://abc@", 1)+"/42", sentry.Options{Release: "v1"})
+	require.NoError(t, err)
+
+	when := time.Date(2015, 1, 2, 3, 4, 5, 0, time.UTC)
+	r.Log(&slog.Log{
+		Level:       slog.LevelErr,
+		When:        when,
+		Source:      []string{"parent", "child"},
+		Data:        []interface{}{"save", "failed"},
+		Fields:      slog.Fields{"id": 1},
+		Err:         errors.New("disk full"),
+		Stack:       "main.save()\n\t/src/save.go:30\nmain.main()\n\t/src/main.go:12\n",
+		Fingerprint: "1f2e",
+	})
+	r.Log(&slog.Log{Level: slog.LevelWarn, When: when, Source: []string{"parent"}, Data: []interface{}{"ignored"}})
+	require.NoError(t, r.Close())
+
+	require.Equal(t, 1, len(envelopes))
+	require.Equal(t, "/api/42/envelope/", paths[0])
+	require.Equal(t, "Sentry sentry_version=7, sentry_client=slog/1.0, sentry_key=abc", auths[0])
+	lines := bytes.Split(bytes.TrimSpace(envelopes[0]), []byte("\n"))
+	require.Equal(t, 3, len(lines))
+
+	var event map[string]interface{}
+	require.NoError(t, json.Unmarshal(lines[2], &event))
+	id := event["event_id"].(string)
+	require.Equal(t, 32, len(id))
+	require.JSONEq(t, `{"event_id":"`+id+`"}`, string(lines[0]))
+	delete(event, "event_id")
+	b, _ := json.Marshal(event)
+	require.JSONEq(t, `{
+		"timestamp": "2015-01-02T03:04:05Z",
+		"level": "error",
+		"logger": "parent>child",
+		"platform": "go",
+		"release": "v1",
+		"message": {"formatted": "save failed"},
+		"tags": {"source": "parent>child"},
+		"extra": {"id": 1},
+		"fingerprint": ["1f2e"],
+		"exception": {"values": [{
+			"type": "*errors.errorString",
+			"value": "disk full",
+			"stacktrace": {"frames": [
+				{"function": "main.main", "filename": "main.go", "abs_path": "/src/main.go", "lineno": 12},
+				{"function": "main.save", "filename": "save.go", "abs_path": "/src/save.go", "lineno": 30}
+			]}
+		}]}
+	}`, string(b))
+
+	r.Log(&slog.Log{Level: slog.LevelErr, When: when})
+	require.Equal(t, uint64(1), r.Dropped())
+
+	// the location of logs from a Logger is extra
+	r, err = sentry.New(strings.Replace(s.URL, "http://", "http://abc@", 1)+"/42", sentry.Options{})
+	require.NoError(t, err)
+	l := slog.New("parent", slog.LevelErr)
+	l.SetSync(true)
+	l.SetReporter(r)
+	l.Err("save", "failed")
+	require.NoError(t, r.Close())
+	require.Equal(t, 2, len(envelopes))
+	lines = bytes.Split(bytes.TrimSpace(envelopes[1]), []byte("\n"))
+	event = nil
+	require.NoError(t, json.Unmarshal(lines[2], &event))
+	require.Equal(t, "save failed", event["message"].(map[string]interface{})["formatted"])
+	require.Contains(t, event["extra"].(map[string]interface{})["location"], "sentry_test.go:")
+
+}
+
+func TestNewBadDSN(t *testing.T) {
+
+	_, err := sentry.New("https://o0.ingest.sentry.io/42", sentry.Options{})
+	require.Error(t, err)
+	_, err = sentry.New("https://abc@o0.ingest.sentry.io/", sentry.Options{})
+	require.Error(t, err)
+
+}
+
+func TestReporterHints(t *testing.T) {
+
+	var m sync.Mutex
+	var events []map[string]interface{}
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		lines := bytes.Split(bytes.TrimSpace(body), []byte("\n"))
+		var event map[string]interface{}
+		json.Unmarshal(lines[2], &event)
+		m.Lock()
+		defer m.Unlock()
+		events = append(events, event)
+	}))
+	defer s.Close()
+
+	r, err := sentry.New(strings.Replace(s.URL, "http://", "http://abc@", 1)+"/42", sentry.Options{})
+	require.NoError(t, err)
+
+	r.Log(&slog.Log{
+		Level:       slog.LevelErr,
+		Data:        []interface{}{"grouped"},
+		Fingerprint: "1f2e",
+		Hints:       slog.Hints{sentry.FingerprintHint: []string{"payments", "timeout"}},
+	})
+	r.Log(&slog.Log{
+		Level: slog.LevelWarn,
+		Data:  []interface{}{"escalated"},
+		Hints: slog.Hints{sentry.LevelHint: slog.LevelErr},
+	})
+	r.Log(&slog.Log{
+		Level: slog.LevelErr,
+		Data:  []interface{}{"quietened"},
+		Hints: slog.Hints{sentry.LevelHint: slog.LevelInfo},
+	})
+	require.NoError(t, r.Close())
+
+	require.Equal(t, 2, len(events))
+	require.Equal(t, []interface{}{"payments", "timeout"}, events[0]["fingerprint"])
+	require.Equal(t, "escalated", events[1]["message"].(map[string]interface{})["formatted"])
+	require.Equal(t, "error", events[1]["level"])
+
+}
+
+func TestReporterFlush(t *testing.T) {
+
+	var m sync.Mutex
+	var envelopes int
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(10 * time.Millisecond)
+		m.Lock()
+		envelopes++
+		m.Unlock()
+	}))
+	defer s.Close()
+
+	r, err := sentry.New(strings.Replace(s.URL, "http://", "http://abc@", 1)+"/42", sentry.Options{})
+	require.NoError(t, err)
+	defer r.Close()
+	r.Log(&slog.Log{Level: slog.LevelErr, Data: []interface{}{"one"}})
+	r.Log(&slog.Log{Level: slog.LevelErr, Data: []interface{}{"two"}})
+	r.Flush()
+
+	// the events have been sent without closing the Reporter
+	m.Lock()
+	require.Equal(t, 2, envelopes)
+	m.Unlock()
+
+}
@@ -0,0 +1,89 @@
+package slog
+
+import (
+	"fmt"
+	"os"
+	"os/signal"
+	"sync"
+)
+
+// CycleLevelOnSignal changes the level of l each time the process
+// gets one of the signals, to the one after its current level in
+// levels, going back to the first after the last, e.g.
+//
+//	slog.CycleLevelOnSignal(root, []slog.Level{slog.LevelWarn, slog.LevelInfo, slog.LevelDebug}, syscall.SIGUSR1)
+//
+// Each change is logged at LevelWarn.
+// The returned cancel func stops listening for the signals.
+// If levels is empty there is nothing to cycle through, so it
+// doesn't listen for the signals at all, and cancel does nothing.
+func CycleLevelOnSignal(l RootLogger, levels []Level, sigs ...os.Signal) (cancel func()) {
+	if len(levels) == 0 {
+		return func() {}
+	}
+	c := make(chan os.Signal, 1)
+	done := make(chan struct{})
+	signal.Notify(c, sigs...)
+	go func() {
+		for {
+			select {
+			case <-c:
+				level := nextLevel(l.Level(), levels)
+				l.SetLevel(level)
+				l.Warn("level changed to", level)
+			case <-done:
+				return
+			}
+		}
+	}()
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			signal.Stop(c)
+			close(done)
+		})
+	}
+}
+
+// ReopenOnSignal reopens the file of f each time the process gets
+// one of the signals, for use with logrotate(8), e.g.
+//
+//	slog.ReopenOnSignal(root, r, syscall.SIGHUP)
+//
+// Errors reopening the file are logged to l.
+// The returned cancel func stops listening for the signals.
+func ReopenOnSignal(l Logger, f *FileReporter, sigs ...os.Signal) (cancel func()) {
+	c := make(chan os.Signal, 1)
+	done := make(chan struct{})
+	signal.Notify(c, sigs...)
+	go func() {
+		for {
+			select {
+			case <-c:
+				if err := f.Reopen(); err != nil {
+					l.Err(fmt.Errorf("slog: reopening %s: %w", f.path, err))
+				}
+			case <-done:
+				return
+			}
+		}
+	}()
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			signal.Stop(c)
+			close(done)
+		})
+	}
+}
+
+// nextLevel gets the level after level in levels, or the first
+// if level isn't one of them.
+func nextLevel(level Level, levels []Level) Level {
+	for i, l := range levels {
+		if l == level {
+			return levels[(i+1)%len(levels)]
+		}
+	}
+	return levels[0]
+}
@@ -0,0 +1,82 @@
+//go:build unix
+
+package slog_test
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/pat/stop"
+	"github.com/stretchr/slog"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCycleLevelOnSignal(t *testing.T) {
+
+	l := slog.New("parent", slog.LevelWarn)
+	defer func() {
+		l.Stop(stop.NoWait)
+		<-l.StopChan()
+	}()
+	r := NewTestReporter()
+	l.SetReporter(r)
+
+	cancel := slog.CycleLevelOnSignal(l, []slog.Level{slog.LevelWarn, slog.LevelInfo, slog.LevelDebug}, syscall.SIGUSR1)
+	defer cancel()
+
+	for _, want := range []slog.Level{slog.LevelInfo, slog.LevelDebug, slog.LevelWarn} {
+		require.NoError(t, syscall.Kill(syscall.Getpid(), syscall.SIGUSR1))
+		require.Eventually(t, func() bool {
+			return l.Level() == want
+		}, time.Second, time.Millisecond)
+	}
+
+	cancel()
+	cancel()
+
+	// no levels is a no-op
+	slog.CycleLevelOnSignal(l, nil, syscall.SIGUSR1)()
+	require.Equal(t, slog.LevelWarn, l.Level())
+
+}
+
+func TestReopenOnSignal(t *testing.T) {
+
+	l := slog.New("parent", slog.LevelInfo)
+	l.SetReporter(NewTestReporter())
+	l.SetSync(true)
+	defer l.StopContext(context.Background())
+
+	path := filepath.Join(t.TempDir(), "app.log")
+	r, err := slog.NewFileReporter(path, slog.FileOptions{})
+	require.NoError(t, err)
+
+	cancel := slog.ReopenOnSignal(l, r, syscall.SIGHUP)
+	defer cancel()
+
+	require.NoError(t, os.Rename(path, path+".1"))
+	require.NoError(t, syscall.Kill(syscall.Getpid(), syscall.SIGHUP))
+	require.Eventually(t, func() bool {
+		_, err := os.Stat(path)
+		return err == nil
+	}, time.Second, time.Millisecond)
+
+	// errors reopening the file are logged
+	errs, unsubscribe := l.Subscribe(1)
+	defer unsubscribe()
+	require.NoError(t, r.Close())
+	require.NoError(t, syscall.Kill(syscall.Getpid(), syscall.SIGHUP))
+	select {
+	case log := <-errs:
+		require.Equal(t, slog.LevelErr, log.Level)
+		require.Contains(t, fmt.Sprint(log.Data...), "reopening "+path)
+	case <-time.After(time.Second):
+		t.Fatal("no error logged")
+	}
+
+}
@@ -1,30 +1,38 @@
 package slog
 
 import (
+	"context"
+	"encoding"
+	"flag"
 	"fmt"
+	"io"
 	"log"
 	"os"
 	"path/filepath"
+	"reflect"
 	"runtime"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/stretchr/pat/stop"
 )
 
-const nestedLogSep = ">"
+const nestedLogSep = DefaultSourceSep
 
 // Level represents the level of logging.
 type Level uint8
 
 var levelStrs = map[Level]string{
-	LevelInvalid: "(invalid)",
-	LevelNothing: "none",
-	LevelErr:     "error",
-	LevelWarn:    "warning",
-	LevelInfo:    "info",
-	LevelDebug:   "debug",
+	LevelInvalid:    "(invalid)",
+	LevelNothing:    "none",
+	LevelErr:        "error",
+	LevelWarn:       "warning",
+	LevelInfo:       "info",
+	LevelDebug:      "debug",
+	LevelTrace:      "trace",
+	LevelEverything: "everything",
 }
 
 // String gets the string representation of
@@ -37,17 +45,63 @@ func (l Level) String() string {
 }
 
 // ParseLevel gets the Level from the specified
-// String.
+// String, or any prefix of it, e.g. "warn".
+// It gets LevelInvalid if s isn't a level.
 func ParseLevel(s string) Level {
-	s = strings.ToLower(s)
-	for l, str := range levelStrs {
-		if strings.HasPrefix(str, s) {
+	s = strings.ToLower(strings.TrimSpace(s))
+	if s == "" {
+		return LevelInvalid
+	}
+	for l := LevelNothing; l <= LevelEverything; l++ {
+		if levelStrs[l] == s {
+			return l
+		}
+	}
+	for l := LevelNothing; l <= LevelEverything; l++ {
+		if strings.HasPrefix(levelStrs[l], s) {
 			return l
 		}
 	}
 	return LevelInvalid
 }
 
+var _ encoding.TextMarshaler = LevelInvalid
+var _ encoding.TextUnmarshaler = (*Level)(nil)
+var _ flag.Value = (*Level)(nil)
+
+// MarshalText gets the String of the level, so it can be used
+// in JSON and other encodings.
+func (l Level) MarshalText() ([]byte, error) {
+	if _, ok := levelStrs[l]; !ok || l == LevelInvalid {
+		return nil, fmt.Errorf("slog: invalid level %d", l)
+	}
+	return []byte(l.String()), nil
+}
+
+// UnmarshalText sets the level from text, as ParseLevel does.
+func (l *Level) UnmarshalText(text []byte) error {
+	return l.Set(string(text))
+}
+
+// Set sets the level from s, as ParseLevel does, so a Level can
+// be used as a flag, e.g.
+//
+//	level := slog.LevelInfo
+//	flag.Var(&level, "level", "the level to log at")
+func (l *Level) Set(s string) error {
+	level := ParseLevel(s)
+	if level == LevelInvalid {
+		return unknownLevel(s)
+	}
+	*l = level
+	return nil
+}
+
+// unknownLevel gets the error for a string that isn't a Level.
+func unknownLevel(s string) error {
+	return fmt.Errorf("slog: unknown level %q", s)
+}
+
 const (
 	// LevelInvalid represents an invalid Level.
 	// Should never be used, use LevelNothing instead.
@@ -64,6 +118,9 @@ const (
 	LevelInfo
 	// LevelDebug represents debug level logging.
 	LevelDebug
+	// LevelTrace represents trace level logging, which
+	// is more verbose than debug.
+	LevelTrace
 
 	// LevelEverything logs everything.
 	LevelEverything // must always be last value
@@ -74,7 +131,100 @@ type Log struct {
 	Level  Level
 	When   time.Time
 	Data   []interface{}
-	Source []string
+	Source Source
+	// SourceSep separates the names of Source when it is written,
+	// and is DefaultSourceSep if empty.
+	SourceSep string
+	// Fields holds the structured data for the log, and
+	// must not be modified by reporters.
+	Fields Fields
+	// Caller is where the log was made, if the RootLogger is
+	// capturing callers.
+	Caller *Caller
+	// Err is the error passed with Error, or to WithError.
+	Err error
+	// Stack is the stack trace of where the log was made, if the
+	// RootLogger is capturing stacks at its level.
+	Stack string
+	// Template is the format of logs made by the f methods, such
+	// as Infof.
+	Template string
+	// Fingerprint identifies logs that are the same but for the
+	// values in them, once set by the Fingerprint hook.
+	Fingerprint string
+	// Hints are the Hints passed with the log, for the Reporters
+	// that understand them.
+	Hints Hints
+
+	subs []Reporter // added by WithReporter
+	pc   uintptr    // where the log was made, if known
+	loc  *location  // where the log was made, if made by a Logger
+}
+
+// SourceString gets the names of Source joined with SourceSep.
+func (l *Log) SourceString() string {
+	if l.SourceSep == "" {
+		return l.Source.String()
+	}
+	return l.Source.Join(l.SourceSep)
+}
+
+// Location gets the file and line the log was made at, such as
+// "main.go:12", which logs made by a Logger also keep as the first
+// item of Data. It is empty for logs that weren't made by a Logger,
+// such as those built by hand.
+func (l *Log) Location() string {
+	if l.loc == nil {
+		return ""
+	}
+	return l.loc.name
+}
+
+// Message gets the Data of the log without its Location, joined
+// with spaces as fmt.Sprintln does, for reporters that keep the
+// message apart from where it was made.
+func (l *Log) Message() string {
+	return sprint(l.message())
+}
+
+// message gets the Data of the log without its Location.
+func (l *Log) message() []interface{} {
+	if l.hasLocation() {
+		return l.Data[1:]
+	}
+	return l.Data
+}
+
+// hasLocation gets whether the first item of Data is the Location
+// of the log, which it stops being if a Hook replaces the Data.
+func (l *Log) hasLocation() bool {
+	return l.loc != nil && len(l.Data) > 0 && l.Data[0] == l.loc.text
+}
+
+// text gets the Data, Err and Fields of the log, for reporters
+// that write them as text.
+func (l *Log) text() []interface{} {
+	a := make([]interface{}, 0, len(l.Data)+2)
+	a = append(a, l.Data...)
+	if l.Err != nil {
+		a = append(a, l.Err)
+	}
+	if len(l.Fields) > 0 {
+		a = append(a, l.Fields.String())
+	}
+	return a
+}
+
+// Caller represents where a log was made.
+type Caller struct {
+	File     string `json:"file"`
+	Line     int    `json:"line"`
+	Function string `json:"function"`
+}
+
+// String gets the base name of the file and the line.
+func (c *Caller) String() string {
+	return fmt.Sprintf("%s:%d", filepath.Base(c.File), c.Line)
 }
 
 // Reporter represents types capable of doing something
@@ -83,6 +233,25 @@ type Reporter interface {
 	Log(*Log)
 }
 
+// ErrReporter represents reporters that can tell when they fail
+// to do something with a log.
+type ErrReporter interface {
+	Reporter
+	// Report does the same as Log, returning an error if it
+	// failed.
+	Report(*Log) error
+}
+
+// report reports the log to r, getting any error if r is an
+// ErrReporter.
+func report(r Reporter, l *Log) error {
+	if er, ok := r.(ErrReporter); ok {
+		return er.Report(l)
+	}
+	r.Log(l)
+	return nil
+}
+
 // ReporterFunc is a function type capable of acting as
 // a reporter.
 type ReporterFunc func(*Log)
@@ -95,19 +264,79 @@ func (f ReporterFunc) Log(l *Log) {
 type reporters []Reporter
 
 func (rs reporters) Log(l *Log) {
+	rs.Report(l)
+}
+
+// Report reports the log to each of the reporters, returning the
+// first error.
+func (rs reporters) Report(l *Log) error {
+	var err error
 	for _, r := range rs {
-		r.Log(l)
+		if rerr := report(r, l); rerr != nil && err == nil {
+			err = rerr
+		}
 	}
+	return err
+}
+
+// Close closes each of the reporters that is an io.Closer,
+// returning the first error.
+func (rs reporters) Close() error {
+	var err error
+	for _, r := range rs {
+		if c, ok := r.(io.Closer); ok {
+			if cerr := c.Close(); cerr != nil && err == nil {
+				err = cerr
+			}
+		}
+	}
+	return err
 }
 
 // Reporters makes a Reporter that reports to multiple
 // reporters in order.
+// Closing the returned Reporter closes each of the reporters
+// that is an io.Closer, and flushing or starting it does the same
+// for each Flusher or Starter.
 func Reporters(rs ...Reporter) Reporter {
 	return reporters(rs)
 }
 
 var _ Reporter = (Reporters)(nil)
 
+type atLevel struct {
+	level Level
+	r     Reporter
+}
+
+func (a *atLevel) Log(l *Log) {
+	if l.Level <= a.level {
+		a.r.Log(l)
+	}
+}
+
+func (a *atLevel) Close() error {
+	if c, ok := a.r.(io.Closer); ok {
+		return c.Close()
+	}
+	return nil
+}
+
+func (a *atLevel) Flush() {
+	reporters{a.r}.Flush()
+}
+
+func (a *atLevel) Start() {
+	reporters{a.r}.Start()
+}
+
+// AtLevel makes a Reporter that only reports logs at the level,
+// or more severe, to r.
+// This lets each of the Reporters have its own level.
+func AtLevel(level Level, r Reporter) Reporter {
+	return &atLevel{level: level, r: r}
+}
+
 // RootLogger represents a the root Logger that has
 // more capabilities than a normal Logger.
 // Normally, caller code would require the Logger interface only.
@@ -117,11 +346,139 @@ type RootLogger interface {
 	// SetReporter sets the Reporter for this logger and
 	// child loggers to use.
 	SetReporter(r Reporter)
+	// SwapReporter sets the Reporter like SetReporter, and gets
+	// the Reporter it replaces.
+	// Logs already being reported finish with the old Reporter,
+	// and it isn't closed.
+	SwapReporter(r Reporter) Reporter
+	// SetReporterForLevel sets the Reporter for logs at the level,
+	// instead of the one set by SetReporter. A nil Reporter goes
+	// back to that one.
+	SetReporterForLevel(level Level, r Reporter)
+	// SetAuditReporter sets the Reporter that audit logs are
+	// reported to, see Logger.Audit.
+	SetAuditReporter(r Reporter)
+	// Tree gets a snapshot of the sources loggers have been made
+	// for, from this one down, with their levels and how many logs
+	// each has made.
+	Tree() Tree
+	// SetClock sets the Clock used to tell the time of logs, and
+	// of boosts. A nil Clock uses SystemClock.
+	SetClock(c Clock)
 	// SetReporterFunc sets the specified ReporterFunc as
 	// the Reporter.
 	SetReporterFunc(f ReporterFunc)
-	// SetLevel sets the level of this and all children loggers.
+	// AddHook adds a Hook that is run on each log before it is
+	// reported. Hooks run in the order they are added.
+	AddHook(h Hook)
+	// SetLevel sets the level of this and all children loggers
+	// that don't have their own level.
 	SetLevel(level Level)
+	// Level gets the level set by SetLevel.
+	Level() Level
+	// SetSourceLevel sets the level of loggers whose source is, or
+	// starts with, source (sources joined with ">"), overriding the
+	// level of the root logger.
+	// The most specific source wins. Setting LevelInvalid removes
+	// the override.
+	SetSourceLevel(source string, level Level)
+	// SourceLevels gets the levels set by SetSourceLevel, keyed
+	// by source.
+	SourceLevels() map[string]Level
+	// SetBuffer sets the number of logs that can be queued
+	// waiting for the Reporter, and what happens when the buffer
+	// is full.
+	// With a buffer of zero (the default), each log waits for the
+	// Reporter to take it.
+	SetBuffer(n int, policy DropPolicy)
+	// Dropped gets the number of logs dropped because the buffer
+	// was full.
+	Dropped() uint64
+	// Pressure gets how full the buffer is, from 0 to 1, so code
+	// that logs a lot can log less before logs start being dropped
+	// or logging blocks. Without a buffer it is 1 while a log is
+	// waiting for the Reporter.
+	Pressure() float64
+	// SetErrorHandler sets a func that is given the problems the
+	// logger has, so they aren't silently lost: ErrDropped for each
+	// log dropped because the buffer was full, and a *ReportError
+	// for each log a Reporter fails to report or panics on. The
+	// func may be called from any goroutine, and mustn't log to
+	// this logger. A nil func ignores them, which is the default.
+	SetErrorHandler(f func(error))
+	// Stats gets the stats of the logging pipeline.
+	Stats() Stats
+	// Counts gets the number of logs made at each level since the
+	// logger was made, e.g. for health checks that there have been
+	// no errors. Tree gets the number made for each source.
+	Counts() map[Level]uint64
+	// Subscribe gets a channel that is sent every log as it is
+	// reported, after the hooks have run, so tools can tail the
+	// logs without being the Reporter, along with a func to
+	// unsubscribe.
+	// The channel holds up to buffer logs; a subscriber that falls
+	// further behind is unsubscribed, so it can't hold up
+	// reporting. Either way, and when the logger stops, the channel
+	// is closed. The logs are shared, so mustn't be changed.
+	Subscribe(buffer int) (<-chan *Log, func())
+	// SetCaptureCaller sets whether logs have their Caller
+	// captured, which has a cost so is off by default.
+	SetCaptureCaller(capture bool)
+	// SetSync sets whether logs are reported in the goroutine that
+	// makes them, before the log method returns, instead of in the
+	// background. It is meant for tests, which can then check what
+	// was reported straight after logging. The buffer isn't used
+	// while it is set.
+	SetSync(sync bool)
+	// SetCaptureStack sets logs of the level, or more severe, to
+	// have their Stack captured, e.g. LevelErr.
+	// Capturing stacks has a cost so is off by default, and
+	// LevelInvalid turns it off again.
+	SetCaptureStack(level Level)
+	// Boost raises the level of loggers whose source starts with
+	// sourcePrefix (sources joined with ">") to level for d, or
+	// until the returned cancel func is called.
+	// Overlapping boosts on the same prefix use the highest level
+	// until the latest deadline.
+	Boost(sourcePrefix string, level Level, d time.Duration) (cancel func())
+	// StopContext stops the logger accepting logs, and reports
+	// the logs that are already queued until ctx is done.
+	// The Reporter is closed once all logs have been reported,
+	// if it is an io.Closer.
+	// If ctx is done first, the remaining logs are abandoned and
+	// a *StopError is returned.
+	// Calling StopContext again does nothing and returns nil.
+	StopContext(ctx context.Context) error
+	// OnStop adds a func that is called when the logger stops, once
+	// the logs have been reported or abandoned, and before the
+	// StopChan is closed, e.g. to flush buffers or close
+	// connections. The funcs are called in the reverse of the order
+	// they were added. If the logger has already stopped, f is
+	// called straight away.
+	OnStop(f func())
+	// Flush waits for the logs made so far to be reported, and then
+	// flushes the Reporters that are a Flusher, so nothing is lost
+	// if the program exits straight after. It gives up when ctx is
+	// done, getting its error.
+	Flush(ctx context.Context) error
+}
+
+// StopError is returned by StopContext when the context is done
+// before all logs have been reported.
+type StopError struct {
+	// Abandoned is the number of logs that were never reported.
+	Abandoned int
+	// Err is the error from the context.
+	Err error
+}
+
+func (e *StopError) Error() string {
+	return fmt.Sprintf("slog: %d log(s) abandoned: %s", e.Abandoned, e.Err)
+}
+
+// Unwrap gets the error from the context.
+func (e *StopError) Unwrap() error {
+	return e.Err
 }
 
 // Logger represents types capable of logging at
@@ -136,23 +493,163 @@ type Logger interface {
 	// Err gets whether the logger is logging errors or not,
 	// and also makes such logs.
 	Err(a ...interface{}) bool
-	// Debug gets whether the logger is logging errors or not,
+	// Debug gets whether the logger is logging debug or not,
 	// and also makes such logs.
 	Debug(a ...interface{}) bool
+	// Trace gets whether the logger is logging trace or not,
+	// and also makes such logs.
+	Trace(a ...interface{}) bool
+	// Infof makes an information log with the message formatted
+	// by fmt.Sprintf, if the logger is logging information.
+	Infof(format string, a ...interface{}) bool
+	// Warnf makes a warning log with the message formatted
+	// by fmt.Sprintf, if the logger is logging warnings.
+	Warnf(format string, a ...interface{}) bool
+	// Errf makes an error log with the message formatted
+	// by fmt.Sprintf, if the logger is logging errors.
+	Errf(format string, a ...interface{}) bool
+	// Debugf makes a debug log with the message formatted
+	// by fmt.Sprintf, if the logger is logging debug.
+	Debugf(format string, a ...interface{}) bool
+	// Tracef makes a trace log with the message formatted
+	// by fmt.Sprintf, if the logger is logging trace.
+	Tracef(format string, a ...interface{}) bool
+	// Fatal makes an error log, waits for the logger to stop
+	// (see StopContext), and then exits the program with status 1.
+	Fatal(a ...interface{})
+	// Panic makes an error log, waits for it to be reported, and
+	// then panics with the message.
+	Panic(a ...interface{})
+	// CapturePanic recovers from a panic, if there is one, and logs
+	// it as Recover does. It must be deferred:
+	//
+	//	defer l.CapturePanic()
+	CapturePanic()
 	// New creates a new child logger, with this as the parent.
 	New(source string) Logger
-	// SetSource sets the source of this logger.
+	// Acquire gets a child logger like New, but from a pool, so
+	// code that makes a child for each request doesn't allocate one
+	// each time. The child should be given back with Release.
+	Acquire(source string) Logger
+	// Release closes the logger, as Close does, and gives it back to
+	// the pool if it was got from Acquire. It mustn't be used
+	// afterwards.
+	Release()
+	// SetSource sets the source of this logger. Logs it has
+	// already made, and its children, keep the old source.
 	SetSource(source string)
+	// WithSource creates a new logger like this one, but with the
+	// source instead of its own, leaving this one as it is.
+	WithSource(source string) Logger
+	// SetLevel sets the level of this logger and its children,
+	// overriding the level of its parent.
+	// Setting LevelInvalid removes the override, so the logger
+	// follows its parent again.
+	SetLevel(level Level)
+	// WithFields creates a new logger with the same source as this
+	// one, which adds the fields to every log it, and its children,
+	// make.
+	WithFields(fields Fields) Logger
+	// With is like WithFields, with a single field.
+	With(key string, value interface{}) Logger
+	// WithError creates a new logger with the same source as this
+	// one, which adds the error to every log it, and its children,
+	// make.
+	WithError(err error) Logger
+	// WithReporter creates a new logger with the same source as
+	// this one, whose logs, and those of its children, are reported
+	// to r as well as to the Reporter of the root logger, e.g. to
+	// write the logs of a job to a file of its own.
+	WithReporter(r Reporter) Logger
+	// Close releases the logger once it is no longer needed,
+	// waiting for its logs to be reported, and closing the Reporter
+	// given to WithReporter if it is an io.Closer. It logs nothing
+	// afterwards.
+	// Its children should be closed first. Closing a RootLogger
+	// stops it, as StopContext does.
+	Close() error
+	// InfoOnce logs at LevelInfo like Info, but only the first time
+	// it's called with the key, by any logger with the same root.
+	// It gets whether it logged.
+	InfoOnce(key string, a ...interface{}) bool
+	// WarnOnce is like InfoOnce, at LevelWarn.
+	WarnOnce(key string, a ...interface{}) bool
+	// ErrOnce is like InfoOnce, at LevelErr.
+	ErrOnce(key string, a ...interface{}) bool
+	// InfoEvery logs at LevelInfo like Info, but only the first time
+	// and then every nth time it's called from the same place, for
+	// conditions that repeat in loops.
+	// It gets whether it logged.
+	InfoEvery(n int, a ...interface{}) bool
+	// WarnEvery is like InfoEvery, at LevelWarn.
+	WarnEvery(n int, a ...interface{}) bool
+	// ErrEvery is like InfoEvery, at LevelErr.
+	ErrEvery(n int, a ...interface{}) bool
+	// Audit reports a log straight away to the audit Reporter of
+	// the root logger, at LevelInfo whatever the level of the
+	// logger, for events that must never be dropped.
+	// It waits for the log to be reported, and synced if the
+	// Reporter is a Syncer, and gets the error if that fails.
+	// Audit logs aren't queued, so they are reported even if the
+	// buffer is full or the logger has stopped. Hooks are run on
+	// them, so they can be redacted.
+	Audit(a ...interface{}) error
+	// Timed logs that the operation has started at LevelInfo, and
+	// gets a Timer that logs when it's Done, and how long it took.
+	Timed(operation string) *Timer
 }
 
 type logger struct {
-	m        sync.Mutex
-	level    Level
-	r        Reporter
-	c        chan *Log
-	src      []string
-	stopChan chan stop.Signal
-	root     *logger
+	m      sync.Mutex   // held while the source is changed
+	path   atomic.Value // holds the *sourcePath
+	node   atomic.Value // holds the *treeNode of the source
+	fields Fields
+	err    error
+	subs   []Reporter // added by WithReporter, never changed
+	owns   bool       // whether the last of subs was added to this logger
+	closed int32      // set once Close has been called
+	active int32      // set while this logger is counted in children
+	pooled bool       // whether Release gives this logger back to the pool
+	root   *logger
+
+	// fields below are only used on the root logger
+	level       int32               // the Level, accessed atomically
+	clock       atomic.Value        // holds a clockBox
+	levels      map[string]Level    // protected by m
+	boosts      map[string][]*boost // protected by m
+	overrides   int32               // set while there are levels or boosts
+	r           atomic.Value        // holds a reporterBox
+	sm          sync.Mutex          // held while r is replaced
+	tm          sync.Mutex          // protects the tree of sources
+	nodes       int                 // number of sources in the tree
+	om          sync.Mutex          // protects onceKeys
+	onceKeys    map[string]struct{}
+	nths        sync.Map     // map[uintptr]*uint64, of calls to the Every methods
+	rm          sync.Mutex   // protects hooks, onStop and stopped
+	am          sync.Mutex   // protects audit, and is held while auditing
+	errh        atomic.Value // holds an errorHandler
+	audit       Reporter
+	hooks       []Hook
+	onStop      []func()
+	stopped     bool // set once the onStop funcs have been called
+	q           *queue
+	done        chan struct{} // closed when dispatch has finished
+	stopChan    chan stop.Signal
+	stopOnce    sync.Once
+	abandon     int32                   // set to abandon the remaining logs
+	abandoned   int64                   // number of logs abandoned
+	late        uint64                  // number of logs made after stopping
+	counts      [LevelEverything]uint64 // number of logs made at each level
+	tail        subscriptions           // of those tailing the logs
+	caller      int32                   // set to capture callers
+	synchronous int32                   // set to report logs in the goroutine making them
+	rpm         sync.Mutex              // held while a log is reported
+	reported    uint64                  // number of logs given to the reporter
+	failed      uint64                  // number of those the reporter failed
+	reporting   int64                   // nanoseconds spent in the reporter
+	stack       int32                   // level to capture stacks at
+	children    int64                   // number of children made by New that haven't been closed
+	sep         string                  // the SourceSep of logs
 }
 
 var _ Logger = (*logger)(nil)
@@ -164,120 +661,731 @@ var _ Logger = (*logger)(nil)
 // By default, the returned Logger will log to the slog.Stdout
 // reporter, but this can be changed with SetReporter.
 func New(source string, level Level) RootLogger {
-	l := &logger{
-		level: level,
-		src:   []string{source},
-		r:     Stdout,
-	}
-	l.root = l // use this one as the root one
-	l.Start()
-	return l
+	return NewWithOptions(source, WithLevel(level))
+}
+
+// sourcePath is the source of a logger.
+// It is replaced rather than changed, as logs keep the Source.
+type sourcePath struct {
+	src  Source
+	name string // src joined with nestedLogSep
+}
+
+// newSourcePath makes the sourcePath for the parent source with
+// the name added, copying it so siblings don't share it.
+func newSourcePath(parent Source, name string) *sourcePath {
+	src := make(Source, len(parent)+1)
+	copy(src, parent)
+	src[len(parent)] = name
+	return &sourcePath{src: src, name: strings.Join(src, nestedLogSep)}
+}
+
+func (l *logger) sourcePath() *sourcePath {
+	return l.path.Load().(*sourcePath)
 }
 
 // New makes a new child logger with the specified source.
 func (l *logger) New(source string) Logger {
-	return &logger{
-		level: l.level,
-		src:   append(l.src, source),
-		root:  l.root,
+	child := &logger{
+		fields: l.fields,
+		err:    l.err,
+		subs:   l.subs,
+		root:   l.root,
 	}
+	l.initChild(child, source)
+	return child
+}
+
+// initChild sets the source of the child of this logger, and counts
+// it.
+func (l *logger) initChild(child *logger, source string) {
+	node := l.root.treeChild(l.treeNode(), source)
+	child.path.Store(childPath(node, l.sourcePath(), source))
+	child.node.Store(node)
+	child.count()
+}
+
+// count counts the logger in the children of the root logger, until
+// it is closed.
+func (l *logger) count() {
+	l.active = 1
+	atomic.AddInt64(&l.root.children, 1)
 }
 
 func (l *logger) SetLevel(level Level) {
-	l.root.m.Lock()
-	l.root.level = level
-	l.root.m.Unlock()
+	if l != l.root {
+		l.root.SetSourceLevel(l.source(), level)
+		return
+	}
+	atomic.StoreInt32(&l.root.level, int32(level))
+}
+
+func (l *logger) Level() Level {
+	return Level(atomic.LoadInt32(&l.root.level))
+}
+
+func (l *logger) SetSourceLevel(source string, level Level) {
+	root := l.root
+	source = l.sourceName(source)
+	root.m.Lock()
+	if level == LevelInvalid {
+		delete(root.levels, source)
+	} else {
+		if root.levels == nil {
+			root.levels = make(map[string]Level)
+		}
+		root.levels[source] = level
+	}
+	root.updateOverrides()
+	root.m.Unlock()
+}
+
+func (l *logger) SourceLevels() map[string]Level {
+	root := l.root
+	root.m.Lock()
+	defer root.m.Unlock()
+	levels := make(map[string]Level, len(root.levels))
+	for source, level := range root.levels {
+		levels[l.sepName(source)] = level
+	}
+	return levels
 }
 
 func (l *logger) SetSource(source string) {
 	l.m.Lock()
-	l.src[len(l.src)-1] = source
+	src := l.sourcePath().src
+	l.path.Store(newSourcePath(src[:len(src)-1], source))
+	if n := l.treeNode(); n != nil && n.parent != nil {
+		l.node.Store(l.root.treeChild(n.parent, source))
+	}
 	l.m.Unlock()
 }
 
+func (l *logger) WithSource(source string) Logger {
+	src := l.sourcePath().src
+	sibling := &logger{
+		fields: l.fields,
+		err:    l.err,
+		subs:   l.subs,
+		root:   l.root,
+	}
+	sibling.path.Store(newSourcePath(src[:len(src)-1], source))
+	node := l.treeNode()
+	if node != nil && node.parent != nil {
+		node = l.root.treeChild(node.parent, source)
+	}
+	sibling.node.Store(node)
+	return sibling
+}
+
+func (l *logger) WithReporter(r Reporter) Logger {
+	startReporter(r)
+	child := &logger{
+		fields: l.fields,
+		err:    l.err,
+		subs:   append(l.subs[:len(l.subs):len(l.subs)], r),
+		owns:   true,
+		root:   l.root,
+	}
+	child.path.Store(l.sourcePath())
+	child.node.Store(l.treeNode())
+	child.count()
+	return child
+}
+
+func (l *logger) Close() error {
+	if l == l.root {
+		return l.StopContext(context.Background())
+	}
+	if !atomic.CompareAndSwapInt32(&l.closed, 0, 1) {
+		return nil
+	}
+	if atomic.CompareAndSwapInt32(&l.active, 1, 0) {
+		atomic.AddInt64(&l.root.children, -1)
+	}
+	if !l.owns {
+		return nil
+	}
+	l.root.q.flush()
+	if c, ok := l.subs[len(l.subs)-1].(io.Closer); ok {
+		return c.Close()
+	}
+	return nil
+}
+
+// reporterBox holds the Reporters, so Reporters of any type can be
+// stored in an atomic.Value.
+// It is replaced rather than changed.
+type reporterBox struct {
+	r      Reporter
+	levels map[Level]Reporter
+}
+
 func (l *logger) SetReporter(r Reporter) {
-	l.root.Stop(stop.NoWait)
-	<-l.root.StopChan()
-	l.root.r = r
-	l.root.Start()
+	l.SwapReporter(r)
+}
+
+func (l *logger) SwapReporter(r Reporter) Reporter {
+	root := l.root
+	startReporter(r)
+	root.sm.Lock()
+	defer root.sm.Unlock()
+	box := reporterBox{r: r}
+	if old, ok := root.r.Load().(reporterBox); ok {
+		box.levels = old.levels
+	}
+	if old, ok := root.r.Swap(box).(reporterBox); ok {
+		return old.r
+	}
+	return nil
+}
+
+func (l *logger) SetReporterForLevel(level Level, r Reporter) {
+	root := l.root
+	if r != nil {
+		startReporter(r)
+	}
+	root.sm.Lock()
+	defer root.sm.Unlock()
+	old := root.r.Load().(reporterBox)
+	box := reporterBox{r: old.r, levels: make(map[Level]Reporter, len(old.levels)+1)}
+	for level, r := range old.levels {
+		box.levels[level] = r
+	}
+	if r == nil {
+		delete(box.levels, level)
+	} else {
+		box.levels[level] = r
+	}
+	root.r.Store(box)
+}
+
+// reporterFor gets the Reporter for logs at the level.
+func (l *logger) reporterFor(level Level) Reporter {
+	box := l.root.r.Load().(reporterBox)
+	if r, ok := box.levels[level]; ok {
+		return r
+	}
+	return box.r
 }
 
 func (l *logger) SetReporterFunc(f ReporterFunc) {
 	l.SetReporter(f)
 }
 
-func (l *logger) Start() {
-	l.root.c = make(chan *Log)
-	l.root.stopChan = stop.Make()
-	go func() {
-		for item := range l.root.c {
-			l.root.r.Log(item)
+func (l *logger) reporter() Reporter {
+	return l.root.r.Load().(reporterBox).r
+}
+
+// start starts the goroutine that hands logs to the Reporter.
+// Must only be called on the root logger.
+func (l *logger) start() {
+	l.q = newQueue()
+	l.done = make(chan struct{})
+	l.stopChan = stop.Make()
+	go l.dispatch()
+}
+
+// dispatch reports logs until the logger stops and all of the
+// queued logs have been reported.
+func (l *logger) dispatch() {
+	defer close(l.done)
+	for {
+		item, ok := l.q.take()
+		if !ok {
+			return
 		}
-	}()
+		l.rpm.Lock()
+		l.report(item)
+		l.rpm.Unlock()
+		l.q.finish()
+	}
 }
 
-func (l *logger) Debug(a ...interface{}) bool {
-	if l.skip(LevelDebug) {
-		return false
+func (l *logger) report(item *Log) {
+	if atomic.LoadInt32(&l.abandon) == 1 {
+		atomic.AddInt64(&l.abandoned, 1)
+		return
 	}
-	if len(a) == 0 {
-		return true
+	l.root.rm.Lock()
+	hooks := l.root.hooks
+	l.root.rm.Unlock()
+	if item = runHooks(hooks, resolveLazy(item)); item == nil {
+		return
+	}
+	start := time.Now()
+	err := safeReport(l.reporterFor(item.Level), item)
+	for _, r := range item.subs {
+		if err := safeReport(r, item); err != nil {
+			l.handleError(err)
+		}
+	}
+	atomic.AddInt64(&l.reporting, int64(time.Since(start)))
+	atomic.AddUint64(&l.reported, 1)
+	l.tail.publish(item)
+	if err != nil {
+		atomic.AddUint64(&l.failed, 1)
+		l.handleError(err)
 	}
-	_, path, line, _ := runtime.Caller(1)
-	file := fmt.Sprintf("( %s:%d )", filepath.Base(path), line)
-	l.root.c <- &Log{When: time.Now(), Data: append([]interface{}{file}, a...), Source: l.src, Level: LevelDebug}
-	return true
 }
 
-func (l *logger) Info(a ...interface{}) bool {
-	if l.skip(LevelInfo) {
+// reportSync reports the log straight away, returning false, and
+// counting it, if the logger has stopped.
+// Must only be called on the root logger.
+func (l *logger) reportSync(item *Log) bool {
+	l.rpm.Lock()
+	defer l.rpm.Unlock()
+	if l.q.isClosed() {
+		atomic.AddUint64(&l.late, 1)
 		return false
 	}
-	if len(a) == 0 {
-		return true
-	}
-	_, path, line, _ := runtime.Caller(1)
-	file := fmt.Sprintf("( %s:%d )", filepath.Base(path), line)
-	l.root.c <- &Log{When: time.Now(), Data: append([]interface{}{file}, a...), Source: l.src, Level: LevelInfo}
+	l.report(item)
 	return true
 }
 
+// send queues the log to be reported, returning false, and
+// counting it, if the logger has stopped.
+func (l *logger) send(item *Log) bool {
+	if atomic.LoadInt32(&l.root.synchronous) == 1 {
+		return l.root.reportSync(item)
+	}
+	ok, dropped := l.root.q.put(item)
+	if dropped {
+		l.handleError(ErrDropped)
+	}
+	if !ok {
+		atomic.AddUint64(&l.root.late, 1)
+	}
+	return ok
+}
+
+func (l *logger) SetBuffer(n int, policy DropPolicy) {
+	l.root.q.setSize(n, policy)
+}
+
+func (l *logger) Dropped() uint64 {
+	return l.root.q.droppedCount()
+}
+
+func (l *logger) Pressure() float64 {
+	return l.root.q.pressure()
+}
+
+func (l *logger) Trace(a ...interface{}) bool {
+	return l.log(LevelTrace, a)
+}
+
+func (l *logger) Debug(a ...interface{}) bool {
+	return l.log(LevelDebug, a)
+}
+
+func (l *logger) Info(a ...interface{}) bool {
+	return l.log(LevelInfo, a)
+}
+
 func (l *logger) Warn(a ...interface{}) bool {
-	if l.skip(LevelWarn) {
+	return l.log(LevelWarn, a)
+}
+
+func (l *logger) Err(a ...interface{}) bool {
+	return l.log(LevelErr, a)
+}
+
+func (l *logger) Tracef(format string, a ...interface{}) bool {
+	return l.logf(LevelTrace, format, a)
+}
+
+func (l *logger) Debugf(format string, a ...interface{}) bool {
+	return l.logf(LevelDebug, format, a)
+}
+
+func (l *logger) Infof(format string, a ...interface{}) bool {
+	return l.logf(LevelInfo, format, a)
+}
+
+func (l *logger) Warnf(format string, a ...interface{}) bool {
+	return l.logf(LevelWarn, format, a)
+}
+
+func (l *logger) Errf(format string, a ...interface{}) bool {
+	return l.logf(LevelErr, format, a)
+}
+
+func (l *logger) Fatal(a ...interface{}) {
+	if !l.skip(LevelErr) {
+		l.emit(LevelErr, callerPC(1), a)
+	}
+	l.root.StopContext(context.Background())
+	exit(1)
+}
+
+func (l *logger) Panic(a ...interface{}) {
+	if !l.skip(LevelErr) && l.emit(LevelErr, callerPC(1), a) {
+		l.root.q.flush()
+	}
+	panic(sprint(a))
+}
+
+// exit exits the program, and is replaced in tests.
+var exit = os.Exit
+
+// sprint formats a like fmt.Sprintln, without the newline.
+func sprint(a []interface{}) string {
+	return strings.TrimSuffix(fmt.Sprintln(a...), "\n")
+}
+
+// log logs a at the level, and gets whether the logger is
+// logging at the level or not.
+// It must be called directly from the Logger method so the
+// caller's file and line are recorded.
+func (l *logger) log(level Level, a []interface{}) bool {
+	if l.skip(level) {
 		return false
 	}
 	if len(a) == 0 {
 		return true
 	}
-	_, path, line, _ := runtime.Caller(1)
-	file := fmt.Sprintf("( %s:%d )", filepath.Base(path), line)
-	l.root.c <- &Log{When: time.Now(), Data: append([]interface{}{file}, a...), Source: l.src, Level: LevelWarn}
-	return true
+	return l.emit(level, callerPC(2), a)
 }
 
-func (l *logger) Err(a ...interface{}) bool {
-	if l.skip(LevelErr) {
+// logf is like log, but formats the message with fmt.Sprintf
+// only if the logger is logging at the level.
+func (l *logger) logf(level Level, format string, a []interface{}) bool {
+	if l.skip(level) {
 		return false
 	}
-	if len(a) == 0 {
-		return true
+	item := l.makeLog(level, callerPC(2), []interface{}{fmt.Sprintf(format, a...)})
+	item.Template = format
+	return l.emitLog(item)
+}
+
+// logPC is like log, but for logs made by the caller at pc.
+func (l *logger) logPC(level Level, pc uintptr, a []interface{}) bool {
+	if l.skip(level) {
+		return false
 	}
-	_, path, line, _ := runtime.Caller(1)
-	file := fmt.Sprintf("( %s:%d )", filepath.Base(path), line)
-	l.root.c <- &Log{When: time.Now(), Data: append([]interface{}{file}, a...), Source: l.src, Level: LevelErr}
-	return true
+	return l.emit(level, pc, a)
+}
+
+// callerPC gets the program counter of the caller skip frames
+// above the function calling callerPC.
+func callerPC(skip int) uintptr {
+	var pcs [1]uintptr
+	runtime.Callers(skip+2, pcs[:])
+	return pcs[0]
+}
+
+// emit makes the log made by the caller at pc, and sends it
+// to be reported.
+func (l *logger) emit(level Level, pc uintptr, a []interface{}) bool {
+	return l.emitLog(l.makeLog(level, pc, a))
+}
+
+// emitLog counts the log made by this logger, and sends it to be
+// reported.
+func (l *logger) emitLog(item *Log) bool {
+	if n := l.treeNode(); n != nil {
+		atomic.AddUint64(&n.logs, 1)
+	}
+	if item.Level > LevelNothing && item.Level < LevelEverything {
+		atomic.AddUint64(&l.root.counts[item.Level], 1)
+	}
+	return l.send(item)
+}
+
+// makeLog makes the log made by the caller at pc.
+func (l *logger) makeLog(level Level, pc uintptr, a []interface{}) *Log {
+	loc := locate(pc)
+	data := make([]interface{}, 1, len(a)+1)
+	data[0] = loc.text
+	var more [2]Fields
+	fields := more[:0]
+	var hints Hints
+	err := l.err
+	for _, d := range a {
+		switch d := d.(type) {
+		case Fields:
+			fields = append(fields, d)
+		case Hints:
+			hints = hints.merge(d)
+		case errorArg:
+			err = d.err
+		default:
+			data = append(data, d)
+		}
+	}
+	item := &Log{When: l.now(), Data: data, Source: l.sourcePath().src, SourceSep: l.root.sep, Level: level, Fields: l.fields.merge(fields...), Err: err, Hints: hints, subs: l.subs, pc: pc, loc: loc}
+	if atomic.LoadInt32(&l.root.caller) == 1 {
+		item.Caller = &Caller{File: loc.frame.File, Line: loc.frame.Line, Function: loc.frame.Function}
+	}
+	if level <= Level(atomic.LoadInt32(&l.root.stack)) {
+		item.Stack = stack(pc)
+	}
+	return item
+}
+
+// location is where a log was made.
+type location struct {
+	frame runtime.Frame
+	name  string      // the file and line, e.g. main.go:12
+	text  interface{} // the name in brackets, as the first Data of logs
+}
+
+// locations caches the location of each program counter that
+// logs are made at, so they are only looked up once.
+var locations sync.Map // map[uintptr]*location
+
+// locate gets the location of the program counter.
+func locate(pc uintptr) *location {
+	if loc, ok := locations.Load(pc); ok {
+		return loc.(*location)
+	}
+	frame, _ := runtime.CallersFrames([]uintptr{pc}).Next()
+	name := fmt.Sprintf("%s:%d", filepath.Base(frame.File), frame.Line)
+	loc := &location{
+		frame: frame,
+		name:  name,
+		text:  "( " + name + " )",
+	}
+	locations.Store(pc, loc)
+	return loc
+}
+
+// stackPool holds the buffers used to capture stacks.
+var stackPool = sync.Pool{
+	New: func() interface{} {
+		pcs := make([]uintptr, 64)
+		return &pcs
+	},
+}
+
+// stack formats the stack of the current goroutine, from the
+// caller at pc outwards, like a panic does.
+func stack(pc uintptr) string {
+	p := stackPool.Get().(*[]uintptr)
+	defer stackPool.Put(p)
+	pcs := *p
+	pcs = pcs[:runtime.Callers(2, pcs)]
+	for i, p := range pcs {
+		if p == pc {
+			pcs = pcs[i:]
+			break
+		}
+	}
+	var buf strings.Builder
+	frames := runtime.CallersFrames(pcs)
+	for {
+		frame, more := frames.Next()
+		fmt.Fprintf(&buf, "%s()\n\t%s:%d\n", frame.Function, frame.File, frame.Line)
+		if !more {
+			break
+		}
+	}
+	return buf.String()
+}
+
+func (l *logger) SetCaptureCaller(capture bool) {
+	var c int32
+	if capture {
+		c = 1
+	}
+	atomic.StoreInt32(&l.root.caller, c)
+}
+
+func (l *logger) SetSync(sync bool) {
+	if !sync {
+		atomic.StoreInt32(&l.root.synchronous, 0)
+		return
+	}
+	atomic.StoreInt32(&l.root.synchronous, 1)
+	l.root.q.flush()
+}
+
+func (l *logger) SetCaptureStack(level Level) {
+	atomic.StoreInt32(&l.root.stack, int32(level))
 }
 
 func (l *logger) skip(level Level) bool {
-	l.root.m.Lock()
-	s := l.level < level
-	l.root.m.Unlock()
-	return s
+	return l.effectiveLevel() < level || atomic.LoadInt32(&l.closed) == 1
+}
+
+// effectiveLevel gets the level this logger is logging at,
+// taking source levels and boosts into account.
+func (l *logger) effectiveLevel() Level {
+	root := l.root
+	if atomic.LoadInt32(&root.overrides) == 0 {
+		return Level(atomic.LoadInt32(&root.level))
+	}
+	return root.levelOf(l.source())
+}
+
+// levelOf gets the level logs from the source are logged at.
+// Must only be called on the root logger.
+func (l *logger) levelOf(source string) Level {
+	root := l
+	level := Level(atomic.LoadInt32(&root.level))
+	root.m.Lock()
+	match := -1
+	for prefix, prefixLevel := range root.levels {
+		if len(prefix) > match && hasSourcePrefix(source, prefix) {
+			level, match = prefixLevel, len(prefix)
+		}
+	}
+	root.m.Unlock()
+	if boosted := root.boostLevel(source); boosted > level {
+		level = boosted
+	}
+	return level
+}
+
+// updateOverrides records whether any source levels or boosts
+// are set, so effectiveLevel can skip looking for them if not.
+// Must be called on the root logger with m held.
+func (l *logger) updateOverrides() {
+	var overrides int32
+	if len(l.levels) > 0 || len(l.boosts) > 0 {
+		overrides = 1
+	}
+	atomic.StoreInt32(&l.overrides, overrides)
+}
+
+// source gets the source of this logger joined with nestedLogSep.
+func (l *logger) source() string {
+	return l.sourcePath().name
+}
+
+// Stop stops the logger accepting logs, and reports the logs that
+// are already queued in the background, abandoning any that remain
+// after wait. StopChan is closed once it has finished.
+func (l *logger) Stop(wait time.Duration) {
+	if !l.root.halt() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), wait)
+	go func() {
+		defer cancel()
+		l.root.drain(ctx)
+	}()
+}
+
+func (l *logger) StopContext(ctx context.Context) error {
+	if !l.root.halt() {
+		return nil
+	}
+	return l.root.drain(ctx)
+}
+
+// halt stops the logger accepting logs, returning false if it
+// had already been halted.
+// Must only be called on the root logger.
+func (l *logger) halt() bool {
+	halted := false
+	l.stopOnce.Do(func() {
+		l.q.close()
+		halted = true
+	})
+	return halted
+}
+
+// drain waits for the queued logs to be reported, or abandons
+// them when ctx is done, and then closes the StopChan.
+// Must only be called on the root logger.
+func (l *logger) drain(ctx context.Context) error {
+	defer close(l.stopChan)
+	defer l.runOnStop()
+	defer l.tail.close()
+	select {
+	case <-l.done:
+		return l.closeReporter()
+	case <-ctx.Done():
+	}
+	atomic.StoreInt32(&l.abandon, 1)
+	atomic.AddInt64(&l.abandoned, int64(l.q.discard()))
+	select {
+	case <-l.done:
+		if n := atomic.LoadInt64(&l.abandoned); n == 0 {
+			return l.closeReporter()
+		}
+	default:
+		// the Reporter is still busy, so close it when it's done
+		go func() {
+			<-l.done
+			l.closeReporter()
+		}()
+	}
+	return &StopError{Abandoned: int(atomic.LoadInt64(&l.abandoned)), Err: ctx.Err()}
+}
+
+func (l *logger) OnStop(f func()) {
+	root := l.root
+	root.rm.Lock()
+	if root.stopped {
+		root.rm.Unlock()
+		f()
+		return
+	}
+	root.onStop = append(root.onStop, f)
+	root.rm.Unlock()
+}
+
+// runOnStop calls the funcs given to OnStop, newest first.
+// Must only be called on the root logger.
+func (l *logger) runOnStop() {
+	l.rm.Lock()
+	fs := l.onStop
+	l.onStop = nil
+	l.stopped = true
+	l.rm.Unlock()
+	for i := len(fs) - 1; i >= 0; i-- {
+		fs[i]()
+	}
+}
+
+func (l *logger) Flush(ctx context.Context) error {
+	if err := l.root.q.flushContext(ctx); err != nil {
+		return err
+	}
+	l.root.reporters().Flush()
+	return nil
 }
 
-func (l *logger) Stop(time.Duration) {
-	close(l.root.c)
-	close(l.root.stopChan)
+// closeReporter flushes each of the Reporters that is a Flusher,
+// and closes each that is an io.Closer, once, returning the first
+// error.
+func (l *logger) closeReporter() error {
+	// wait for any log being reported by reportSync
+	l.rpm.Lock()
+	defer l.rpm.Unlock()
+	rs := l.reporters()
+	rs.Flush()
+	return rs.Close()
+}
+
+// reporters gets the Reporter, and those for each level, once
+// each.
+func (l *logger) reporters() reporters {
+	box := l.root.r.Load().(reporterBox)
+	rs := reporters{box.r}
+	for level := LevelNothing; level <= LevelEverything; level++ {
+		r, ok := box.levels[level]
+		if !ok {
+			continue
+		}
+		seen := false
+		for _, other := range rs {
+			seen = seen || sameReporter(r, other)
+		}
+		if !seen {
+			rs = append(rs, r)
+		}
+	}
+	return rs
+}
+
+// sameReporter gets whether a and b are the same Reporter.
+func sameReporter(a, b Reporter) bool {
+	ta, tb := reflect.TypeOf(a), reflect.TypeOf(b)
+	return ta == tb && ta != nil && ta.Comparable() && a == b
 }
 
 func (l *logger) StopChan() <-chan stop.Signal {
@@ -291,29 +1399,86 @@ type logReporter struct {
 
 // NewLogReporter gets a Reporter that writes to the specified
 // log.Logger.
-// If fatal is true, errors will call Fatalln on the logger, otherwise
-// they will always call Println.
+// If fatal is true, errors exit the program with status 1 once
+// they are written.
 func NewLogReporter(logger *log.Logger, fatal bool) Reporter {
-	return &logReporter{logger: logger}
+	return &logReporter{logger: logger, fatal: fatal}
 }
 
 func (l *logReporter) Log(log *Log) {
-	args := []interface{}{strings.Join(log.Source, nestedLogSep) + ":"}
-	for _, d := range log.Data {
-		args = append(args, d)
+	l.Report(log)
+}
+
+func (l *logReporter) Report(log *Log) error {
+	args := []interface{}{log.SourceString() + ":"}
+	if log.Caller != nil {
+		args = append(args, log.Caller.Function)
 	}
+	args = append(args, log.text()...)
 
+	err := l.logger.Output(2, lines(sprint(args), log.Stack)+"\n")
 	if l.fatal && log.Level == LevelErr {
-		l.logger.Fatalln(args...)
+		exit(1)
+	}
+	return err
+}
+
+// Stdout represents a reporter that writes to os.Stdout.
+var Stdout = NewLogReporter(log.New(os.Stdout, "", log.LstdFlags), false)
+
+// StdoutFatal represents a reporter that writes to os.Stdout, and
+// exits the program after writing an error.
+var StdoutFatal = NewLogReporter(log.New(os.Stdout, "", log.LstdFlags), true)
+
+// Stderr represents a reporter that writes to os.Stderr.
+var Stderr = NewLogReporter(log.New(os.Stderr, "", log.LstdFlags), false)
+
+// StdStreams represents a reporter that writes errors and
+// warnings to Stderr, and everything else to Stdout.
+var StdStreams = Split(LevelWarn, Stderr, Stdout)
+
+type split struct {
+	level  Level
+	severe Reporter
+	rest   Reporter
+}
+
+// Split makes a Reporter that reports logs at the level, or
+// more severe, to severe and all other logs to rest.
+func Split(level Level, severe, rest Reporter) Reporter {
+	return &split{level: level, severe: severe, rest: rest}
+}
+
+func (s *split) Log(l *Log) {
+	if l.Level <= s.level {
+		s.severe.Log(l)
 	} else {
-		l.logger.Println(args...)
+		s.rest.Log(l)
 	}
+}
 
+func (s *split) Close() error {
+	return reporters{s.severe, s.rest}.Close()
 }
 
-// Stdout represents a reporter that writes to os.Stdout.
-// Errors will also call os.Exit.
-var Stdout = NewLogReporter(log.New(os.Stdout, "", log.LstdFlags), true)
+func (s *split) Flush() {
+	reporters{s.severe, s.rest}.Flush()
+}
+
+func (s *split) Start() {
+	reporters{s.severe, s.rest}.Start()
+}
+
+// DiscardReporter represents a reporter that discards logs.
+var DiscardReporter Reporter = ReporterFunc(func(*Log) {})
+
+// Discard creates a new RootLogger that logs nothing, for giving
+// to code that needs a RootLogger when logs aren't wanted.
+// Unlike NilLogger it can be turned on later, by giving it a
+// level and a Reporter, and its children start logging too.
+func Discard(source string) RootLogger {
+	return NewWithOptions(source, WithLevel(LevelNothing), WithReporter(DiscardReporter))
+}
 
 type nilLogger struct{}
 
@@ -323,14 +1488,62 @@ var NilLogger nilLogger
 
 var _ RootLogger = (*nilLogger)(nil) // ensure nilLogger is a valid Logger
 
-func (n nilLogger) Debug(a ...interface{}) bool  { return false }
-func (n nilLogger) Info(a ...interface{}) bool   { return false }
-func (n nilLogger) Warn(a ...interface{}) bool   { return false }
-func (n nilLogger) Err(a ...interface{}) bool    { return false }
-func (n nilLogger) New(string) Logger            { return NilLogger }
-func (n nilLogger) SetSource(string)             {}
-func (n nilLogger) SetLevel(Level)               {}
-func (n nilLogger) SetReporter(Reporter)         {}
-func (n nilLogger) SetReporterFunc(ReporterFunc) {}
-func (n nilLogger) Stop(time.Duration)           {}
-func (n nilLogger) StopChan() <-chan stop.Signal { return nil }
+func (n nilLogger) Trace(a ...interface{}) bool               { return false }
+func (n nilLogger) Tracef(string, ...interface{}) bool        { return false }
+func (n nilLogger) Debugf(string, ...interface{}) bool        { return false }
+func (n nilLogger) Infof(string, ...interface{}) bool         { return false }
+func (n nilLogger) Warnf(string, ...interface{}) bool         { return false }
+func (n nilLogger) Errf(string, ...interface{}) bool          { return false }
+func (n nilLogger) Fatal(a ...interface{})                    { exit(1) }
+func (n nilLogger) Panic(a ...interface{})                    { panic(sprint(a)) }
+func (n nilLogger) CapturePanic()                             { recover() }
+func (n nilLogger) Debug(a ...interface{}) bool               { return false }
+func (n nilLogger) Info(a ...interface{}) bool                { return false }
+func (n nilLogger) Warn(a ...interface{}) bool                { return false }
+func (n nilLogger) Err(a ...interface{}) bool                 { return false }
+func (n nilLogger) New(string) Logger                         { return NilLogger }
+func (n nilLogger) Acquire(string) Logger                     { return NilLogger }
+func (n nilLogger) Release()                                  {}
+func (n nilLogger) WithFields(Fields) Logger                  { return NilLogger }
+func (n nilLogger) With(string, interface{}) Logger           { return NilLogger }
+func (n nilLogger) WithError(error) Logger                    { return NilLogger }
+func (n nilLogger) Close() error                              { return nil }
+func (n nilLogger) WithReporter(Reporter) Logger              { return NilLogger }
+func (n nilLogger) WithSource(string) Logger                  { return NilLogger }
+func (n nilLogger) Timed(string) *Timer                       { return &Timer{} }
+func (n nilLogger) Audit(...interface{}) error                { return nil }
+func (n nilLogger) InfoOnce(string, ...interface{}) bool      { return false }
+func (n nilLogger) WarnOnce(string, ...interface{}) bool      { return false }
+func (n nilLogger) ErrOnce(string, ...interface{}) bool       { return false }
+func (n nilLogger) InfoEvery(int, ...interface{}) bool        { return false }
+func (n nilLogger) WarnEvery(int, ...interface{}) bool        { return false }
+func (n nilLogger) ErrEvery(int, ...interface{}) bool         { return false }
+func (n nilLogger) SetSource(string)                          {}
+func (n nilLogger) SetSourceLevel(string, Level)              {}
+func (n nilLogger) SourceLevels() map[string]Level            { return nil }
+func (n nilLogger) SetBuffer(int, DropPolicy)                 {}
+func (n nilLogger) Dropped() uint64                           { return 0 }
+func (n nilLogger) SetErrorHandler(func(error))               {}
+func (n nilLogger) Pressure() float64                         { return 0 }
+func (n nilLogger) Stats() Stats                              { return Stats{} }
+func (n nilLogger) Counts() map[Level]uint64                  { return nil }
+func (n nilLogger) Subscribe(int) (<-chan *Log, func())       { return closedLogs, func() {} }
+func (n nilLogger) SetCaptureCaller(bool)                     {}
+func (n nilLogger) SetSync(bool)                              {}
+func (n nilLogger) SetCaptureStack(Level)                     {}
+func (n nilLogger) SetLevel(Level)                            {}
+func (n nilLogger) Level() Level                              { return LevelNothing }
+func (n nilLogger) SetReporter(Reporter)                      {}
+func (n nilLogger) SwapReporter(Reporter) Reporter            { return nil }
+func (n nilLogger) SetReporterForLevel(Level, Reporter)       {}
+func (n nilLogger) SetAuditReporter(Reporter)                 {}
+func (n nilLogger) SetClock(Clock)                            {}
+func (n nilLogger) Tree() Tree                                { return Tree{} }
+func (n nilLogger) SetReporterFunc(ReporterFunc)              {}
+func (n nilLogger) AddHook(Hook)                              {}
+func (n nilLogger) Stop(time.Duration)                        {}
+func (n nilLogger) StopContext(context.Context) error         { return nil }
+func (n nilLogger) OnStop(func())                             {}
+func (n nilLogger) Flush(context.Context) error               { return nil }
+func (n nilLogger) Boost(string, Level, time.Duration) func() { return func() {} }
+func (n nilLogger) StopChan() <-chan stop.Signal              { return nil }
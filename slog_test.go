@@ -2,8 +2,14 @@ package slog_test
 
 import (
 	"bytes"
+	"context"
+	"encoding/json"
 	"errors"
+	"flag"
+	"fmt"
 	"log"
+	"strconv"
+	"strings"
 	"sync"
 	"testing"
 	"time"
@@ -33,6 +39,7 @@ func NewTestReporter() *TestReporter {
 func TestLog(t *testing.T) {
 
 	l := slog.New("parent", slog.LevelErr)
+	l.SetSync(true)
 	defer func() {
 		l.Stop(stop.NoWait)
 		<-l.StopChan()
@@ -51,6 +58,7 @@ func TestLog(t *testing.T) {
 	require.Equal(t, 1, len(r.logs))
 
 	require.Equal(t, "parent", r.logs[0].Source[0])
+	require.Contains(t, r.logs[0].Data[0], "slog_test.go:")
 	require.Equal(t, "Something went", r.logs[0].Data[1])
 	require.Equal(t, "wrong", r.logs[0].Data[2])
 	require.Equal(t, slog.LevelErr, r.logs[0].Level)
@@ -58,12 +66,44 @@ func TestLog(t *testing.T) {
 
 }
 
+func TestLogMessage(t *testing.T) {
+
+	l := slog.New("parent", slog.LevelInfo)
+	l.SetSync(true)
+	r := NewTestReporter()
+	l.SetReporter(r)
+
+	l.Info("Something went", "wrong", 1)
+	require.True(t, strings.HasPrefix(r.logs[0].Location(), "slog_test.go:"))
+	require.Equal(t, "Something went wrong 1", r.logs[0].Message())
+
+	// messages that look like a location are kept
+	l.Info("( ok )", 2)
+	require.Equal(t, "( ok ) 2", r.logs[1].Message())
+
+	// a hook replacing the Data replaces the location too
+	l.AddHook(func(log *slog.Log) *slog.Log {
+		log.Data = []interface{}{"replaced"}
+		return log
+	})
+	l.Info("original")
+	require.Equal(t, "replaced", r.logs[2].Message())
+
+	// logs built by hand have no location
+	log := &slog.Log{Data: []interface{}{"( main.go:12 )", 2}}
+	require.Equal(t, "", log.Location())
+	require.Equal(t, "( main.go:12 ) 2", log.Message())
+	require.Equal(t, "", (&slog.Log{}).Message())
+
+}
+
 func TestLevelStrings(t *testing.T) {
 
 	require.Equal(t, slog.LevelDebug.String(), "debug")
 	require.Equal(t, slog.LevelInfo.String(), "info")
 	require.Equal(t, slog.LevelErr.String(), "error")
 	require.Equal(t, slog.LevelWarn.String(), "warning")
+	require.Equal(t, slog.LevelTrace.String(), "trace")
 
 	require.Equal(t, slog.LevelDebug, slog.ParseLevel("debug"))
 	require.Equal(t, slog.LevelInfo, slog.ParseLevel("info"))
@@ -73,6 +113,36 @@ func TestLevelStrings(t *testing.T) {
 	require.Equal(t, slog.LevelInfo, slog.ParseLevel("i"))
 	require.Equal(t, slog.LevelErr, slog.ParseLevel("e"))
 	require.Equal(t, slog.LevelWarn, slog.ParseLevel("w"))
+	require.Equal(t, slog.LevelTrace, slog.ParseLevel("trace"))
+	require.Equal(t, slog.LevelTrace, slog.ParseLevel("t"))
+
+}
+
+func TestLevelText(t *testing.T) {
+
+	require.Equal(t, slog.LevelWarn, slog.ParseLevel(" WARNING "))
+	require.Equal(t, slog.LevelEverything, slog.ParseLevel("everything"))
+	require.Equal(t, slog.LevelNothing, slog.ParseLevel("none"))
+	require.Equal(t, slog.LevelInvalid, slog.ParseLevel(""))
+	require.Equal(t, slog.LevelInvalid, slog.ParseLevel("loud"))
+
+	var config struct {
+		Level slog.Level `json:"level"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(`{"level":"debug"}`), &config))
+	require.Equal(t, slog.LevelDebug, config.Level)
+	b, err := json.Marshal(config)
+	require.NoError(t, err)
+	require.Equal(t, `{"level":"debug"}`, string(b))
+	require.Error(t, json.Unmarshal([]byte(`{"level":"loud"}`), &config))
+	_, err = slog.LevelInvalid.MarshalText()
+	require.Error(t, err)
+
+	level := slog.LevelInfo
+	flags := flag.NewFlagSet("test", flag.ContinueOnError)
+	flags.Var(&level, "level", "")
+	require.NoError(t, flags.Parse([]string{"-level", "err"}))
+	require.Equal(t, slog.LevelErr, level)
 
 }
 
@@ -114,6 +184,167 @@ func TestSetSource(t *testing.T) {
 
 }
 
+func TestSetSourceKeepsLogs(t *testing.T) {
+
+	p := slog.New("parent", slog.LevelErr)
+	p.SetSync(true)
+	defer func() {
+		p.Stop(stop.NoWait)
+		<-p.StopChan()
+	}()
+	r := NewTestReporter()
+	p.SetReporter(r)
+
+	l := p.New("child")
+	grandchild := l.New("grandchild")
+	l.Err("before")
+	l.SetSource("renamed")
+	l.Err("after")
+	grandchild.Err("grandchild")
+
+	require.Equal(t, 3, len(r.logs))
+	require.Equal(t, slog.Source{"parent", "child"}, r.logs[0].Source)
+	require.Equal(t, slog.Source{"parent", "renamed"}, r.logs[1].Source)
+	require.Equal(t, slog.Source{"parent", "child", "grandchild"}, r.logs[2].Source)
+
+}
+
+func TestSiblingSources(t *testing.T) {
+
+	p := slog.New("parent", slog.LevelErr)
+	p.SetSync(true)
+	defer func() {
+		p.Stop(stop.NoWait)
+		<-p.StopChan()
+	}()
+	r := NewTestReporter()
+	p.SetReporter(r)
+
+	// a source with spare capacity would be shared by siblings made
+	// by appending to it
+	l := p.New("a").New("b").New("c")
+	first := l.New("first")
+	second := l.New("second")
+	first.Err("one")
+	second.Err("two")
+
+	require.Equal(t, 2, len(r.logs))
+	require.Equal(t, slog.Source{"parent", "a", "b", "c", "first"}, r.logs[0].Source)
+	require.Equal(t, slog.Source{"parent", "a", "b", "c", "second"}, r.logs[1].Source)
+
+}
+
+func TestWithSource(t *testing.T) {
+
+	p := slog.New("parent", slog.LevelErr)
+	p.SetSync(true)
+	defer func() {
+		p.Stop(stop.NoWait)
+		<-p.StopChan()
+	}()
+	r := NewTestReporter()
+	p.SetReporter(r)
+
+	l := p.New("child").With("k", "v")
+	other := l.WithSource("other")
+	l.Err("one")
+	other.Err("two")
+
+	require.Equal(t, 2, len(r.logs))
+	require.Equal(t, slog.Source{"parent", "child"}, r.logs[0].Source)
+	require.Equal(t, slog.Source{"parent", "other"}, r.logs[1].Source)
+	require.Equal(t, slog.Fields{"k": "v"}, r.logs[1].Fields)
+
+}
+
+func TestWithReporter(t *testing.T) {
+
+	p := slog.New("parent", slog.LevelErr)
+	p.SetSync(true)
+	defer func() {
+		p.Stop(stop.NoWait)
+		<-p.StopChan()
+	}()
+	r := NewTestReporter()
+	p.SetReporter(r)
+
+	job := NewTestReporter()
+	l := p.New("job").WithReporter(job)
+	l.Err("one")
+	l.New("step").With("k", "v").Err("two")
+	p.New("other").Err("three")
+
+	require.Equal(t, 3, len(r.logs))
+	require.Equal(t, 2, len(job.logs))
+	require.Equal(t, slog.Source{"parent", "job"}, job.logs[0].Source)
+	require.Equal(t, slog.Source{"parent", "job", "step"}, job.logs[1].Source)
+
+}
+
+func TestClose(t *testing.T) {
+
+	p := slog.New("parent", slog.LevelErr)
+	p.SetBuffer(10, slog.BlockWhenFull)
+	r := NewTestReporter()
+	p.SetReporter(r)
+
+	job := &closeCounter{TestReporter: NewTestReporter()}
+	l := p.New("job")
+	jl := l.WithReporter(job)
+	view := l.With("k", "v")
+	require.Equal(t, int64(2), p.Stats().Children)
+
+	require.True(t, jl.Err("one"))
+	require.NoError(t, jl.Close())
+	require.Equal(t, 1, len(job.logs))
+	require.Equal(t, 1, job.closed)
+	require.False(t, jl.Err("two"))
+	require.NoError(t, jl.Close())
+	require.Equal(t, 1, job.closed)
+	require.Equal(t, int64(1), p.Stats().Children)
+
+	require.NoError(t, view.Close())
+	require.Equal(t, int64(1), p.Stats().Children)
+	require.True(t, l.Err("three"))
+	require.NoError(t, l.Close())
+	require.Equal(t, int64(0), p.Stats().Children)
+
+	require.NoError(t, p.Close())
+	<-p.StopChan()
+	require.Equal(t, 2, len(r.logs))
+	require.Equal(t, int64(0), p.Stats().Children)
+
+}
+
+func TestSetSourceConcurrently(t *testing.T) {
+
+	p := slog.New("parent", slog.LevelErr)
+	defer func() {
+		p.Stop(stop.NoWait)
+		<-p.StopChan()
+	}()
+	p.SetReporter(slog.DiscardReporter)
+
+	l := p.New("child")
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 100; i++ {
+			l.SetSource("child" + strconv.Itoa(i))
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 100; i++ {
+			l.Err("log")
+			l.New("grandchild")
+		}
+	}()
+	wg.Wait()
+
+}
+
 func TestLogChildren(t *testing.T) {
 
 	var wg sync.WaitGroup
@@ -196,12 +427,19 @@ func TestLevels(t *testing.T) {
 	require.True(t, logger.Err())
 
 	logger.SetLevel(slog.LevelDebug)
+	require.False(t, logger.Trace())
 	require.True(t, logger.Debug())
 	require.True(t, logger.Info())
 	require.True(t, logger.Warn())
 	require.True(t, logger.Err())
 
+	logger.SetLevel(slog.LevelTrace)
+	require.True(t, logger.Trace())
+	require.True(t, logger.Debug())
+	require.True(t, logger.Info())
+
 	logger.SetLevel(slog.LevelEverything)
+	require.True(t, logger.Trace())
 	require.True(t, logger.Info())
 	require.True(t, logger.Warn())
 	require.True(t, logger.Err())
@@ -214,21 +452,49 @@ func TestLogReporter(t *testing.T) {
 	logLogger := log.New(&buf, "prefix: ", log.LstdFlags)
 
 	logger := slog.New("parent", slog.LevelEverything)
+	logger.SetSync(true)
 	logger.SetReporter(slog.NewLogReporter(logLogger, false))
 	child := logger.New("child")
 	child.Info(errors.New("message"))
 
-	time.Sleep(500 * time.Millisecond)
-
 	require.Contains(t, buf.String(), `message`)
 	require.Contains(t, buf.String(), "parent>child:")
 	require.Contains(t, buf.String(), `prefix:`)
 
 }
 
+func TestSync(t *testing.T) {
+
+	l := slog.NewWithOptions("parent", slog.WithBuffer(10), slog.WithSync(true))
+	r := NewTestReporter()
+	l.SetReporterFunc(func(item *slog.Log) {
+		time.Sleep(10 * time.Millisecond)
+		r.Log(item)
+	})
+
+	require.True(t, l.Info("one"))
+	require.Len(t, r.logs, 1)
+	require.True(t, l.New("child").Warn("two"))
+	require.Len(t, r.logs, 2)
+	require.Equal(t, uint64(2), l.Stats().Reported)
+	require.Equal(t, map[slog.Level]uint64{slog.LevelErr: 0, slog.LevelWarn: 1, slog.LevelInfo: 1, slog.LevelDebug: 0, slog.LevelTrace: 0}, l.Counts())
+
+	l.SetSync(false)
+	require.True(t, l.Info("three"))
+	l.SetSync(true)
+	require.Len(t, r.logs, 3)
+
+	require.NoError(t, l.StopContext(context.Background()))
+	require.False(t, l.Info("after stop"))
+	require.Len(t, r.logs, 3)
+	require.Equal(t, uint64(1), l.Stats().Late)
+
+}
+
 func TestReporterFunc(t *testing.T) {
 
 	l := slog.New("parent", slog.LevelErr)
+	l.SetSync(true)
 	defer func() {
 		l.Stop(stop.NoWait)
 		<-l.StopChan()
@@ -283,3 +549,670 @@ func TestMultipleReporters(t *testing.T) {
 	require.Equal(t, l, logs3[0])
 
 }
+
+func TestAtLevel(t *testing.T) {
+
+	errs, infos := NewTestReporter(), NewTestReporter()
+	r := slog.Reporters(slog.AtLevel(slog.LevelErr, errs), slog.AtLevel(slog.LevelInfo, infos))
+
+	r.Log(&slog.Log{Level: slog.LevelErr})
+	r.Log(&slog.Log{Level: slog.LevelWarn})
+	r.Log(&slog.Log{Level: slog.LevelInfo})
+	r.Log(&slog.Log{Level: slog.LevelDebug})
+
+	require.Equal(t, 1, len(errs.logs))
+	require.Equal(t, slog.LevelErr, errs.logs[0].Level)
+	require.Equal(t, 3, len(infos.logs))
+	require.Equal(t, slog.LevelInfo, infos.logs[2].Level)
+
+}
+
+func TestSplit(t *testing.T) {
+
+	severe, rest := NewTestReporter(), NewTestReporter()
+	r := slog.Split(slog.LevelWarn, severe, rest)
+
+	r.Log(&slog.Log{Level: slog.LevelErr})
+	r.Log(&slog.Log{Level: slog.LevelWarn})
+	r.Log(&slog.Log{Level: slog.LevelInfo})
+	r.Log(&slog.Log{Level: slog.LevelDebug})
+
+	require.Equal(t, 2, len(severe.logs))
+	require.Equal(t, slog.LevelWarn, severe.logs[1].Level)
+	require.Equal(t, 2, len(rest.logs))
+	require.Equal(t, slog.LevelInfo, rest.logs[0].Level)
+
+}
+
+type closeReporter struct {
+	*TestReporter
+	closed bool
+}
+
+func (r *closeReporter) Close() error {
+	r.closed = true
+	return nil
+}
+
+func TestStopContext(t *testing.T) {
+
+	l := slog.New("parent", slog.LevelInfo)
+	r := &closeReporter{TestReporter: NewTestReporter()}
+	l.SetReporter(slog.Reporters(r))
+
+	require.True(t, l.Info("before stop"))
+	require.NoError(t, l.StopContext(context.Background()))
+	<-l.StopChan()
+
+	require.Equal(t, 1, len(r.logs))
+	require.True(t, r.closed)
+	require.False(t, l.Info("after stop"))
+	require.False(t, l.New("child").Warn("after stop"))
+	require.Equal(t, 1, len(r.logs))
+	require.Equal(t, uint64(2), l.Stats().Late)
+
+	// stopping again is safe
+	require.NoError(t, l.StopContext(context.Background()))
+	l.Stop(stop.NoWait)
+
+}
+
+func TestStopContextTimeout(t *testing.T) {
+
+	l := slog.New("parent", slog.LevelInfo)
+	block := make(chan struct{})
+	logging := make(chan struct{})
+	r := &closeReporter{TestReporter: NewTestReporter()}
+	r.logFunc = func(*slog.Log) {
+		close(logging)
+		<-block
+	}
+	l.SetReporter(r)
+
+	l.SetBuffer(10, slog.BlockWhenFull)
+	l.Info("blocks the reporter")
+	<-logging
+	l.Info("abandoned")
+	l.Info("abandoned")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	err := l.StopContext(ctx)
+	require.True(t, errors.Is(err, context.DeadlineExceeded))
+	var stopErr *slog.StopError
+	require.True(t, errors.As(err, &stopErr))
+	require.Equal(t, 2, stopErr.Abandoned)
+	<-l.StopChan()
+	require.False(t, r.closed)
+
+	close(block)
+
+}
+
+type formatCounter int
+
+func (f *formatCounter) String() string {
+	*f++
+	return "formatted"
+}
+
+func TestLogf(t *testing.T) {
+
+	var wg sync.WaitGroup
+
+	l := slog.New("parent", slog.LevelWarn)
+	defer func() {
+		l.Stop(stop.NoWait)
+		<-l.StopChan()
+	}()
+	r := NewTestReporter()
+	f := r.logFunc
+	r.logFunc = func(l *slog.Log) {
+		f(l)
+		wg.Done()
+	}
+	l.SetReporter(r)
+
+	var count formatCounter
+	require.False(t, l.Infof("ignored %s", &count))
+	require.Equal(t, formatCounter(0), count)
+
+	wg.Add(2)
+	require.True(t, l.Warnf("%s %d", &count, 1))
+	require.True(t, l.Errf("failed: %v", "oops"))
+	wg.Wait()
+
+	require.Equal(t, formatCounter(1), count)
+	require.Equal(t, 2, len(r.logs))
+	require.Contains(t, r.logs[0].Data[0], "slog_test.go:")
+	require.Equal(t, "formatted 1", r.logs[0].Data[1])
+	require.Equal(t, slog.LevelWarn, r.logs[0].Level)
+	require.Equal(t, "failed: oops", r.logs[1].Data[1])
+	require.Equal(t, slog.LevelErr, r.logs[1].Level)
+
+}
+
+func TestChildLevels(t *testing.T) {
+
+	root := slog.New("parent", slog.LevelInfo)
+	defer func() {
+		root.Stop(stop.NoWait)
+		<-root.StopChan()
+	}()
+	root.SetReporter(NewTestReporter())
+
+	http := root.New("http")
+	handler := http.New("handler")
+	db := root.New("db")
+
+	http.SetLevel(slog.LevelErr)
+	require.True(t, root.Info())
+	require.False(t, http.Info())
+	require.True(t, http.Err())
+	require.False(t, handler.Info())
+	require.True(t, db.Info())
+
+	handler.SetLevel(slog.LevelDebug)
+	require.True(t, handler.Debug())
+	require.False(t, http.Info())
+
+	// children without their own level follow the root
+	root.SetLevel(slog.LevelErr)
+	require.False(t, db.Info())
+	require.True(t, handler.Debug())
+
+	http.SetLevel(slog.LevelInvalid)
+	handler.SetLevel(slog.LevelInvalid)
+	root.SetLevel(slog.LevelInfo)
+	require.True(t, http.Info())
+	require.False(t, handler.Debug())
+
+	root.SetSourceLevel("parent>db", slog.LevelNothing)
+	require.False(t, db.Err())
+
+}
+
+func TestOnStop(t *testing.T) {
+
+	l := slog.New("parent", slog.LevelInfo)
+	l.SetBuffer(10, slog.BlockWhenFull)
+	r := NewTestReporter()
+	l.SetReporter(r)
+
+	var calls []string
+	l.OnStop(func() { calls = append(calls, "first") })
+	l.OnStop(func() {
+		calls = append(calls, fmt.Sprint("second after ", len(r.logs), " logs"))
+	})
+	l.Info("one")
+	l.Info("two")
+	require.NoError(t, l.StopContext(context.Background()))
+	<-l.StopChan()
+	require.Equal(t, []string{"second after 2 logs", "first"}, calls)
+
+	l.OnStop(func() { calls = append(calls, "late") })
+	require.Equal(t, []string{"second after 2 logs", "first", "late"}, calls)
+
+}
+
+func TestStopWait(t *testing.T) {
+
+	l := slog.New("parent", slog.LevelInfo)
+	r := newBlockingReporter(3)
+	l.SetReporter(r)
+	l.SetBuffer(10, slog.BlockWhenFull)
+
+	l.Info("1")
+	<-r.logging
+	l.Info("2")
+	l.Info("3")
+	l.Stop(time.Second)
+	require.False(t, l.Info("4"))
+
+	time.AfterFunc(10*time.Millisecond, func() { close(r.release) })
+	<-l.StopChan()
+	require.Equal(t, []string{"1", "2", "3"}, r.logs)
+
+}
+
+func TestStopTimeout(t *testing.T) {
+
+	l := slog.New("parent", slog.LevelInfo)
+	r := newBlockingReporter(3)
+	defer close(r.release)
+	l.SetReporter(r)
+	l.SetBuffer(10, slog.BlockWhenFull)
+
+	l.Info("1")
+	<-r.logging
+	l.Info("2")
+
+	start := time.Now()
+	l.Stop(20 * time.Millisecond)
+	<-l.StopChan()
+	require.True(t, time.Since(start) >= 20*time.Millisecond)
+
+}
+
+// blockingReporter is a reporter that records logs, blocking on the
+// first one until release is closed.
+type blockingReporter struct {
+	m       sync.Mutex
+	logs    []string
+	logging chan struct{}
+	release chan struct{}
+	done    chan struct{}
+	want    int
+}
+
+func newBlockingReporter(want int) *blockingReporter {
+	return &blockingReporter{
+		logging: make(chan struct{}),
+		release: make(chan struct{}),
+		done:    make(chan struct{}),
+		want:    want,
+	}
+}
+
+func (r *blockingReporter) Log(l *slog.Log) {
+	r.m.Lock()
+	r.logs = append(r.logs, l.Data[1].(string))
+	n := len(r.logs)
+	r.m.Unlock()
+	if n == 1 {
+		close(r.logging)
+		<-r.release
+	}
+	if n == r.want {
+		close(r.done)
+	}
+}
+
+func TestBufferDropNewest(t *testing.T) {
+
+	l := slog.New("parent", slog.LevelInfo)
+	defer func() {
+		l.Stop(stop.NoWait)
+		<-l.StopChan()
+	}()
+	r := newBlockingReporter(3)
+	l.SetReporter(r)
+	l.SetBuffer(2, slog.DropNewest)
+
+	l.Info("1")
+	<-r.logging
+	for _, d := range []string{"2", "3", "4", "5"} {
+		require.True(t, l.Info(d))
+	}
+	require.Equal(t, uint64(2), l.Dropped())
+	close(r.release)
+	<-r.done
+
+	require.Equal(t, []string{"1", "2", "3"}, r.logs)
+
+}
+
+func TestBufferDropOldest(t *testing.T) {
+
+	l := slog.New("parent", slog.LevelInfo)
+	defer func() {
+		l.Stop(stop.NoWait)
+		<-l.StopChan()
+	}()
+	r := newBlockingReporter(3)
+	l.SetReporter(r)
+	l.SetBuffer(2, slog.DropOldest)
+
+	l.Info("1")
+	<-r.logging
+	for _, d := range []string{"2", "3", "4", "5"} {
+		require.True(t, l.Info(d))
+	}
+	require.Equal(t, uint64(2), l.Dropped())
+	close(r.release)
+	<-r.done
+
+	require.Equal(t, []string{"1", "4", "5"}, r.logs)
+
+}
+
+func TestPressure(t *testing.T) {
+
+	l := slog.New("parent", slog.LevelInfo)
+	defer func() {
+		l.Stop(stop.NoWait)
+		<-l.StopChan()
+	}()
+	r := newBlockingReporter(5)
+	l.SetReporter(r)
+	l.SetBuffer(4, slog.DropNewest)
+	require.Equal(t, 0.0, l.Pressure())
+
+	l.Info("1")
+	<-r.logging
+	l.Info("2")
+	require.Equal(t, 0.25, l.Pressure())
+	l.Info("3")
+	l.Info("4")
+	l.Info("5")
+	l.Info("6")
+	require.Equal(t, 1.0, l.Pressure())
+	require.Equal(t, 1.0, l.Stats().Pressure)
+	close(r.release)
+	<-r.done
+	require.Equal(t, 0.0, l.Pressure())
+	require.Equal(t, 0.0, slog.NilLogger.Pressure())
+
+}
+
+func TestBufferBlockWhenFull(t *testing.T) {
+
+	l := slog.New("parent", slog.LevelInfo)
+	defer func() {
+		l.Stop(stop.NoWait)
+		<-l.StopChan()
+	}()
+	r := newBlockingReporter(3)
+	l.SetReporter(r)
+	l.SetBuffer(1, slog.BlockWhenFull)
+
+	l.Info("1")
+	<-r.logging
+	l.Info("2")
+	logged := make(chan struct{})
+	go func() {
+		l.Info("3")
+		close(logged)
+	}()
+
+	select {
+	case <-logged:
+		require.Fail(t, "should block while the buffer is full")
+	case <-time.After(50 * time.Millisecond):
+	}
+	close(r.release)
+	<-logged
+	<-r.done
+
+	require.Equal(t, uint64(0), l.Dropped())
+	require.Equal(t, []string{"1", "2", "3"}, r.logs)
+
+}
+
+func TestCaptureCaller(t *testing.T) {
+
+	var wg sync.WaitGroup
+
+	l := slog.New("parent", slog.LevelInfo)
+	defer func() {
+		l.Stop(stop.NoWait)
+		<-l.StopChan()
+	}()
+	r := NewTestReporter()
+	f := r.logFunc
+	r.logFunc = func(l *slog.Log) {
+		f(l)
+		wg.Done()
+	}
+	l.SetReporter(r)
+
+	wg.Add(1)
+	l.Info("without caller")
+	wg.Wait()
+	require.Nil(t, r.logs[0].Caller)
+
+	l.SetCaptureCaller(true)
+	wg.Add(1)
+	l.New("child").Infof("with %s", "caller")
+	wg.Wait()
+	caller := r.logs[1].Caller
+	require.NotNil(t, caller)
+	require.Equal(t, "github.com/stretchr/slog_test.TestCaptureCaller", caller.Function)
+	require.True(t, strings.HasSuffix(caller.File, "/slog_test.go"))
+	require.Equal(t, r.logs[1].Data[0], "( "+caller.String()+" )")
+
+	var buf bytes.Buffer
+	slog.NewLogReporter(log.New(&buf, "", 0), false).Log(r.logs[1])
+	require.Equal(t, "parent>child: github.com/stretchr/slog_test.TestCaptureCaller "+r.logs[1].Data[0].(string)+" with caller\n", buf.String())
+
+}
+
+func TestCaptureStack(t *testing.T) {
+
+	var wg sync.WaitGroup
+
+	l := slog.New("parent", slog.LevelInfo)
+	defer func() {
+		l.Stop(stop.NoWait)
+		<-l.StopChan()
+	}()
+	r := NewTestReporter()
+	f := r.logFunc
+	r.logFunc = func(l *slog.Log) {
+		f(l)
+		wg.Done()
+	}
+	l.SetReporter(r)
+
+	wg.Add(1)
+	l.Err("without stack")
+	wg.Wait()
+	require.Equal(t, "", r.logs[0].Stack)
+
+	l.SetCaptureStack(slog.LevelWarn)
+	wg.Add(3)
+	l.Err("with stack")
+	l.Warnf("with %s", "stack")
+	l.Info("without stack")
+	wg.Wait()
+	for _, log := range r.logs[1:3] {
+		require.True(t, strings.HasPrefix(log.Stack, "github.com/stretchr/slog_test.TestCaptureStack()\n\t"))
+		require.False(t, strings.Contains(log.Stack, "slog.(*logger)"))
+	}
+	require.Equal(t, "", r.logs[3].Stack)
+
+	l.SetCaptureStack(slog.LevelInvalid)
+	wg.Add(1)
+	l.Err("without stack")
+	wg.Wait()
+	require.Equal(t, "", r.logs[4].Stack)
+
+}
+
+func TestFatal(t *testing.T) {
+
+	var code int
+	defer slog.SetExit(func(c int) { code = c })()
+
+	l := slog.New("parent", slog.LevelInfo)
+	r := newBlockingReporter(2)
+	l.SetReporter(r)
+	l.SetBuffer(10, slog.BlockWhenFull)
+
+	l.Info("1")
+	<-r.logging
+	time.AfterFunc(10*time.Millisecond, func() { close(r.release) })
+	l.New("child").Fatal("2")
+
+	// everything is reported before exiting
+	require.Equal(t, 1, code)
+	require.Equal(t, []string{"1", "2"}, r.logs)
+	<-l.StopChan()
+
+}
+
+func TestPanic(t *testing.T) {
+
+	l := slog.New("parent", slog.LevelInfo)
+	defer func() {
+		l.Stop(stop.NoWait)
+		<-l.StopChan()
+	}()
+	r := newBlockingReporter(2)
+	l.SetReporter(r)
+	l.SetBuffer(10, slog.BlockWhenFull)
+
+	l.Info("1")
+	<-r.logging
+	time.AfterFunc(10*time.Millisecond, func() { close(r.release) })
+	require.PanicsWithValue(t, "something went wrong", func() {
+		l.Panic("something went", "wrong")
+	})
+
+	// the log is reported before panicking
+	require.Equal(t, []string{"1", "something went"}, r.logs)
+	require.True(t, l.Info("still logging"))
+
+}
+
+func TestDiscard(t *testing.T) {
+
+	l := slog.Discard("parent")
+	child := l.New("child")
+	require.False(t, child.Info("discarded"))
+
+	r := NewTestReporter()
+	l.SetReporter(r)
+	l.SetLevel(slog.LevelInfo)
+	require.True(t, child.Info("reported"))
+	require.NoError(t, l.StopContext(context.Background()))
+
+	require.Equal(t, 1, len(r.logs))
+	require.Equal(t, "reported", r.logs[0].Data[1])
+
+}
+
+func TestNilLoggerFatal(t *testing.T) {
+
+	var code int
+	defer slog.SetExit(func(c int) { code = c })()
+
+	slog.NilLogger.Fatal("exits anyway")
+	require.Equal(t, 1, code)
+	require.Panics(t, func() { slog.NilLogger.Panic("panics anyway") })
+
+}
+
+func TestLogReporterFatal(t *testing.T) {
+
+	code := -1
+	defer slog.SetExit(func(c int) { code = c })()
+
+	var buf bytes.Buffer
+	errLog := &slog.Log{Level: slog.LevelErr, Source: slog.Source{"parent"}, Data: []interface{}{"failed"}}
+	slog.NewLogReporter(log.New(&buf, "", 0), false).Log(errLog)
+	require.Equal(t, -1, code)
+	require.Equal(t, "parent: failed\n", buf.String())
+
+	r := slog.NewLogReporter(log.New(&buf, "", 0), true)
+	r.Log(&slog.Log{Level: slog.LevelInfo, Source: slog.Source{"parent"}, Data: []interface{}{"fine"}})
+	require.Equal(t, -1, code)
+	r.Log(errLog)
+	require.Equal(t, 1, code)
+
+	// the error is written before exiting
+	require.Equal(t, "parent: failed\nparent: fine\nparent: failed\n", buf.String())
+
+}
+
+func TestSetLevelConcurrently(t *testing.T) {
+
+	l := slog.Discard("parent")
+	child := l.New("child")
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 1000; i++ {
+			child.Info("message")
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 1000; i++ {
+			l.SetLevel(slog.Level(i % int(slog.LevelEverything)))
+			l.SetSourceLevel("parent>child", slog.LevelDebug)
+			l.SetSourceLevel("parent>child", slog.LevelInvalid)
+		}
+	}()
+	wg.Wait()
+
+	l.SetLevel(slog.LevelWarn)
+	require.Equal(t, slog.LevelWarn, l.Level())
+	require.False(t, child.Info())
+	require.True(t, child.Warn())
+
+	l.SetSourceLevel("parent>child", slog.LevelInfo)
+	require.True(t, child.Info())
+	require.False(t, l.Info())
+
+}
+
+func TestSwapReporter(t *testing.T) {
+
+	l := slog.New("parent", slog.LevelInfo)
+	first := NewTestReporter()
+	l.SetReporter(first)
+
+	var m sync.Mutex
+	var second []*slog.Log
+	old := l.SwapReporter(slog.ReporterFunc(func(log *slog.Log) {
+		m.Lock()
+		second = append(second, log)
+		m.Unlock()
+	}))
+	require.Equal(t, first, old)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 100; i++ {
+				l.Info("message")
+			}
+		}()
+	}
+	wg.Wait()
+	require.NoError(t, l.StopContext(context.Background()))
+
+	require.Equal(t, 0, len(first.logs))
+	require.Equal(t, 400, len(second))
+
+}
+
+type closeCounter struct {
+	*TestReporter
+	closed int
+}
+
+func (c *closeCounter) Close() error {
+	c.closed++
+	return nil
+}
+
+func TestSetReporterForLevel(t *testing.T) {
+
+	l := slog.New("parent", slog.LevelDebug)
+	rest := NewTestReporter()
+	errs := &closeCounter{TestReporter: NewTestReporter()}
+	l.SetReporter(rest)
+	l.SetReporterForLevel(slog.LevelErr, errs)
+	l.SetReporterForLevel(slog.LevelWarn, errs)
+	l.SetReporterForLevel(slog.LevelDebug, errs)
+	l.SetReporterForLevel(slog.LevelDebug, nil)
+
+	l.Err("one")
+	l.Warn("two")
+	l.Info("three")
+	l.Debug("four")
+	require.NoError(t, l.StopContext(context.Background()))
+
+	require.Equal(t, 2, len(errs.logs))
+	require.Equal(t, 2, len(rest.logs))
+	require.Equal(t, "one", errs.logs[0].Data[1])
+	require.Equal(t, "four", rest.logs[1].Data[1])
+	require.Equal(t, 1, errs.closed)
+
+}
@@ -0,0 +1,85 @@
+// Package sloggrpc provides gRPC server interceptors that give each
+// RPC its own child slog.Logger.
+package sloggrpc
+
+import (
+	"context"
+	"time"
+
+	"github.com/stretchr/slog"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// UnaryServerInterceptor gets a grpc.UnaryServerInterceptor that
+// creates a child of l for each RPC, with the full method as its
+// source, stores it in the context (see slog.FromContext), and logs
+// when the RPC finishes with its code, duration and peer. The child
+// is closed once the RPC is done.
+func UnaryServerInterceptor(l slog.Logger) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		rl := l.New(info.FullMethod)
+		defer rl.Close()
+		start := time.Now()
+		resp, err := handler(slog.NewContext(ctx, rl), req)
+		finished(ctx, rl, start, err)
+		return resp, err
+	}
+}
+
+// StreamServerInterceptor gets a grpc.StreamServerInterceptor that
+// creates a child of l for each stream, as UnaryServerInterceptor
+// does for each RPC.
+func StreamServerInterceptor(l slog.Logger) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx := ss.Context()
+		rl := l.New(info.FullMethod)
+		defer rl.Close()
+		start := time.Now()
+		err := handler(srv, &serverStream{ServerStream: ss, ctx: slog.NewContext(ctx, rl)})
+		finished(ctx, rl, start, err)
+		return err
+	}
+}
+
+// finished logs that the RPC finished, as an error if the server
+// failed.
+func finished(ctx context.Context, l slog.Logger, start time.Time, err error) {
+	code := status.Code(err)
+	fields := slog.Fields{
+		"code":     code.String(),
+		"duration": time.Since(start),
+	}
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		fields["peer"] = p.Addr.String()
+	}
+	if serverError(code) {
+		l.Err("finished", fields, slog.Error(err))
+	} else {
+		l.Info("finished", fields)
+	}
+}
+
+// serverError gets whether the code means the server failed,
+// rather than the client making a bad request.
+func serverError(code codes.Code) bool {
+	switch code {
+	case codes.Unknown, codes.DeadlineExceeded, codes.Unimplemented,
+		codes.Internal, codes.Unavailable, codes.DataLoss:
+		return true
+	}
+	return false
+}
+
+// serverStream is a grpc.ServerStream with the context holding
+// the logger.
+type serverStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *serverStream) Context() context.Context {
+	return s.ctx
+}
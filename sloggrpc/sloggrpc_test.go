@@ -0,0 +1,112 @@
+package sloggrpc_test
+
+import (
+	"context"
+	"errors"
+	"net"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/slog"
+	"github.com/stretchr/slog/sloggrpc"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+func newLogger() (slog.RootLogger, func() []*slog.Log) {
+	var m sync.Mutex
+	var logs []*slog.Log
+	l := slog.New("server", slog.LevelInfo)
+	l.SetReporterFunc(func(log *slog.Log) {
+		m.Lock()
+		logs = append(logs, log)
+		m.Unlock()
+	})
+	return l, func() []*slog.Log {
+		m.Lock()
+		defer m.Unlock()
+		return logs
+	}
+}
+
+func TestUnaryServerInterceptor(t *testing.T) {
+
+	l, logs := newLogger()
+	intercept := sloggrpc.UnaryServerInterceptor(l)
+	info := &grpc.UnaryServerInfo{FullMethod: "/tea.Pot/Brew"}
+	ctx := peer.NewContext(context.Background(), &peer.Peer{Addr: &net.TCPAddr{IP: net.IPv4(10, 0, 0, 1), Port: 1234}})
+
+	resp, err := intercept(ctx, "earl grey", info, func(ctx context.Context, req interface{}) (interface{}, error) {
+		slog.FromContext(ctx).Info("brewing", req)
+		return "tea", nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, "tea", resp)
+
+	failed := status.Error(codes.Internal, "kettle broke")
+	_, err = intercept(context.Background(), "oolong", info, func(ctx context.Context, req interface{}) (interface{}, error) {
+		return nil, failed
+	})
+	require.Equal(t, failed, err)
+	require.NoError(t, l.StopContext(context.Background()))
+
+	require.Equal(t, 3, len(logs()))
+	for _, log := range logs() {
+		require.Equal(t, slog.Source{"server", "/tea.Pot/Brew"}, log.Source)
+	}
+	require.Equal(t, "brewing", logs()[0].Data[1])
+	require.Equal(t, "finished", logs()[1].Data[1])
+	require.Equal(t, slog.LevelInfo, logs()[1].Level)
+	require.Equal(t, "OK", logs()[1].Fields["code"])
+	require.Equal(t, "10.0.0.1:1234", logs()[1].Fields["peer"])
+	require.NotNil(t, logs()[1].Fields["duration"])
+	require.Equal(t, slog.LevelErr, logs()[2].Level)
+	require.Equal(t, "Internal", logs()[2].Fields["code"])
+	require.Equal(t, failed, logs()[2].Err)
+
+	// the RPC loggers don't pile up
+	require.Equal(t, int64(0), l.Stats().Children)
+
+}
+
+type serverStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *serverStream) Context() context.Context {
+	return s.ctx
+}
+
+func TestStreamServerInterceptor(t *testing.T) {
+
+	l, logs := newLogger()
+	intercept := sloggrpc.StreamServerInterceptor(l)
+	info := &grpc.StreamServerInfo{FullMethod: "/tea.Pot/Pour"}
+
+	err := intercept(nil, &serverStream{ctx: context.Background()}, info, func(srv interface{}, ss grpc.ServerStream) error {
+		slog.FromContext(ss.Context()).Info("pouring")
+		return status.Error(codes.NotFound, "no cups")
+	})
+	require.Error(t, err)
+	err = intercept(nil, &serverStream{ctx: context.Background()}, info, func(srv interface{}, ss grpc.ServerStream) error {
+		return errors.New("spilled")
+	})
+	require.Error(t, err)
+	require.NoError(t, l.StopContext(context.Background()))
+
+	require.Equal(t, 3, len(logs()))
+	require.Equal(t, slog.Source{"server", "/tea.Pot/Pour"}, logs()[0].Source)
+	require.Equal(t, "pouring", logs()[0].Data[1])
+
+	// clients asking for missing things aren't server errors
+	require.Equal(t, slog.LevelInfo, logs()[1].Level)
+	require.Equal(t, "NotFound", logs()[1].Fields["code"])
+	require.Nil(t, logs()[1].Fields["peer"])
+	require.Equal(t, slog.LevelErr, logs()[2].Level)
+	require.Equal(t, "Unknown", logs()[2].Fields["code"])
+
+}
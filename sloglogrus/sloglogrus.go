@@ -0,0 +1,96 @@
+// Package sloglogrus connects slog and logrus, so programs can move
+// from one to the other a package at a time.
+// NewReporter reports slog logs to a logrus.Logger, and NewHook
+// makes a logrus.Hook that logs to a slog.Logger.
+package sloglogrus
+
+import (
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/slog"
+)
+
+// SourceKey is the logrus field holding the source of slog logs.
+const SourceKey = "source"
+
+// levels maps slog levels to logrus levels.
+var levels = map[slog.Level]logrus.Level{
+	slog.LevelErr:   logrus.ErrorLevel,
+	slog.LevelWarn:  logrus.WarnLevel,
+	slog.LevelInfo:  logrus.InfoLevel,
+	slog.LevelDebug: logrus.DebugLevel,
+	slog.LevelTrace: logrus.TraceLevel,
+}
+
+type reporter struct {
+	l *logrus.Logger
+}
+
+// NewReporter gets a slog.Reporter that logs to l.
+// The Data of each log becomes the message, and its source, Fields
+// and Err logrus fields.
+func NewReporter(l *logrus.Logger) slog.Reporter {
+	return &reporter{l: l}
+}
+
+func (r *reporter) Log(l *slog.Log) {
+	level, ok := levels[l.Level]
+	if !ok {
+		level = logrus.TraceLevel
+	}
+	fields := make(logrus.Fields, len(l.Fields)+2)
+	for k, v := range l.Fields {
+		fields[k] = v
+	}
+	fields[SourceKey] = l.SourceString()
+	if l.Err != nil {
+		fields[logrus.ErrorKey] = l.Err
+	}
+	r.l.WithFields(fields).WithTime(l.When).Log(level, l.Message())
+}
+
+type hook struct {
+	l slog.Logger
+}
+
+// NewHook makes a logrus.Hook that logs every entry to l as well.
+// The data of entries becomes Fields, except for an error under
+// logrus.ErrorKey, which becomes the Err of the log.
+// To only log through slog, set the output of the logrus.Logger to
+// io.Discard.
+// Panic and fatal entries are logged at slog.LevelErr; logrus still
+// panics or exits for them.
+func NewHook(l slog.Logger) logrus.Hook {
+	return &hook{l: l}
+}
+
+func (h *hook) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+func (h *hook) Fire(e *logrus.Entry) error {
+	a := []interface{}{e.Message}
+	fields := make(slog.Fields, len(e.Data))
+	for k, v := range e.Data {
+		if err, ok := v.(error); ok && k == logrus.ErrorKey {
+			a = append(a, slog.Error(err))
+			continue
+		}
+		fields[k] = v
+	}
+	if len(fields) > 0 {
+		a = append(a, fields)
+	}
+	switch e.Level {
+	case logrus.PanicLevel, logrus.FatalLevel, logrus.ErrorLevel:
+		h.l.Err(a...)
+	case logrus.WarnLevel:
+		h.l.Warn(a...)
+	case logrus.InfoLevel:
+		h.l.Info(a...)
+	case logrus.DebugLevel:
+		h.l.Debug(a...)
+	default:
+		h.l.Trace(a...)
+	}
+	return nil
+}
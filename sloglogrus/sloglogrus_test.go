@@ -0,0 +1,56 @@
+package sloglogrus_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/slog"
+	"github.com/stretchr/slog/sloglogrus"
+	"github.com/stretchr/slog/slogtest"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReporter(t *testing.T) {
+
+	lr, hook := test.NewNullLogger()
+	l := slog.New("parent", slog.LevelInfo)
+	l.SetReporter(sloglogrus.NewReporter(lr))
+
+	err := errors.New("oops")
+	l.New("child").Warn("careful", slog.Fields{"n": 1}, slog.Error(err))
+	require.NoError(t, l.StopContext(context.Background()))
+
+	entries := hook.AllEntries()
+	require.Equal(t, 1, len(entries))
+	require.Equal(t, logrus.WarnLevel, entries[0].Level)
+	require.Equal(t, "careful", entries[0].Message)
+	require.Equal(t, logrus.Fields{"n": 1, "source": "parent>child", "error": err}, entries[0].Data)
+
+}
+
+func TestHook(t *testing.T) {
+
+	l, rec := slogtest.New(t, "parent")
+	l.SetLevel(slog.LevelInfo)
+	lr := logrus.New()
+	lr.SetOutput(io.Discard)
+	lr.SetLevel(logrus.TraceLevel)
+	lr.AddHook(sloglogrus.NewHook(l))
+
+	err := errors.New("oops")
+	lr.Debug("below the slog level")
+	lr.WithError(err).WithField("n", 2).Error("failed")
+	require.NoError(t, l.StopContext(context.Background()))
+
+	logs := rec.Logs()
+	require.Equal(t, 1, len(logs))
+	require.Equal(t, slog.LevelErr, logs[0].Level)
+	require.Equal(t, "failed", logs[0].Data[1])
+	require.Equal(t, err, logs[0].Err)
+	require.Equal(t, slog.Fields{"n": 2}, logs[0].Fields)
+
+}
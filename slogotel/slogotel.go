@@ -0,0 +1,42 @@
+// Package slogotel adds the OpenTelemetry trace and span of a
+// context to logs, so they can be found from traces, and traces
+// from them.
+package slogotel
+
+import (
+	"context"
+
+	"github.com/stretchr/slog"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	// TraceIDKey is the field holding the trace ID of a log.
+	TraceIDKey = "trace_id"
+	// SpanIDKey is the field holding the span ID of a log.
+	SpanIDKey = "span_id"
+)
+
+// WithSpan gets a child of l that adds the IDs of the span in ctx
+// to every log it makes, or l if ctx has no span.
+func WithSpan(ctx context.Context, l slog.Logger) slog.Logger {
+	sc := trace.SpanContextFromContext(ctx)
+	if !sc.IsValid() {
+		return l
+	}
+	return l.WithFields(slog.Fields{
+		TraceIDKey: sc.TraceID().String(),
+		SpanIDKey:  sc.SpanID().String(),
+	})
+}
+
+// FromContext gets the Logger carried by ctx, like
+// slog.FromContext, adding the IDs of the span in ctx as WithSpan
+// does:
+//
+//	ctx, span := tracer.Start(ctx, "save")
+//	defer span.End()
+//	slogotel.FromContext(ctx).Info("saving")
+func FromContext(ctx context.Context) slog.Logger {
+	return WithSpan(ctx, slog.FromContext(ctx))
+}
@@ -0,0 +1,37 @@
+package slogotel_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/slog"
+	"github.com/stretchr/slog/slogotel"
+	"github.com/stretchr/slog/slogtest"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestFromContext(t *testing.T) {
+
+	l, rec := slogtest.New(t, "parent")
+	ctx := slog.NewContext(context.Background(), l)
+
+	slogotel.FromContext(ctx).Info("no span")
+
+	sc := trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID: trace.TraceID{0x4b, 0xf9, 0x2f, 0x35, 0x77, 0xb3, 0x4d, 0xa6, 0xa3, 0xce, 0x92, 0x9d, 0x0e, 0x0e, 0x47, 0x36},
+		SpanID:  trace.SpanID{0x00, 0xf0, 0x67, 0xaa, 0x0b, 0xa9, 0x02, 0xb7},
+	})
+	ctx = trace.ContextWithSpanContext(ctx, sc)
+	slogotel.FromContext(ctx).Info("in a span")
+
+	require.True(t, rec.Wait(2, time.Second))
+	logs := rec.Logs()
+	require.Equal(t, 0, len(logs[0].Fields))
+	require.Equal(t, slog.Fields{
+		slogotel.TraceIDKey: "4bf92f3577b34da6a3ce929d0e0e4736",
+		slogotel.SpanIDKey:  "00f067aa0ba902b7",
+	}, logs[1].Fields)
+
+}
@@ -0,0 +1,139 @@
+// Package slogtest provides a slog.Reporter that records logs, for
+// testing code that logs.
+package slogtest
+
+import (
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/slog"
+)
+
+// Recorder is a slog.Reporter that keeps every log it is given,
+// and can be used from many goroutines at once.
+type Recorder struct {
+	m       sync.Mutex
+	logs    []*slog.Log
+	changed chan struct{} // closed when a log is recorded
+}
+
+var _ slog.Reporter = (*Recorder)(nil)
+
+// NewRecorder makes an empty Recorder.
+func NewRecorder() *Recorder {
+	return &Recorder{changed: make(chan struct{})}
+}
+
+// New makes a RootLogger that logs everything to a new Recorder,
+// and is stopped when the test finishes.
+// Logs are reported synchronously, so they have been recorded by
+// the time the log methods return.
+func New(t testing.TB, source string) (slog.RootLogger, *Recorder) {
+	r := NewRecorder()
+	l := slog.NewWithOptions(source, slog.WithLevel(slog.LevelEverything), slog.WithReporter(r), slog.WithSync(true))
+	t.Cleanup(func() { l.Stop(0) })
+	return l, r
+}
+
+// Log records the log.
+func (r *Recorder) Log(l *slog.Log) {
+	r.m.Lock()
+	r.logs = append(r.logs, l)
+	close(r.changed)
+	r.changed = make(chan struct{})
+	r.m.Unlock()
+}
+
+// Logs gets the logs recorded so far, oldest first.
+func (r *Recorder) Logs() []*slog.Log {
+	r.m.Lock()
+	defer r.m.Unlock()
+	return append([]*slog.Log(nil), r.logs...)
+}
+
+// Len gets the number of logs recorded so far.
+func (r *Recorder) Len() int {
+	r.m.Lock()
+	defer r.m.Unlock()
+	return len(r.logs)
+}
+
+// Reset forgets the logs recorded so far.
+func (r *Recorder) Reset() {
+	r.m.Lock()
+	r.logs = nil
+	r.m.Unlock()
+}
+
+// Wait waits until at least n logs have been recorded, and gets
+// whether they were before the timeout.
+// Logs are reported in the background, so tests should Wait for
+// them before looking at them.
+func (r *Recorder) Wait(n int, timeout time.Duration) bool {
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	for {
+		r.m.Lock()
+		if len(r.logs) >= n {
+			r.m.Unlock()
+			return true
+		}
+		changed := r.changed
+		r.m.Unlock()
+		select {
+		case <-changed:
+		case <-timer.C:
+			return false
+		}
+	}
+}
+
+// Entries gets the logs recorded at the level that contain each
+// of the strings when formatted by slog.TextFormatter.
+func (r *Recorder) Entries(level slog.Level, contains ...string) []*slog.Log {
+	var entries []*slog.Log
+	for _, l := range r.Logs() {
+		if l.Level == level && matches(l, contains) {
+			entries = append(entries, l)
+		}
+	}
+	return entries
+}
+
+// HasEntry gets whether a log has been recorded at the level that
+// contains each of the strings, as Entries does.
+func (r *Recorder) HasEntry(level slog.Level, contains ...string) bool {
+	return len(r.Entries(level, contains...)) > 0
+}
+
+// RequireEntry fails the test now if HasEntry is false.
+func (r *Recorder) RequireEntry(t testing.TB, level slog.Level, contains ...string) {
+	t.Helper()
+	if !r.HasEntry(level, contains...) {
+		t.Fatalf("slogtest: no %s log containing %q in:\n%s", level, contains, r)
+	}
+}
+
+// String gets the logs recorded so far formatted by
+// slog.TextFormatter.
+func (r *Recorder) String() string {
+	var buf strings.Builder
+	for _, l := range r.Logs() {
+		buf.Write(slog.TextFormatter.Format(l))
+	}
+	return buf.String()
+}
+
+// matches gets whether the formatted log contains each of the
+// strings.
+func matches(l *slog.Log, contains []string) bool {
+	text := string(slog.TextFormatter.Format(l))
+	for _, s := range contains {
+		if !strings.Contains(text, s) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,43 @@
+package slogtest_test
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/slog"
+	"github.com/stretchr/slog/slogtest"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecorder(t *testing.T) {
+
+	l, r := slogtest.New(t, "parent")
+	child := l.New("child")
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 25; i++ {
+				child.Debug("working", slog.Fields{"n": i})
+			}
+		}()
+	}
+	wg.Wait()
+	child.Err("failed", slog.Error(errors.New("disk full")))
+
+	require.True(t, r.Wait(101, time.Second))
+	require.Equal(t, 101, r.Len())
+	require.True(t, r.HasEntry(slog.LevelErr, "parent>child", "failed", "disk full"))
+	require.False(t, r.HasEntry(slog.LevelWarn, "failed"))
+	require.Equal(t, 4, len(r.Entries(slog.LevelDebug, "n=24")))
+	r.RequireEntry(t, slog.LevelDebug, "working")
+
+	r.Reset()
+	require.Equal(t, 0, r.Len())
+	require.False(t, r.Wait(1, 10*time.Millisecond))
+
+}
@@ -0,0 +1,141 @@
+// Package slogzap connects slog and zap, so programs can move from
+// one to the other a package at a time.
+// NewReporter reports slog logs to a zap.Logger, and NewCore makes
+// a zapcore.Core that logs to a slog.Logger.
+package slogzap
+
+import (
+	"sort"
+
+	"github.com/stretchr/slog"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// levels maps slog levels to zap levels.
+var levels = map[slog.Level]zapcore.Level{
+	slog.LevelErr:   zapcore.ErrorLevel,
+	slog.LevelWarn:  zapcore.WarnLevel,
+	slog.LevelInfo:  zapcore.InfoLevel,
+	slog.LevelDebug: zapcore.DebugLevel,
+	slog.LevelTrace: zapcore.DebugLevel,
+}
+
+type reporter struct {
+	z *zap.Logger
+}
+
+// NewReporter gets a slog.Reporter that logs to z.
+// The source of each log becomes the logger name, its Data the
+// message, and its Fields and Err zap fields.
+// The time, caller and stack of the log are kept.
+func NewReporter(z *zap.Logger) slog.Reporter {
+	return &reporter{z: z}
+}
+
+func (r *reporter) Log(l *slog.Log) {
+	level, ok := levels[l.Level]
+	if !ok {
+		level = zapcore.DebugLevel
+	}
+	ent := zapcore.Entry{
+		Level:      level,
+		Time:       l.When,
+		LoggerName: l.SourceString(),
+		Message:    l.Message(),
+		Stack:      l.Stack,
+	}
+	if l.Caller != nil {
+		ent.Caller = zapcore.NewEntryCaller(0, l.Caller.File, l.Caller.Line, true)
+		ent.Caller.Function = l.Caller.Function
+	}
+	ce := r.z.Core().Check(ent, nil)
+	if ce == nil {
+		return
+	}
+	keys := make([]string, 0, len(l.Fields))
+	for k := range l.Fields {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	fields := make([]zap.Field, 0, len(keys)+1)
+	for _, k := range keys {
+		fields = append(fields, zap.Any(k, l.Fields[k]))
+	}
+	if l.Err != nil {
+		fields = append(fields, zap.Error(l.Err))
+	}
+	ce.Write(fields...)
+}
+
+type core struct {
+	l slog.Logger
+}
+
+var _ zapcore.Core = (*core)(nil)
+
+// NewCore makes a zapcore.Core that logs to l, so zap.New(NewCore(l))
+// is a zap.Logger that logs through slog.
+// zap fields become Fields, and the logger name, if any, is added to
+// the source.
+// Levels above zap's ErrorLevel are logged at slog.LevelErr; zap
+// still panics or exits for them.
+func NewCore(l slog.Logger) zapcore.Core {
+	return &core{l: l}
+}
+
+func (c *core) Enabled(level zapcore.Level) bool {
+	return logAt(c.l, level)
+}
+
+func (c *core) With(fields []zapcore.Field) zapcore.Core {
+	return &core{l: c.l.WithFields(encode(fields))}
+}
+
+func (c *core) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
+
+func (c *core) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	l := c.l
+	if ent.LoggerName != "" {
+		l = l.New(ent.LoggerName)
+	}
+	a := []interface{}{ent.Message}
+	if len(fields) > 0 {
+		a = append(a, encode(fields))
+	}
+	logAt(l, ent.Level, a...)
+	return nil
+}
+
+// Sync does nothing, as slog reports logs in the background.
+func (c *core) Sync() error {
+	return nil
+}
+
+// encode gets the zap fields as slog Fields.
+func encode(fields []zapcore.Field) slog.Fields {
+	enc := zapcore.NewMapObjectEncoder()
+	for _, f := range fields {
+		f.AddTo(enc)
+	}
+	return slog.Fields(enc.Fields)
+}
+
+// logAt logs a to l at the slog level for the zap level, and gets
+// whether l is logging at it.
+func logAt(l slog.Logger, level zapcore.Level, a ...interface{}) bool {
+	switch {
+	case level >= zapcore.ErrorLevel:
+		return l.Err(a...)
+	case level == zapcore.WarnLevel:
+		return l.Warn(a...)
+	case level == zapcore.InfoLevel:
+		return l.Info(a...)
+	}
+	return l.Debug(a...)
+}
@@ -0,0 +1,54 @@
+package slogzap_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/slog"
+	"github.com/stretchr/slog/slogtest"
+	"github.com/stretchr/slog/slogzap"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestReporter(t *testing.T) {
+
+	core, logs := observer.New(zapcore.InfoLevel)
+	l := slog.New("parent", slog.LevelDebug)
+	l.SetReporter(slogzap.NewReporter(zap.New(core)))
+
+	child := l.New("child")
+	child.Debug("below the zap level")
+	child.Warn("careful", slog.Fields{"n": 1}, slog.Error(errors.New("oops")))
+	require.NoError(t, l.StopContext(context.Background()))
+
+	entries := logs.All()
+	require.Equal(t, 1, len(entries))
+	require.Equal(t, zapcore.WarnLevel, entries[0].Level)
+	require.Equal(t, "parent>child", entries[0].LoggerName)
+	require.Equal(t, "careful", entries[0].Message)
+	require.Equal(t, map[string]interface{}{"n": int64(1), "error": "oops"}, entries[0].ContextMap())
+
+}
+
+func TestCore(t *testing.T) {
+
+	l, rec := slogtest.New(t, "parent")
+	l.SetLevel(slog.LevelInfo)
+	z := zap.New(slogzap.NewCore(l)).Named("zap").With(zap.String("app", "test"))
+
+	z.Debug("below the slog level")
+	z.Error("failed", zap.Int("n", 2))
+	require.NoError(t, l.StopContext(context.Background()))
+
+	logs := rec.Logs()
+	require.Equal(t, 1, len(logs))
+	require.Equal(t, slog.LevelErr, logs[0].Level)
+	require.Equal(t, slog.Source{"parent", "zap"}, logs[0].Source)
+	require.Equal(t, "failed", logs[0].Data[1])
+	require.Equal(t, slog.Fields{"app": "test", "n": int64(2)}, logs[0].Fields)
+
+}
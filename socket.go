@@ -0,0 +1,73 @@
+package slog
+
+import (
+	"io"
+	"net"
+	"sync"
+)
+
+// SocketReporter is a Reporter that writes logs to a network
+// connection, e.g. newline delimited JSON over TCP.
+type SocketReporter struct {
+	m       sync.Mutex
+	network string
+	addr    string
+	f       Formatter
+	conn    net.Conn
+}
+
+var _ ErrReporter = (*SocketReporter)(nil)
+var _ io.Closer = (*SocketReporter)(nil)
+
+// NewSocketReporter makes a SocketReporter that connects to addr
+// on the network ("tcp", "udp", "unix"...) and writes each log,
+// formatted by f, to it.
+func NewSocketReporter(network, addr string, f Formatter) (*SocketReporter, error) {
+	conn, err := net.Dial(network, addr)
+	if err != nil {
+		return nil, err
+	}
+	return &SocketReporter{network: network, addr: addr, f: f, conn: conn}, nil
+}
+
+// Log writes the log, reconnecting once if writing fails.
+func (s *SocketReporter) Log(l *Log) {
+	s.Report(l)
+}
+
+// Report writes the log as Log does, returning an error if it
+// couldn't be written.
+func (s *SocketReporter) Report(l *Log) error {
+	b, err := format(s.f, l)
+	if b == nil {
+		return err
+	}
+	s.m.Lock()
+	defer s.m.Unlock()
+	if s.conn != nil {
+		if _, err := s.conn.Write(b); err == nil {
+			return nil
+		}
+		s.conn.Close()
+		s.conn = nil
+	}
+	conn, err := net.Dial(s.network, s.addr)
+	if err != nil {
+		return err
+	}
+	s.conn = conn
+	_, err = conn.Write(b)
+	return err
+}
+
+// Close closes the connection.
+func (s *SocketReporter) Close() error {
+	s.m.Lock()
+	defer s.m.Unlock()
+	if s.conn == nil {
+		return nil
+	}
+	err := s.conn.Close()
+	s.conn = nil
+	return err
+}
@@ -0,0 +1,48 @@
+package slog_test
+
+import (
+	"bufio"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/slog"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSocketReporter(t *testing.T) {
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer ln.Close()
+	lines := make(chan string)
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				sc := bufio.NewScanner(conn)
+				for sc.Scan() {
+					lines <- sc.Text()
+				}
+				conn.Close()
+			}()
+		}
+	}()
+
+	r, err := slog.NewSocketReporter("tcp", ln.Addr().String(), slog.JSONFormatter)
+	require.NoError(t, err)
+	defer r.Close()
+
+	when := time.Date(2015, 1, 2, 3, 4, 5, 0, time.UTC)
+	require.NoError(t, r.Report(&slog.Log{Level: slog.LevelInfo, When: when, Source: []string{"parent"}, Data: []interface{}{"one"}}))
+	require.JSONEq(t, `{"level":"info","time":"2015-01-02T03:04:05Z","source":"parent","data":["one"]}`, <-lines)
+
+	// reconnects after the connection is closed
+	require.NoError(t, r.Close())
+	require.NoError(t, r.Report(&slog.Log{Level: slog.LevelInfo, When: when, Source: []string{"parent"}, Data: []interface{}{"two"}}))
+	require.JSONEq(t, `{"level":"info","time":"2015-01-02T03:04:05Z","source":"parent","data":["two"]}`, <-lines)
+
+}
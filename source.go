@@ -0,0 +1,90 @@
+package slog
+
+import (
+	"strings"
+	"sync/atomic"
+)
+
+// DefaultSourceSep separates the names in sources when they are
+// written, unless the RootLogger was made WithSourceSep.
+const DefaultSourceSep = ">"
+
+// Source is the path of names of the logger a log was made by,
+// from the name of its RootLogger to the name of the logger.
+type Source []string
+
+// Path gets the names in the source.
+func (s Source) Path() []string {
+	return []string(s)
+}
+
+// Leaf gets the last name in the source, which is the name of
+// the logger the log was made by.
+func (s Source) Leaf() string {
+	if len(s) == 0 {
+		return ""
+	}
+	return s[len(s)-1]
+}
+
+// String gets the names joined with DefaultSourceSep.
+func (s Source) String() string {
+	return s.Join(DefaultSourceSep)
+}
+
+// Join gets the names joined with sep.
+func (s Source) Join(sep string) string {
+	return strings.Join(s, sep)
+}
+
+// sourceSplit is a source, or pattern of sources, given to a
+// Reporter. It is split into names by the SourceSep of the logs it
+// is matched against, so it is written like their sources are, as
+// well as by DefaultSourceSep.
+type sourceSplit struct {
+	source string
+	parts  []string     // split by DefaultSourceSep
+	other  atomic.Value // holds a sepParts, split by another SourceSep
+}
+
+type sepParts struct {
+	sep   string
+	parts []string
+}
+
+func newSourceSplit(source string) *sourceSplit {
+	return &sourceSplit{source: source, parts: strings.Split(source, DefaultSourceSep)}
+}
+
+// split gets the names of the source separated by sep or
+// DefaultSourceSep.
+func (s *sourceSplit) split(sep string) []string {
+	if sep == "" || sep == DefaultSourceSep {
+		return s.parts
+	}
+	if p, ok := s.other.Load().(sepParts); ok && p.sep == sep {
+		return p.parts
+	}
+	parts := strings.Split(strings.ReplaceAll(s.source, sep, DefaultSourceSep), DefaultSourceSep)
+	s.other.Store(sepParts{sep: sep, parts: parts})
+	return parts
+}
+
+// sourceName gets the name the logger keeps for a source written
+// with its SourceSep, or DefaultSourceSep, as given to
+// SetSourceLevel and Boost.
+func (l *logger) sourceName(source string) string {
+	if sep := l.root.sep; sep != "" && sep != nestedLogSep {
+		return strings.ReplaceAll(source, sep, nestedLogSep)
+	}
+	return source
+}
+
+// sepName gets a name the logger keeps for a source written with
+// its SourceSep.
+func (l *logger) sepName(name string) string {
+	if sep := l.root.sep; sep != "" && sep != nestedLogSep {
+		return strings.ReplaceAll(name, nestedLogSep, sep)
+	}
+	return name
+}
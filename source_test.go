@@ -0,0 +1,64 @@
+package slog_test
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/stretchr/slog"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSource(t *testing.T) {
+
+	s := slog.Source{"parent", "child", "grandchild"}
+	require.Equal(t, []string{"parent", "child", "grandchild"}, s.Path())
+	require.Equal(t, "grandchild", s.Leaf())
+	require.Equal(t, "parent>child>grandchild", s.String())
+	require.Equal(t, "parent/child/grandchild", s.Join("/"))
+	require.Equal(t, "", slog.Source{}.Leaf())
+
+}
+
+func TestWithSourceSep(t *testing.T) {
+
+	var buf bytes.Buffer
+	l := slog.NewWithOptions("parent",
+		slog.WithReporter(slog.NewLogfmtReporter(&buf)),
+		slog.WithSourceSep("."),
+	)
+	l.SetSourceLevel("parent>child", slog.LevelDebug)
+	l.New("child").Debug("one")
+	require.NoError(t, l.StopContext(context.Background()))
+
+	require.Contains(t, buf.String(), "source=parent.child ")
+
+}
+
+func TestWithSourceSepPatterns(t *testing.T) {
+
+	billing, rest := NewTestReporter(), NewTestReporter()
+	filter, err := slog.NewSourceFilter(slog.Rules(rest, slog.Rule{Source: "parent/billing", Reporter: billing}), map[string]slog.Level{"parent/db/*": slog.LevelWarn})
+	require.NoError(t, err)
+	l := slog.NewWithOptions("parent",
+		slog.WithReporter(filter),
+		slog.WithSourceSep("/"),
+		slog.WithSync(true),
+	)
+	l.SetSourceLevel("parent/billing", slog.LevelDebug)
+	l.SetSourceLevel("parent>db", slog.LevelDebug)
+	require.Equal(t, map[string]slog.Level{"parent/billing": slog.LevelDebug, "parent/db": slog.LevelDebug}, l.SourceLevels())
+
+	l.New("billing").Debug("charged")
+	db := l.New("db")
+	db.New("pool").Info("filtered")
+	db.New("pool").Warn("slow")
+	require.Equal(t, "parent/db/pool", l.Tree().Children[1].Children[0].Source)
+	require.NoError(t, l.StopContext(context.Background()))
+
+	require.Equal(t, 1, len(billing.logs))
+	require.Equal(t, "charged", billing.logs[0].Data[1])
+	require.Equal(t, 1, len(rest.logs))
+	require.Equal(t, "slow", rest.logs[0].Data[1])
+
+}
@@ -0,0 +1,197 @@
+package slog
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+// sourcePattern is a pattern given to a SourceFilter, split into
+// its parts.
+type sourcePattern struct {
+	pattern *sourceSplit
+	level   Level
+}
+
+// SourceFilter is a Reporter that reports logs to another
+// Reporter only if they are at or more severe than the level of
+// the pattern that matches their source, so the verbosity of each
+// part of a program can be tuned.
+//
+// Patterns are sources, like "parent>http", where each part may
+// contain * to match any characters, and ? to match one. They are
+// written with the SourceSep of the logs, e.g. "parent/http" if
+// the RootLogger was made WithSourceSep("/").
+// A pattern matches a source and all of its children.
+// If several patterns match, the one matching the most parts of
+// the source is used, and logs that no pattern matches are all
+// reported.
+//
+// Logs the logger isn't logging at never reach the SourceFilter,
+// so the logger should log at the most verbose level needed.
+type SourceFilter struct {
+	m        sync.RWMutex
+	r        Reporter
+	patterns []sourcePattern
+}
+
+var _ Reporter = (*SourceFilter)(nil)
+var _ io.Closer = (*SourceFilter)(nil)
+
+// NewSourceFilter makes a SourceFilter that reports to r, with
+// the level of each of the patterns.
+func NewSourceFilter(r Reporter, patterns map[string]Level) (*SourceFilter, error) {
+	f := &SourceFilter{r: r}
+	if err := f.SetPatterns(patterns); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+// SetPatterns replaces the patterns of the SourceFilter, and can
+// be called while logs are being reported.
+func (f *SourceFilter) SetPatterns(patterns map[string]Level) error {
+	ps := make([]sourcePattern, 0, len(patterns))
+	for pattern, level := range patterns {
+		if pattern == "" {
+			return fmt.Errorf("slog: empty source pattern")
+		}
+		if _, ok := levelStrs[level]; !ok || level == LevelInvalid {
+			return fmt.Errorf("slog: invalid level for source pattern %q", pattern)
+		}
+		ps = append(ps, sourcePattern{
+			pattern: newSourceSplit(pattern),
+			level:   level,
+		})
+	}
+	f.m.Lock()
+	f.patterns = ps
+	f.m.Unlock()
+	return nil
+}
+
+// Patterns gets the patterns of the SourceFilter.
+func (f *SourceFilter) Patterns() map[string]Level {
+	f.m.RLock()
+	defer f.m.RUnlock()
+	patterns := make(map[string]Level, len(f.patterns))
+	for _, p := range f.patterns {
+		patterns[p.pattern.source] = p.level
+	}
+	return patterns
+}
+
+// Log reports the log if its level is allowed for its source.
+func (f *SourceFilter) Log(l *Log) {
+	if l.Level <= f.level(l) {
+		f.r.Log(l)
+	}
+}
+
+// level gets the level of the pattern that best matches the
+// source of the log, or LevelEverything if none does.
+func (f *SourceFilter) level(l *Log) Level {
+	f.m.RLock()
+	defer f.m.RUnlock()
+	level := LevelEverything
+	best := 0
+	for _, p := range f.patterns {
+		parts := p.pattern.split(l.SourceSep)
+		if len(parts) < best || len(parts) > len(l.Source) {
+			continue
+		}
+		if len(parts) == best && level != LevelEverything && p.level <= level {
+			continue // prefer the most verbose of equal matches, so order doesn't matter
+		}
+		if matchParts(parts, l.Source) {
+			level, best = p.level, len(parts)
+		}
+	}
+	return level
+}
+
+// Flush flushes the Reporter, if it is a Flusher.
+func (f *SourceFilter) Flush() {
+	reporters{f.r}.Flush()
+}
+
+// Start starts the Reporter, if it is a Starter.
+func (f *SourceFilter) Start() {
+	reporters{f.r}.Start()
+}
+
+// Close closes the Reporter, if it is an io.Closer.
+func (f *SourceFilter) Close() error {
+	if c, ok := f.r.(io.Closer); ok {
+		return c.Close()
+	}
+	return nil
+}
+
+// ParseSourcePatterns parses patterns for a SourceFilter from a
+// comma separated list of pattern=level pairs, like
+// "parent>http>*=error,parent>db=info", as may be set in config or
+// the environment.
+func ParseSourcePatterns(s string) (map[string]Level, error) {
+	patterns := make(map[string]Level)
+	for _, pair := range strings.Split(s, ",") {
+		if pair = strings.TrimSpace(pair); pair == "" {
+			continue
+		}
+		i := strings.LastIndex(pair, "=")
+		if i < 0 {
+			return nil, fmt.Errorf("slog: source pattern %q has no level", pair)
+		}
+		level := ParseLevel(pair[i+1:])
+		if level == LevelInvalid {
+			return nil, unknownLevel(pair[i+1:])
+		}
+		patterns[strings.TrimSpace(pair[:i])] = level
+	}
+	return patterns, nil
+}
+
+// matchParts gets whether the first parts of the source match
+// each of the parts of a pattern.
+func matchParts(parts, source []string) bool {
+	for i, part := range parts {
+		if !matchGlob(part, source[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// matchGlob gets whether s matches the pattern, where * matches
+// any characters and ? matches one, which may be a multi-byte
+// rune.
+func matchGlob(pattern, s string) bool {
+	star, next := -1, 0
+	p, i := 0, 0
+	for i < len(s) {
+		switch {
+		case p < len(pattern) && pattern[p] == '?':
+			_, size := utf8.DecodeRuneInString(s[i:])
+			p++
+			i += size
+		case p < len(pattern) && pattern[p] == s[i]:
+			p++
+			i++
+		case p < len(pattern) && pattern[p] == '*':
+			star, next = p, i
+			p++
+		case star >= 0:
+			_, size := utf8.DecodeRuneInString(s[next:])
+			next += size
+			p, i = star+1, next
+		default:
+			return false
+		}
+	}
+	for p < len(pattern) && pattern[p] == '*' {
+		p++
+	}
+	return p == len(pattern)
+}
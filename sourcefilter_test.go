@@ -0,0 +1,76 @@
+package slog_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/slog"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSourceFilter(t *testing.T) {
+
+	r := NewTestReporter()
+	f, err := slog.NewSourceFilter(r, map[string]slog.Level{
+		"parent>http>*": slog.LevelErr,
+		"parent>db":     slog.LevelInfo,
+		"parent>d?":     slog.LevelWarn,
+		"*>cache":       slog.LevelWarn,
+	})
+	require.NoError(t, err)
+
+	logs := []struct {
+		source string
+		level  slog.Level
+	}{
+		{"parent>http>GET /users", slog.LevelWarn}, // filtered
+		{"parent>http>GET /users", slog.LevelErr},
+		{"parent>http", slog.LevelDebug},
+		{"parent>db>pool", slog.LevelInfo},
+		{"parent>db>pool", slog.LevelDebug}, // filtered
+		{"parent>dx", slog.LevelInfo},       // filtered
+		{"parent>dé", slog.LevelInfo},       // filtered
+		{"parent>dé", slog.LevelWarn},
+		{"other>cache", slog.LevelInfo}, // filtered
+		{"other>cache>shard", slog.LevelWarn},
+	}
+	for _, l := range logs {
+		f.Log(&slog.Log{Source: strings.Split(l.source, ">"), Level: l.level})
+	}
+	var got []string
+	for _, l := range r.logs {
+		got = append(got, strings.Join(l.Source, ">")+" "+l.Level.String())
+	}
+	require.Equal(t, []string{
+		"parent>http>GET /users error",
+		"parent>http debug",
+		"parent>db>pool info",
+		"parent>dé warning",
+		"other>cache>shard warning",
+	}, got)
+
+	require.NoError(t, f.SetPatterns(map[string]slog.Level{"parent": slog.LevelErr}))
+	f.Log(&slog.Log{Source: []string{"parent", "db"}, Level: slog.LevelInfo})
+	require.Equal(t, 5, len(r.logs))
+	require.Equal(t, map[string]slog.Level{"parent": slog.LevelErr}, f.Patterns())
+
+	require.Error(t, f.SetPatterns(map[string]slog.Level{"": slog.LevelErr}))
+	require.Error(t, f.SetPatterns(map[string]slog.Level{"parent": slog.LevelInvalid}))
+
+}
+
+func TestParseSourcePatterns(t *testing.T) {
+
+	patterns, err := slog.ParseSourcePatterns("parent>http>*=error, parent>db=info,")
+	require.NoError(t, err)
+	require.Equal(t, map[string]slog.Level{
+		"parent>http>*": slog.LevelErr,
+		"parent>db":     slog.LevelInfo,
+	}, patterns)
+
+	_, err = slog.ParseSourcePatterns("parent>db")
+	require.Error(t, err)
+	_, err = slog.ParseSourcePatterns("parent>db=loud")
+	require.Error(t, err)
+
+}
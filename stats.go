@@ -0,0 +1,106 @@
+package slog
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"sync/atomic"
+	"time"
+)
+
+// Stats represents the health of the logging pipeline of a
+// RootLogger.
+type Stats struct {
+	// Queued is the number of logs waiting for the Reporter.
+	Queued int
+	// Reported is the number of logs given to the Reporter.
+	Reported uint64
+	// Failed is the number of logs the Reporter failed to report,
+	// which can only be counted for an ErrReporter.
+	Failed uint64
+	// Dropped is the number of logs dropped because the buffer was
+	// full.
+	Dropped uint64
+	// Abandoned is the number of logs abandoned when stopping.
+	Abandoned uint64
+	// Late is the number of logs made after stopping, which are
+	// dropped.
+	Late uint64
+	// ReportTime is the total time spent in the Reporter.
+	ReportTime time.Duration
+	// Pressure is how full the buffer is, see RootLogger.Pressure.
+	Pressure float64
+	// Counts is the number of logs made at each level, see
+	// RootLogger.Counts.
+	Counts map[Level]uint64
+	// Children is the number of child loggers made by New or
+	// WithReporter that haven't been closed.
+	Children int64
+}
+
+func (l *logger) Stats() Stats {
+	root := l.root
+	return Stats{
+		Queued:     root.q.length(),
+		Reported:   atomic.LoadUint64(&root.reported),
+		Failed:     atomic.LoadUint64(&root.failed),
+		Dropped:    root.q.droppedCount(),
+		Abandoned:  uint64(atomic.LoadInt64(&root.abandoned)),
+		Late:       atomic.LoadUint64(&root.late),
+		ReportTime: time.Duration(atomic.LoadInt64(&root.reporting)),
+		Pressure:   root.q.pressure(),
+		Children:   atomic.LoadInt64(&root.children),
+		Counts:     l.Counts(),
+	}
+}
+
+func (l *logger) Counts() map[Level]uint64 {
+	counts := make(map[Level]uint64, LevelEverything-LevelErr)
+	for level := LevelErr; level < LevelEverything; level++ {
+		counts[level] = atomic.LoadUint64(&l.root.counts[level])
+	}
+	return counts
+}
+
+// WriteTo writes the stats to w in the Prometheus text
+// exposition format, with names that don't clash with those of a
+// MetricsReporter.
+func (s Stats) WriteTo(w io.Writer) (int64, error) {
+	var buf bytes.Buffer
+	metric := func(name, typ, help string, value interface{}) {
+		fmt.Fprintf(&buf, "# HELP %s %s\n# TYPE %s %s\n%s %v\n", name, help, name, typ, name, value)
+	}
+	metric("log_queue_length", "gauge", "Number of logs waiting for the reporter.", s.Queued)
+	metric("log_reported_total", "counter", "Number of logs given to the reporter.", s.Reported)
+	metric("log_report_errors_total", "counter", "Number of logs the reporter failed to report.", s.Failed)
+	metric("log_dropped_total", "counter", "Number of logs dropped because the buffer was full.", s.Dropped)
+	metric("log_abandoned_total", "counter", "Number of logs abandoned when stopping.", s.Abandoned)
+	metric("log_late_total", "counter", "Number of logs made after stopping.", s.Late)
+	metric("log_report_seconds_total", "counter", "Total time spent in the reporter.", s.ReportTime.Seconds())
+	metric("log_queue_pressure", "gauge", "How full the buffer is, from 0 to 1.", s.Pressure)
+	metric("log_children", "gauge", "Number of child loggers that haven't been closed.", s.Children)
+	if len(s.Counts) > 0 {
+		buf.WriteString("# HELP log_made_total Number of logs made at each level.\n# TYPE log_made_total counter\n")
+		for level := LevelErr; level < LevelEverything; level++ {
+			fmt.Fprintf(&buf, "log_made_total{level=%q} %d\n", level, s.Counts[level])
+		}
+	}
+	return buf.WriteTo(w)
+}
+
+type statsHandler struct {
+	l RootLogger
+}
+
+// StatsHandler gets an http.Handler that writes the Stats of l in
+// the Prometheus text exposition format, so it can be mounted as
+// a scrape endpoint alongside a MetricsReporter.
+func StatsHandler(l RootLogger) http.Handler {
+	return &statsHandler{l: l}
+}
+
+func (h *statsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+	h.l.Stats().WriteTo(w)
+}
@@ -0,0 +1,81 @@
+package slog_test
+
+import (
+	"context"
+	"errors"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/slog"
+	"github.com/stretchr/testify/require"
+)
+
+// errReporter fails to report logs at LevelErr.
+type errReporter struct{}
+
+func (errReporter) Log(*slog.Log) {}
+
+func (errReporter) Report(l *slog.Log) error {
+	if l.Level == slog.LevelErr {
+		return errors.New("failed")
+	}
+	return nil
+}
+
+func TestStats(t *testing.T) {
+
+	l := slog.New("parent", slog.LevelInfo)
+	l.SetReporter(errReporter{})
+
+	l.Info("one")
+	l.Err("two")
+	l.Info("three")
+	require.NoError(t, l.StopContext(context.Background()))
+
+	stats := l.Stats()
+	require.Equal(t, 0, stats.Queued)
+	require.Equal(t, uint64(3), stats.Reported)
+	require.Equal(t, uint64(1), stats.Failed)
+	require.Equal(t, uint64(0), stats.Dropped)
+	require.True(t, stats.ReportTime > 0)
+
+	w := httptest.NewRecorder()
+	slog.StatsHandler(l).ServeHTTP(w, httptest.NewRequest("GET", "/metrics", nil))
+	require.True(t, strings.Contains(w.Body.String(), "# TYPE log_queue_length gauge\nlog_queue_length 0\n"))
+	require.True(t, strings.Contains(w.Body.String(), "log_reported_total 3\n"))
+	require.True(t, strings.Contains(w.Body.String(), "log_report_errors_total 1\n"))
+
+	require.Equal(t, slog.Stats{}, slog.NilLogger.Stats())
+
+}
+
+func TestCounts(t *testing.T) {
+
+	l := slog.New("parent", slog.LevelDebug)
+	l.SetReporter(slog.DiscardReporter)
+	l.Err("one")
+	l.Warn("two")
+	l.New("child").Warnf("%s", "three")
+	l.Trace("skipped")
+	l.Info()
+
+	counts := l.Counts()
+	require.Equal(t, map[slog.Level]uint64{
+		slog.LevelErr:   1,
+		slog.LevelWarn:  2,
+		slog.LevelInfo:  0,
+		slog.LevelDebug: 0,
+		slog.LevelTrace: 0,
+	}, counts)
+	require.Equal(t, counts, l.Stats().Counts)
+
+	var buf strings.Builder
+	l.Stats().WriteTo(&buf)
+	require.Contains(t, buf.String(), "# TYPE log_made_total counter\nlog_made_total{level=\"error\"} 1\nlog_made_total{level=\"warning\"} 2\n")
+
+	// the metric names don't clash with those of a MetricsReporter
+	require.NotContains(t, buf.String(), "log_messages_total")
+	require.Nil(t, slog.NilLogger.Counts())
+
+}
@@ -0,0 +1,160 @@
+package slog
+
+import (
+	"context"
+	stdslog "log/slog"
+)
+
+// stdLevel gets the log/slog level for the Level.
+func stdLevel(l Level) stdslog.Level {
+	switch {
+	case l <= LevelErr:
+		return stdslog.LevelError
+	case l == LevelWarn:
+		return stdslog.LevelWarn
+	case l == LevelInfo:
+		return stdslog.LevelInfo
+	case l == LevelDebug:
+		return stdslog.LevelDebug
+	}
+	return stdslog.LevelDebug - 4
+}
+
+// fromStdLevel gets the Level for the log/slog level.
+func fromStdLevel(l stdslog.Level) Level {
+	switch {
+	case l >= stdslog.LevelError:
+		return LevelErr
+	case l >= stdslog.LevelWarn:
+		return LevelWarn
+	case l >= stdslog.LevelInfo:
+		return LevelInfo
+	case l >= stdslog.LevelDebug:
+		return LevelDebug
+	}
+	return LevelTrace
+}
+
+type handlerReporter struct {
+	h stdslog.Handler
+}
+
+// NewHandlerReporter gets a Reporter that forwards logs to a
+// log/slog Handler.
+// The Data of the log, without its Location, becomes the message,
+// and the source and Fields become attributes. Where the log was
+// made is the PC of the record, for Handlers that add the source.
+func NewHandlerReporter(h stdslog.Handler) Reporter {
+	return &handlerReporter{h: h}
+}
+
+func (r *handlerReporter) Log(l *Log) {
+	ctx := context.Background()
+	level := stdLevel(l.Level)
+	if !r.h.Enabled(ctx, level) {
+		return
+	}
+	record := stdslog.NewRecord(l.When, level, l.Message(), l.pc)
+	record.AddAttrs(stdslog.String("source", l.SourceString()))
+	if l.Err != nil {
+		record.AddAttrs(stdslog.Any("error", l.Err))
+	}
+	for _, k := range l.Fields.keys() {
+		record.AddAttrs(stdslog.Any(k, l.Fields[k]))
+	}
+	r.h.Handle(ctx, record)
+}
+
+// Handler is a log/slog Handler that logs to a Logger.
+// Attributes become Fields, with the keys of attributes in
+// groups prefixed by the group names joined with ".".
+type Handler struct {
+	l      Logger
+	prefix string
+}
+
+var _ stdslog.Handler = (*Handler)(nil)
+
+// NewHandler makes a Handler that logs to l.
+func NewHandler(l Logger) *Handler {
+	return &Handler{l: l}
+}
+
+// Enabled gets whether the Logger is logging at the level.
+func (h *Handler) Enabled(ctx context.Context, level stdslog.Level) bool {
+	return logAt(h.l, fromStdLevel(level), 0, nil)
+}
+
+// Handle logs the record.
+func (h *Handler) Handle(ctx context.Context, r stdslog.Record) error {
+	var fields Fields
+	if r.NumAttrs() > 0 {
+		fields = make(Fields, r.NumAttrs())
+		r.Attrs(func(a stdslog.Attr) bool {
+			addAttr(fields, h.prefix, a)
+			return true
+		})
+	}
+	a := []interface{}{r.Message}
+	if fields != nil {
+		a = append(a, fields)
+	}
+	logAt(h.l, fromStdLevel(r.Level), r.PC, a)
+	return nil
+}
+
+// WithAttrs gets a Handler that adds the attributes to each log.
+func (h *Handler) WithAttrs(attrs []stdslog.Attr) stdslog.Handler {
+	fields := make(Fields, len(attrs))
+	for _, a := range attrs {
+		addAttr(fields, h.prefix, a)
+	}
+	return &Handler{l: h.l.WithFields(fields), prefix: h.prefix}
+}
+
+// WithGroup gets a Handler that puts attributes in the group.
+func (h *Handler) WithGroup(name string) stdslog.Handler {
+	if name == "" {
+		return h
+	}
+	return &Handler{l: h.l, prefix: h.prefix + name + "."}
+}
+
+// addAttr adds the attribute to the fields, flattening groups.
+func addAttr(fields Fields, prefix string, a stdslog.Attr) {
+	v := a.Value.Resolve()
+	if v.Kind() == stdslog.KindGroup {
+		if a.Key != "" {
+			prefix += a.Key + "."
+		}
+		for _, ga := range v.Group() {
+			addAttr(fields, prefix, ga)
+		}
+		return
+	}
+	if a.Key == "" {
+		return
+	}
+	fields[prefix+a.Key] = v.Any()
+}
+
+// logAt logs a at the level, recording pc as the caller if
+// the Logger supports it.
+// If a is empty it only gets whether the Logger is logging
+// at the level.
+func logAt(l Logger, level Level, pc uintptr, a []interface{}) bool {
+	if pl, ok := l.(*logger); ok && len(a) > 0 && pc != 0 {
+		return pl.logPC(level, pc, a)
+	}
+	switch level {
+	case LevelErr:
+		return l.Err(a...)
+	case LevelWarn:
+		return l.Warn(a...)
+	case LevelInfo:
+		return l.Info(a...)
+	case LevelDebug:
+		return l.Debug(a...)
+	}
+	return l.Trace(a...)
+}
@@ -0,0 +1,76 @@
+package slog_test
+
+import (
+	"bytes"
+	"context"
+	stdslog "log/slog"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/pat/stop"
+	"github.com/stretchr/slog"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandlerReporter(t *testing.T) {
+
+	var buf bytes.Buffer
+	h := stdslog.NewTextHandler(&buf, &stdslog.HandlerOptions{Level: stdslog.LevelInfo})
+	r := slog.NewHandlerReporter(h)
+	when := time.Date(2015, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	r.Log(&slog.Log{Level: slog.LevelDebug, When: when, Source: []string{"parent"}, Data: []interface{}{"ignored"}})
+	r.Log(&slog.Log{Level: slog.LevelWarn, When: when, Source: []string{"parent", "child"}, Data: []interface{}{"something", "happened"}, Fields: slog.Fields{"status": 200}})
+
+	require.Equal(t, "time=2015-01-02T03:04:05.000Z level=WARN msg=\"something happened\" source=parent>child status=200\n", buf.String())
+
+	// the location of logs from a Logger is the source of the record
+	buf.Reset()
+	h = stdslog.NewTextHandler(&buf, &stdslog.HandlerOptions{AddSource: true})
+	l := slog.New("parent", slog.LevelInfo)
+	l.SetSync(true)
+	l.SetReporter(slog.NewHandlerReporter(h))
+	l.Info("something", "happened")
+	require.Contains(t, buf.String(), "/stdslog_test.go:")
+	require.Contains(t, buf.String(), " msg=\"something happened\" ")
+
+}
+
+func TestHandler(t *testing.T) {
+
+	var wg sync.WaitGroup
+
+	l := slog.New("parent", slog.LevelInfo)
+	defer func() {
+		l.Stop(stop.NoWait)
+		<-l.StopChan()
+	}()
+	r := NewTestReporter()
+	f := r.logFunc
+	r.logFunc = func(l *slog.Log) {
+		f(l)
+		wg.Done()
+	}
+	l.SetReporter(r)
+
+	std := stdslog.New(slog.NewHandler(l.New("std")))
+	require.False(t, std.Enabled(context.Background(), stdslog.LevelDebug))
+	require.True(t, std.Enabled(context.Background(), stdslog.LevelWarn))
+
+	wg.Add(2)
+	std.Debug("ignored")
+	std.With("request_id", "abc").WithGroup("http").Warn("slow request", "status", 200, stdslog.Group("timing", "ms", 1500))
+	std.Error("failed")
+	wg.Wait()
+
+	require.Equal(t, 2, len(r.logs))
+	require.Equal(t, slog.Source{"parent", "std"}, r.logs[0].Source)
+	require.Equal(t, slog.LevelWarn, r.logs[0].Level)
+	require.Contains(t, r.logs[0].Data[0], "stdslog_test.go:")
+	require.Equal(t, "slow request", r.logs[0].Data[1])
+	require.Equal(t, slog.Fields{"request_id": "abc", "http.status": int64(200), "http.timing.ms": int64(1500)}, r.logs[0].Fields)
+	require.Equal(t, slog.LevelErr, r.logs[1].Level)
+	require.Equal(t, "failed", r.logs[1].Data[1])
+
+}
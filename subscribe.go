@@ -0,0 +1,83 @@
+package slog
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// subscriptions holds the channels of those subscribed with
+// Subscribe.
+type subscriptions struct {
+	m      sync.Mutex
+	n      int32 // number of subscriptions, accessed atomically
+	subs   map[chan *Log]struct{}
+	closed bool
+}
+
+// closedLogs is the channel got by subscribing once the logger
+// has stopped.
+var closedLogs = func() chan *Log {
+	c := make(chan *Log)
+	close(c)
+	return c
+}()
+
+func (l *logger) Subscribe(buffer int) (<-chan *Log, func()) {
+	s := &l.root.tail
+	s.m.Lock()
+	defer s.m.Unlock()
+	if s.closed {
+		return closedLogs, func() {}
+	}
+	c := make(chan *Log, buffer)
+	if s.subs == nil {
+		s.subs = make(map[chan *Log]struct{})
+	}
+	s.subs[c] = struct{}{}
+	atomic.AddInt32(&s.n, 1)
+	return c, func() {
+		s.m.Lock()
+		s.remove(c)
+		s.m.Unlock()
+	}
+}
+
+// publish sends the log to each subscription, removing those
+// whose buffers are full.
+func (s *subscriptions) publish(item *Log) {
+	if atomic.LoadInt32(&s.n) == 0 {
+		return
+	}
+	s.m.Lock()
+	defer s.m.Unlock()
+	for c := range s.subs {
+		select {
+		case c <- item:
+		default:
+			s.remove(c)
+		}
+	}
+}
+
+// remove removes the subscription and closes its channel, if it
+// is still subscribed.
+// s.m must be held.
+func (s *subscriptions) remove(c chan *Log) {
+	if _, ok := s.subs[c]; !ok {
+		return
+	}
+	delete(s.subs, c)
+	atomic.AddInt32(&s.n, -1)
+	close(c)
+}
+
+// close removes every subscription, and stops any more being
+// made.
+func (s *subscriptions) close() {
+	s.m.Lock()
+	defer s.m.Unlock()
+	for c := range s.subs {
+		s.remove(c)
+	}
+	s.closed = true
+}
@@ -0,0 +1,83 @@
+package slog_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/slog"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSubscribe(t *testing.T) {
+
+	l := slog.New("parent", slog.LevelInfo)
+	r := NewTestReporter()
+	l.SetReporter(r)
+	l.SetSync(true)
+	l.AddHook(func(log *slog.Log) *slog.Log {
+		if log.Data[1] == "hidden" {
+			return nil
+		}
+		return log
+	})
+
+	logs, cancel := l.Subscribe(10)
+	other, cancelOther := l.Subscribe(10)
+	l.New("child").Info("one")
+	l.Info("hidden")
+	l.Warn("two")
+	cancelOther()
+	cancelOther() // more than once is fine
+	l.Info("three")
+
+	require.Equal(t, 3, len(r.logs))
+	require.Equal(t, r.logs[0], <-logs)
+	require.Equal(t, r.logs[1], <-logs)
+	require.Equal(t, r.logs[2], <-logs)
+	require.Equal(t, slog.Source{"parent", "child"}, r.logs[0].Source)
+
+	require.Equal(t, "one", (<-other).Data[1])
+	require.Equal(t, "two", (<-other).Data[1])
+	_, ok := <-other
+	require.False(t, ok)
+
+	// the channel is closed when the logger stops
+	require.NoError(t, l.StopContext(context.Background()))
+	_, ok = <-logs
+	require.False(t, ok)
+	cancel()
+	logs, cancel = l.Subscribe(10)
+	defer cancel()
+	_, ok = <-logs
+	require.False(t, ok)
+
+}
+
+func TestSubscribeSlow(t *testing.T) {
+
+	l := slog.New("parent", slog.LevelInfo)
+	l.SetReporter(NewTestReporter())
+	l.SetSync(true)
+	defer l.StopContext(context.Background())
+
+	slow, cancelSlow := l.Subscribe(2)
+	defer cancelSlow()
+	fast, cancelFast := l.Subscribe(2)
+	defer cancelFast()
+	for i := 0; i < 5; i++ {
+		require.True(t, l.Info(i))
+		require.Equal(t, i, (<-fast).Data[1])
+	}
+
+	// the slow subscriber gets what fit in its buffer, and is then
+	// unsubscribed
+	require.Equal(t, 0, (<-slow).Data[1])
+	require.Equal(t, 1, (<-slow).Data[1])
+	_, ok := <-slow
+	require.False(t, ok)
+
+	discarded, _ := slog.NilLogger.Subscribe(1)
+	_, ok = <-discarded
+	require.False(t, ok)
+
+}
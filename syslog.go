@@ -0,0 +1,186 @@
+package slog
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"sync"
+	"time"
+)
+
+// SyslogFacilityUser is the syslog facility for user-level
+// messages, and is used when SyslogOptions.Facility is zero.
+const SyslogFacilityUser = 1
+
+// DefaultSyslogTimeout is how long connecting to syslog may take
+// when SyslogOptions.Timeout is zero.
+const DefaultSyslogTimeout = 5 * time.Second
+
+// syslogSeverities maps levels to syslog severities.
+var syslogSeverities = map[Level]int{
+	LevelErr:   3,
+	LevelWarn:  4,
+	LevelInfo:  6,
+	LevelDebug: 7,
+	LevelTrace: 7,
+}
+
+// localSyslogAddrs are the addresses tried for the local syslog
+// daemon.
+var localSyslogAddrs = []string{"/dev/log", "/var/run/syslog", "/var/run/log"}
+
+// SyslogOptions represents the options for a SyslogReporter.
+type SyslogOptions struct {
+	// Network is the network of the syslog server, "udp", "tcp"
+	// or "unix". If empty, the local syslog daemon is used.
+	Network string
+	// Addr is the address of the syslog server.
+	Addr string
+	// Facility is the syslog facility, defaulting to
+	// SyslogFacilityUser.
+	Facility int
+	// Hostname is the hostname sent with each message, defaulting
+	// to os.Hostname.
+	Hostname string
+	// Timeout is how long connecting to syslog may take,
+	// defaulting to DefaultSyslogTimeout.
+	Timeout time.Duration
+}
+
+// SyslogReporter is a Reporter that sends logs to syslog in
+// the RFC 5424 format, using the source of the log as the
+// app name.
+type SyslogReporter struct {
+	m      sync.Mutex
+	opts   SyslogOptions
+	conn   net.Conn
+	pid    int
+	closed bool
+}
+
+var _ ErrReporter = (*SyslogReporter)(nil)
+var _ io.Closer = (*SyslogReporter)(nil)
+
+// NewSyslogReporter makes a SyslogReporter and connects it to
+// the syslog server.
+func NewSyslogReporter(opts SyslogOptions) (*SyslogReporter, error) {
+	if opts.Facility == 0 {
+		opts.Facility = SyslogFacilityUser
+	}
+	if opts.Hostname == "" {
+		opts.Hostname, _ = os.Hostname()
+	}
+	if opts.Timeout == 0 {
+		opts.Timeout = DefaultSyslogTimeout
+	}
+	s := &SyslogReporter{opts: opts, pid: os.Getpid()}
+	if err := s.connect(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func (s *SyslogReporter) connect() error {
+	if s.opts.Network != "" {
+		conn, err := net.DialTimeout(s.opts.Network, s.opts.Addr, s.opts.Timeout)
+		if err != nil {
+			return err
+		}
+		s.conn = conn
+		return nil
+	}
+	for _, addr := range localSyslogAddrs {
+		if conn, err := net.DialTimeout("unixgram", addr, s.opts.Timeout); err == nil {
+			s.conn = conn
+			return nil
+		}
+	}
+	return errors.New("slog: no local syslog daemon")
+}
+
+// Log sends the log to syslog, reconnecting once if sending
+// fails.
+func (s *SyslogReporter) Log(l *Log) {
+	s.Report(l)
+}
+
+// Report sends the log to syslog as Log does, returning an error
+// if it couldn't be sent, or os.ErrClosed once it's closed.
+func (s *SyslogReporter) Report(l *Log) error {
+	msg := s.format(l)
+	s.m.Lock()
+	defer s.m.Unlock()
+	if s.closed {
+		return os.ErrClosed
+	}
+	if s.conn != nil {
+		if _, err := s.conn.Write(msg); err == nil {
+			return nil
+		}
+		s.conn.Close()
+		s.conn = nil
+	}
+	if err := s.connect(); err != nil {
+		return err
+	}
+	_, err := s.conn.Write(msg)
+	return err
+}
+
+// Close closes the connection to syslog, after which logs are
+// no longer sent.
+func (s *SyslogReporter) Close() error {
+	s.m.Lock()
+	defer s.m.Unlock()
+	s.closed = true
+	if s.conn == nil {
+		return nil
+	}
+	err := s.conn.Close()
+	s.conn = nil
+	return err
+}
+
+// format formats the log as an RFC 5424 message, framed with its
+// length for stream connections.
+func (s *SyslogReporter) format(l *Log) []byte {
+	severity, ok := syslogSeverities[l.Level]
+	if !ok {
+		severity = syslogSeverities[LevelInfo]
+	}
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "<%d>1 %s %s %s %d - - %s",
+		s.opts.Facility*8+severity,
+		l.When.Format(time.RFC3339Nano),
+		syslogHeader(s.opts.Hostname, 255),
+		syslogHeader(l.SourceString(), 48),
+		s.pid,
+		sprint(l.text()),
+	)
+	switch s.opts.Network {
+	case "tcp", "tcp4", "tcp6", "unix":
+		return append([]byte(fmt.Sprintf("%d ", buf.Len())), buf.Bytes()...)
+	}
+	return buf.Bytes()
+}
+
+// syslogHeader makes s safe to use as a header field, which must be
+// printable ASCII without spaces, and at most max long.
+func syslogHeader(s string, max int) string {
+	b := []byte(s)
+	for i, c := range b {
+		if c < 33 || c > 126 {
+			b[i] = '_'
+		}
+	}
+	if len(b) > max {
+		b = b[:max]
+	}
+	if len(b) == 0 {
+		return "-"
+	}
+	return string(b)
+}
@@ -0,0 +1,80 @@
+package slog_test
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/slog"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSyslogReporterUDP(t *testing.T) {
+
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer conn.Close()
+
+	r, err := slog.NewSyslogReporter(slog.SyslogOptions{Network: "udp", Addr: conn.LocalAddr().String(), Hostname: "host"})
+	require.NoError(t, err)
+	defer r.Close()
+
+	when := time.Date(2015, 1, 2, 3, 4, 5, 0, time.UTC)
+	r.Log(&slog.Log{Level: slog.LevelWarn, When: when, Source: []string{"parent", "child"}, Data: []interface{}{"something", "happened"}})
+
+	buf := make([]byte, 1024)
+	conn.SetReadDeadline(time.Now().Add(time.Second))
+	n, _, err := conn.ReadFrom(buf)
+	require.NoError(t, err)
+	require.Equal(t, fmt.Sprintf("<12>1 2015-01-02T03:04:05Z host parent>child %d - - something happened", os.Getpid()), string(buf[:n]))
+
+}
+
+func TestSyslogReporterTCP(t *testing.T) {
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer ln.Close()
+
+	r, err := slog.NewSyslogReporter(slog.SyslogOptions{Network: "tcp", Addr: ln.Addr().String(), Hostname: "host", Facility: 16})
+	require.NoError(t, err)
+	defer r.Close()
+
+	conn, err := ln.Accept()
+	require.NoError(t, err)
+	defer conn.Close()
+
+	when := time.Date(2015, 1, 2, 3, 4, 5, 0, time.UTC)
+	r.Log(&slog.Log{Level: slog.LevelErr, When: when, Source: []string{"my app"}, Data: []interface{}{"failed"}})
+
+	var length int
+	br := bufio.NewReader(conn)
+	conn.SetReadDeadline(time.Now().Add(time.Second))
+	_, err = fmt.Fscanf(br, "%d ", &length)
+	require.NoError(t, err)
+	msg := make([]byte, length)
+	_, err = io.ReadFull(br, msg)
+	require.NoError(t, err)
+	require.Equal(t, fmt.Sprintf("<131>1 2015-01-02T03:04:05Z host my_app %d - - failed", os.Getpid()), string(msg))
+
+}
+
+func TestSyslogReporterClose(t *testing.T) {
+
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer conn.Close()
+
+	r, err := slog.NewSyslogReporter(slog.SyslogOptions{Network: "udp", Addr: conn.LocalAddr().String(), Hostname: "host"})
+	require.NoError(t, err)
+	require.NoError(t, r.Close())
+
+	err = r.Report(&slog.Log{Level: slog.LevelInfo, When: time.Now(), Source: []string{"parent"}, Data: []interface{}{"dropped"}})
+	require.Equal(t, os.ErrClosed, err)
+	require.NoError(t, r.Close())
+
+}
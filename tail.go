@@ -0,0 +1,116 @@
+package slog
+
+import (
+	"bytes"
+	"net/http"
+	"time"
+)
+
+// tailBuffer is the number of logs a TailHandler holds for each
+// client that is behind.
+const tailBuffer = 256
+
+// tailKeepAlive is how often a TailHandler sends a comment to
+// idle clients, so proxies don't close the connection.
+const tailKeepAlive = 30 * time.Second
+
+type tailHandler struct {
+	l RootLogger
+}
+
+// TailHandler gets an http.Handler that streams the logs of l as
+// they are reported, as server-sent events, e.g. for a
+// "/debug/logs" page on internal services:
+//
+//	new EventSource("/debug/logs?level=warn&source=parent>http")
+//
+// Each event is a log as written by NewJSONReporter, or by
+// TextFormatter if the format query parameter is "text".
+// The level parameter only streams logs at or more severe than
+// it, and the source parameter only those from sources matching
+// the pattern, written with the SourceSep of the logs, as a
+// SourceFilter matches them.
+// A client that falls too far behind is disconnected, and
+// EventSource will reconnect.
+func TailHandler(l RootLogger) http.Handler {
+	return &tailHandler{l: l}
+}
+
+func (h *tailHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	level := LevelEverything
+	if s := query.Get("level"); s != "" {
+		if level = ParseLevel(s); level == LevelInvalid {
+			http.Error(w, unknownLevel(s).Error(), http.StatusBadRequest)
+			return
+		}
+	}
+	var source *sourceSplit
+	if pattern := query.Get("source"); pattern != "" {
+		source = newSourceSplit(pattern)
+	}
+	f := JSONFormatter
+	if query.Get("format") == "text" {
+		f = TextFormatter
+	}
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "slog: streaming not supported", http.StatusInternalServerError)
+		return
+	}
+
+	logs, cancel := h.l.Subscribe(tailBuffer)
+	defer cancel()
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	keepAlive := time.NewTicker(tailKeepAlive)
+	defer keepAlive.Stop()
+	var buf bytes.Buffer
+	for {
+		select {
+		case l, ok := <-logs:
+			if !ok {
+				return
+			}
+			if l.Level > level || !matchSource(source, l) {
+				continue
+			}
+			buf.Reset()
+			writeEvent(&buf, f.Format(l))
+			if _, err := w.Write(buf.Bytes()); err != nil {
+				return
+			}
+		case <-keepAlive.C:
+			if _, err := w.Write([]byte(":\n\n")); err != nil {
+				return
+			}
+		case <-r.Context().Done():
+			return
+		}
+		flusher.Flush()
+	}
+}
+
+// matchSource gets whether the source of the log matches the
+// pattern, or the pattern is nil.
+func matchSource(pattern *sourceSplit, l *Log) bool {
+	if pattern == nil {
+		return true
+	}
+	parts := pattern.split(l.SourceSep)
+	return len(parts) <= len(l.Source) && matchParts(parts, l.Source)
+}
+
+// writeEvent writes b as the data of a server-sent event, one
+// data line for each of its lines.
+func writeEvent(buf *bytes.Buffer, b []byte) {
+	for _, line := range bytes.Split(bytes.TrimRight(b, "\n"), []byte("\n")) {
+		buf.WriteString("data: ")
+		buf.Write(line)
+		buf.WriteByte('\n')
+	}
+	buf.WriteByte('\n')
+}
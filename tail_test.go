@@ -0,0 +1,92 @@
+package slog_test
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/slog"
+	"github.com/stretchr/testify/require"
+)
+
+// readEvent reads the data of the next server-sent event.
+func readEvent(t *testing.T, r *bufio.Reader) string {
+	var data []string
+	for {
+		line, err := r.ReadString('\n')
+		require.NoError(t, err)
+		line = strings.TrimSuffix(line, "\n")
+		if line == "" {
+			return strings.Join(data, "\n")
+		}
+		data = append(data, strings.TrimPrefix(line, "data: "))
+	}
+}
+
+func TestTailHandler(t *testing.T) {
+
+	l := slog.New("parent", slog.LevelInfo)
+	l.SetReporter(NewTestReporter())
+	l.SetSync(true)
+	server := httptest.NewServer(slog.TailHandler(l))
+	defer server.Close()
+
+	res, err := http.Get(server.URL + "?level=warn&source=parent>db")
+	require.NoError(t, err)
+	defer res.Body.Close()
+	require.Equal(t, http.StatusOK, res.StatusCode)
+	require.Equal(t, "text/event-stream", res.Header.Get("Content-Type"))
+
+	l.Warn("not from db")
+	l.New("db").Info("not severe")
+	l.New("db").New("pool").Err("failed")
+	l.New("db").Warn("slow")
+
+	body := bufio.NewReader(res.Body)
+	var item struct {
+		Level  string        `json:"level"`
+		Source string        `json:"source"`
+		Data   []interface{} `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(readEvent(t, body)), &item))
+	require.Equal(t, "error", item.Level)
+	require.Equal(t, "parent>db>pool", item.Source)
+	require.Equal(t, "failed", item.Data[0])
+	require.NoError(t, json.Unmarshal([]byte(readEvent(t, body)), &item))
+	require.Equal(t, "slow", item.Data[0])
+
+	// the stream ends when the logger stops
+	require.NoError(t, l.StopContext(context.Background()))
+	_, err = body.ReadString('\n')
+	require.Error(t, err)
+
+}
+
+func TestTailHandlerText(t *testing.T) {
+
+	l := slog.New("parent", slog.LevelInfo)
+	l.SetReporter(NewTestReporter())
+	l.SetSync(true)
+	defer l.StopContext(context.Background())
+	server := httptest.NewServer(slog.TailHandler(l))
+	defer server.Close()
+
+	res, err := http.Get(server.URL + "?level=bogus")
+	require.NoError(t, err)
+	res.Body.Close()
+	require.Equal(t, http.StatusBadRequest, res.StatusCode)
+
+	res, err = http.Get(server.URL + "?format=text")
+	require.NoError(t, err)
+	defer res.Body.Close()
+
+	l.Info("two\nlines")
+	event := readEvent(t, bufio.NewReader(res.Body))
+	require.Contains(t, event, "parent")
+	require.True(t, strings.HasSuffix(event, "two\n\tlines"), event)
+
+}
@@ -0,0 +1,44 @@
+package slog
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// ElapsedKey is the field a Timer puts the time an operation took
+// in.
+const ElapsedKey = "elapsed"
+
+// Timer times an operation, made by Logger.Timed.
+type Timer struct {
+	l     *logger
+	op    string
+	start time.Time
+	done  int32
+}
+
+func (l *logger) Timed(operation string) *Timer {
+	t := &Timer{l: l, op: operation, start: l.now()}
+	l.logPC(LevelInfo, callerPC(1), []interface{}{operation, "started"})
+	return t
+}
+
+// Done logs that the operation has finished, with the time it
+// took under ElapsedKey, at LevelInfo, or at LevelErr with the
+// error if err isn't nil.
+// Only the first call to Done logs.
+//
+//	t := l.Timed("rebuilding index")
+//	err := rebuild()
+//	t.Done(err)
+func (t *Timer) Done(err error) {
+	if t.l == nil || !atomic.CompareAndSwapInt32(&t.done, 0, 1) {
+		return
+	}
+	fields := Fields{ElapsedKey: t.l.now().Sub(t.start)}
+	if err != nil {
+		t.l.logPC(LevelErr, callerPC(1), []interface{}{t.op, "failed", fields, Error(err)})
+		return
+	}
+	t.l.logPC(LevelInfo, callerPC(1), []interface{}{t.op, "finished", fields})
+}
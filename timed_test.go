@@ -0,0 +1,47 @@
+package slog_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/slog"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTimed(t *testing.T) {
+
+	l := slog.New("parent", slog.LevelInfo)
+	r := NewTestReporter()
+	l.SetReporter(r)
+	clock := &testClock{now: time.Date(2015, 1, 1, 0, 0, 0, 0, time.UTC)}
+	l.SetClock(clock)
+
+	timer := l.Timed("rebuilding index")
+	clock.Add(2 * time.Second)
+	timer.Done(nil)
+
+	err := errors.New("disk full")
+	timer = l.New("child").Timed("compacting")
+	clock.Add(time.Second)
+	timer.Done(err)
+	timer.Done(nil)
+
+	slog.NilLogger.Timed("nothing").Done(nil)
+	require.NoError(t, l.StopContext(context.Background()))
+
+	require.Equal(t, 4, len(r.logs))
+	require.Equal(t, []interface{}{"rebuilding index", "started"}, r.logs[0].Data[1:])
+	require.True(t, strings.HasPrefix(r.logs[0].Data[0].(string), "( timed_test.go:"))
+	require.Equal(t, slog.LevelInfo, r.logs[1].Level)
+	require.Equal(t, []interface{}{"rebuilding index", "finished"}, r.logs[1].Data[1:])
+	require.Equal(t, slog.Fields{slog.ElapsedKey: 2 * time.Second}, r.logs[1].Fields)
+	require.True(t, strings.HasPrefix(r.logs[1].Data[0].(string), "( timed_test.go:"))
+	require.Equal(t, slog.LevelErr, r.logs[3].Level)
+	require.Equal(t, []interface{}{"compacting", "failed"}, r.logs[3].Data[1:])
+	require.Equal(t, slog.Fields{slog.ElapsedKey: time.Second}, r.logs[3].Fields)
+	require.Equal(t, err, r.logs[3].Err)
+
+}
@@ -0,0 +1,119 @@
+package slog
+
+import (
+	"sort"
+	"strings"
+	"sync/atomic"
+)
+
+// TreeLimit is the number of sources a RootLogger keeps track of
+// for Tree. Loggers made for further sources still log, but aren't
+// in the Tree.
+const TreeLimit = 1024
+
+// Tree is a snapshot of a source that loggers have been made for,
+// and of its children.
+type Tree struct {
+	// Source is the full source, joined with ">".
+	Source string `json:"source"`
+	// Level is the level logs from the source are logged at,
+	// taking source levels and boosts into account.
+	Level Level `json:"level"`
+	// Logs is the number of logs made by loggers with the source,
+	// not counting its children.
+	Logs uint64 `json:"logs"`
+	// Children are the sources of children of the loggers, ordered
+	// by source.
+	Children []Tree `json:"children,omitempty"`
+}
+
+// treeNode is a source in the tree of a root logger.
+type treeNode struct {
+	logs     uint64 // first, to be aligned for atomic use
+	parent   *treeNode
+	children map[string]*treeNode // protected by tm of the root logger
+	path     atomic.Value         // holds the *nodePath of the source
+}
+
+// treeChild gets the node for the child source of the parent node,
+// adding it to the tree if there's room.
+// It gets nil if there isn't, or the parent isn't in the tree.
+// Must only be called on the root logger.
+func (l *logger) treeChild(parent *treeNode, name string) *treeNode {
+	if parent == nil {
+		return nil
+	}
+	l.tm.Lock()
+	defer l.tm.Unlock()
+	if n, ok := parent.children[name]; ok {
+		return n
+	}
+	if l.nodes >= TreeLimit {
+		return nil
+	}
+	n := &treeNode{parent: parent}
+	if parent.children == nil {
+		parent.children = make(map[string]*treeNode)
+	}
+	parent.children[name] = n
+	l.nodes++
+	return n
+}
+
+// treeNode gets the node of the source of this logger.
+func (l *logger) treeNode() *treeNode {
+	return l.node.Load().(*treeNode)
+}
+
+func (l *logger) Tree() Tree {
+	root := l.root
+	n := l.treeNode()
+	if n == nil {
+		return Tree{Source: l.sepName(l.source()), Level: l.effectiveLevel()}
+	}
+	root.tm.Lock()
+	t := tree(n, l.source())
+	root.tm.Unlock()
+	// the levels are set without tm held, as SetSource holds m
+	// while it takes tm
+	root.setTreeLevels(&t)
+	root.setTreeSources(&t)
+	return t
+}
+
+// tree makes the snapshot of the node, without its levels.
+// Must be called with tm of the root logger held.
+func tree(n *treeNode, source string) Tree {
+	t := Tree{
+		Source: source,
+		Logs:   atomic.LoadUint64(&n.logs),
+	}
+	names := make([]string, 0, len(n.children))
+	for name := range n.children {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		t.Children = append(t.Children, tree(n.children[name], strings.Join([]string{source, name}, nestedLogSep)))
+	}
+	return t
+}
+
+// setTreeSources writes the sources of the snapshot and its
+// children with the SourceSep of the logger.
+// Must only be called on the root logger.
+func (l *logger) setTreeSources(t *Tree) {
+	t.Source = l.sepName(t.Source)
+	for i := range t.Children {
+		l.setTreeSources(&t.Children[i])
+	}
+}
+
+// setTreeLevels sets the levels of the snapshot and its children.
+// Must only be called on the root logger.
+func (l *logger) setTreeLevels(t *Tree) {
+	t.Level = l.levelOf(t.Source)
+	for i := range t.Children {
+		l.setTreeLevels(&t.Children[i])
+	}
+}
@@ -0,0 +1,43 @@
+package slog_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/slog"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTree(t *testing.T) {
+
+	l := slog.Discard("parent")
+	l.SetLevel(slog.LevelInfo)
+	l.SetSourceLevel("parent>db", slog.LevelDebug)
+
+	http := l.New("http")
+	db := l.New("db")
+	pool := db.New("pool").With("size", 10)
+	http.Info("one")
+	http.New("http").Info("two")
+	http.Debug("skipped")
+	pool.Debug("three")
+	l.New("db").Info("four")
+	renamed := l.New("old")
+	renamed.SetSource("new")
+	renamed.Info("five")
+
+	require.Equal(t, slog.Tree{
+		Source: "parent",
+		Level:  slog.LevelInfo,
+		Children: []slog.Tree{
+			{Source: "parent>db", Level: slog.LevelDebug, Logs: 1, Children: []slog.Tree{
+				{Source: "parent>db>pool", Level: slog.LevelDebug, Logs: 1},
+			}},
+			{Source: "parent>http", Level: slog.LevelInfo, Logs: 1, Children: []slog.Tree{
+				{Source: "parent>http>http", Level: slog.LevelInfo, Logs: 1},
+			}},
+			{Source: "parent>new", Level: slog.LevelInfo, Logs: 1},
+			{Source: "parent>old", Level: slog.LevelInfo},
+		},
+	}, l.Tree())
+
+}
@@ -0,0 +1,70 @@
+package slog
+
+import (
+	"fmt"
+	"unicode/utf8"
+)
+
+// TruncatedSuffix is added to the values cut short by a Truncate
+// hook.
+const TruncatedSuffix = "...[truncated]"
+
+// OriginalSizeKey is the field a Truncate hook adds to logs it
+// cuts short, holding the size of the log before it was.
+const OriginalSizeKey = "original_size"
+
+// Truncate gets a Hook that limits the size of logs to about max
+// bytes, so dumped request bodies and the like don't overwhelm
+// reporters.
+// The size of a log is the length of its Data, not counting its
+// location, and the keys and values of its Fields written as text. Values beyond max are cut
+// short, ending with TruncatedSuffix, and the size of the whole
+// log is added to its Fields as OriginalSizeKey.
+func Truncate(max int) Hook {
+	return func(l *Log) *Log {
+		size := 0
+		for _, d := range l.message() {
+			size += len(fmt.Sprint(d))
+		}
+		keys := l.Fields.keys()
+		for _, k := range keys {
+			size += len(k) + len(fmt.Sprint(l.Fields[k]))
+		}
+		if size <= max {
+			return l
+		}
+		left := max
+		cut := func(v interface{}) interface{} {
+			s := fmt.Sprint(v)
+			if len(s) <= left {
+				left -= len(s)
+				return v
+			}
+			n := left
+			left = 0
+			for n > 0 && !utf8.RuneStart(s[n]) {
+				n--
+			}
+			return s[:n] + TruncatedSuffix
+		}
+		truncated := *l
+		truncated.Data = make([]interface{}, len(l.Data))
+		for i, d := range l.Data {
+			if i == 0 && l.hasLocation() {
+				truncated.Data[i] = d
+				continue
+			}
+			truncated.Data[i] = cut(d)
+		}
+		truncated.Fields = make(Fields, len(l.Fields)+1)
+		for _, k := range keys {
+			left -= len(k)
+			if left < 0 {
+				left = 0
+			}
+			truncated.Fields[k] = cut(l.Fields[k])
+		}
+		truncated.Fields[OriginalSizeKey] = size
+		return &truncated
+	}
+}
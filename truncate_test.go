@@ -0,0 +1,52 @@
+package slog_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/slog"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTruncate(t *testing.T) {
+
+	hook := slog.Truncate(20)
+
+	small := &slog.Log{Data: []interface{}{"small"}, Fields: slog.Fields{"k": 1}}
+	require.Equal(t, small, hook(small))
+
+	body := strings.Repeat("x", 100)
+	fields := slog.Fields{"body": body, "id": 7}
+	l := &slog.Log{Data: []interface{}{"request", 42}, Fields: fields}
+	truncated := hook(l)
+	require.Equal(t, []interface{}{"request", 42}, truncated.Data)
+	require.Equal(t, "xxxxxxx"+slog.TruncatedSuffix, truncated.Fields["body"])
+	require.Equal(t, slog.TruncatedSuffix, truncated.Fields["id"])
+	require.Equal(t, 116, truncated.Fields[slog.OriginalSizeKey])
+
+	// the log is not changed
+	require.Equal(t, body, fields["body"])
+
+	// runes aren't split
+	truncated = slog.Truncate(4)(&slog.Log{Data: []interface{}{"añb", "more"}})
+	require.Equal(t, []interface{}{"añb", slog.TruncatedSuffix}, truncated.Data)
+
+	truncated = slog.Truncate(2)(&slog.Log{Data: []interface{}{"añb"}})
+	require.Equal(t, []interface{}{"a" + slog.TruncatedSuffix}, truncated.Data)
+
+	// the location of a log is kept, and doesn't count
+	lg := slog.New("parent", slog.LevelInfo)
+	lg.SetSync(true)
+	r := NewTestReporter()
+	lg.SetReporter(r)
+	lg.AddHook(slog.Truncate(4))
+	lg.Info("abcd")
+	lg.Info("abcdef")
+	require.Len(t, r.logs, 2)
+	require.Equal(t, "abcd", r.logs[0].Message())
+	require.Nil(t, r.logs[0].Fields)
+	require.Contains(t, r.logs[1].Location(), "truncate_test.go:")
+	require.Equal(t, "abcd"+slog.TruncatedSuffix, r.logs[1].Message())
+	require.Equal(t, 6, r.logs[1].Fields[slog.OriginalSizeKey])
+
+}
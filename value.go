@@ -0,0 +1,144 @@
+package slog
+
+import (
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+)
+
+// The limits on how much of a struct, map or slice structured
+// reporters expand.
+const (
+	// ExpandDepth is how deeply values are expanded. Values nested
+	// more deeply are written with fmt.
+	ExpandDepth = 5
+	// ExpandSize is the number of fields, entries or items of each
+	// value that are expanded. The rest are summed up as "...".
+	ExpandSize = 100
+)
+
+// maxLogValues is the number of times LogValue is called on the
+// values it gets, in case LogValuers get each other forever.
+const maxLogValues = 10
+
+// LogValuer is implemented by types that choose how they are
+// logged, such as to leave out secrets, or to log a summary of
+// something big. LogValue is called when the log is reported, in
+// the goroutine that reports logs, and may get another LogValuer.
+type LogValuer interface {
+	LogValue() interface{}
+}
+
+// logValue gets the value v is logged as, working out Lazy values
+// and calling LogValue.
+func logValue(v interface{}) interface{} {
+	if lv, ok := v.(lazyValue); ok {
+		v = lv()
+	}
+	for i := 0; i < maxLogValues; i++ {
+		lv, ok := v.(LogValuer)
+		if !ok {
+			break
+		}
+		v = lv.LogValue()
+	}
+	return v
+}
+
+// expand gets v with the structs and maps in it expanded into
+// map[string]interface{}, slices and arrays expanded into
+// []interface{}, and everything else given to leaf, for reporters
+// that write structured data. The fields of structs are named by
+// their json tags, like encoding/json names them.
+func expand(v interface{}, leaf func(interface{}) interface{}) interface{} {
+	return expandValue(logValue(v), 0, leaf)
+}
+
+func expandValue(v interface{}, depth int, leaf func(interface{}) interface{}) interface{} {
+	switch v.(type) {
+	case nil, string, bool, int, int8, int16, int32, int64,
+		uint, uint8, uint16, uint32, uint64, float32, float64,
+		[]byte, time.Time, time.Duration, error, fmt.Stringer,
+		json.Marshaler, encoding.TextMarshaler:
+		// these say how they are written themselves
+		return leaf(v)
+	}
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Pointer || rv.Kind() == reflect.Interface {
+		if rv.IsNil() {
+			return leaf(nil)
+		}
+		rv = rv.Elem()
+	}
+	switch rv.Kind() {
+	case reflect.Struct, reflect.Map, reflect.Slice, reflect.Array:
+	default:
+		return leaf(rv.Interface())
+	}
+	if depth >= ExpandDepth {
+		return fmt.Sprint(v)
+	}
+	next := func(v reflect.Value) interface{} {
+		if !v.CanInterface() {
+			return nil
+		}
+		return expandValue(logValue(v.Interface()), depth+1, leaf)
+	}
+	switch rv.Kind() {
+	case reflect.Struct:
+		t := rv.Type()
+		m := make(map[string]interface{})
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			name := f.Name
+			if tag, ok := f.Tag.Lookup("json"); ok {
+				if tag, _, _ = strings.Cut(tag, ","); tag == "-" {
+					continue
+				} else if tag != "" {
+					name = tag
+				}
+			}
+			if !f.IsExported() {
+				continue
+			}
+			if len(m) == ExpandSize {
+				m["..."] = fmt.Sprintf("%d more", t.NumField()-i)
+				break
+			}
+			m[name] = next(rv.Field(i))
+		}
+		return m
+	case reflect.Map:
+		keys := rv.MapKeys()
+		names := make([]string, len(keys))
+		byName := make(map[string]reflect.Value, len(keys))
+		for i, k := range keys {
+			names[i] = fmt.Sprint(k.Interface())
+			byName[names[i]] = k
+		}
+		sort.Strings(names)
+		m := make(map[string]interface{}, min(len(names), ExpandSize+1))
+		for i, name := range names {
+			if i == ExpandSize {
+				m["..."] = fmt.Sprintf("%d more", len(names)-i)
+				break
+			}
+			m[name] = next(rv.MapIndex(byName[name]))
+		}
+		return m
+	}
+	n := rv.Len()
+	a := make([]interface{}, 0, min(n, ExpandSize+1))
+	for i := 0; i < n; i++ {
+		if i == ExpandSize {
+			a = append(a, fmt.Sprintf("...%d more", n-i))
+			break
+		}
+		a = append(a, next(rv.Index(i)))
+	}
+	return a
+}
@@ -0,0 +1,129 @@
+package slog_test
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/slog"
+	"github.com/stretchr/testify/require"
+)
+
+type address struct {
+	City   string `json:"city"`
+	Secret string `json:"-"`
+	Zip    int
+	note   string
+}
+
+type user struct {
+	Name    string
+	Address *address
+	Tags    []string
+	Meta    map[string]int
+}
+
+// userID logs as the ID of the user only.
+type userID struct {
+	id   int
+	name string
+}
+
+func (u userID) LogValue() interface{} {
+	return map[string]int{"id": u.id}
+}
+
+func TestExpandJSON(t *testing.T) {
+
+	u := user{
+		Name:    "Mat",
+		Address: &address{City: "Boulder", Secret: "hidden", Zip: 80301, note: "unexported"},
+		Tags:    []string{"a", "b"},
+		Meta:    map[string]int{"visits": 2},
+	}
+	b := slog.JSONFormatter.Format(&slog.Log{
+		Level:  slog.LevelInfo,
+		When:   time.Date(2015, 1, 2, 3, 4, 5, 0, time.UTC),
+		Source: slog.Source{"parent"},
+		Data:   []interface{}{"( main.go:12 )", u, userID{id: 1, name: "Mat"}},
+		Fields: slog.Fields{"user": &u, "nil": (*user)(nil), "when": time.Date(2015, 1, 2, 3, 4, 5, 0, time.UTC)},
+	})
+	var item map[string]interface{}
+	require.NoError(t, json.Unmarshal(b, &item))
+
+	expanded := map[string]interface{}{
+		"Name":    "Mat",
+		"Address": map[string]interface{}{"city": "Boulder", "Zip": 80301.0},
+		"Tags":    []interface{}{"a", "b"},
+		"Meta":    map[string]interface{}{"visits": 2.0},
+	}
+	require.Equal(t, []interface{}{"( main.go:12 )", expanded, map[string]interface{}{"id": 1.0}}, item["data"])
+	fields := item["fields"].(map[string]interface{})
+	require.Equal(t, expanded, fields["user"])
+	require.Nil(t, fields["nil"])
+	require.Equal(t, "2015-01-02T03:04:05Z", fields["when"])
+
+}
+
+type nested struct {
+	Next *nested
+}
+
+func TestExpandLimits(t *testing.T) {
+
+	n := &nested{}
+	n.Next = n
+	big := make([]int, slog.ExpandSize+5)
+	b := slog.JSONFormatter.Format(&slog.Log{Fields: slog.Fields{"loop": n, "big": big}})
+	var item struct {
+		Fields struct {
+			Loop map[string]interface{}
+			Big  []interface{}
+		}
+	}
+	require.NoError(t, json.Unmarshal(b, &item))
+
+	depth := 0
+	var v interface{} = item.Fields.Loop
+	for {
+		m, ok := v.(map[string]interface{})
+		if !ok {
+			break
+		}
+		v = m["Next"]
+		depth++
+	}
+	require.Equal(t, slog.ExpandDepth, depth)
+	require.IsType(t, "", v)
+
+	require.Len(t, item.Fields.Big, slog.ExpandSize+1)
+	require.Equal(t, "...5 more", item.Fields.Big[slog.ExpandSize])
+
+}
+
+func TestLogValuer(t *testing.T) {
+
+	l := slog.New("parent", slog.LevelInfo)
+	var buf strings.Builder
+	l.SetReporter(slog.NewWriterReporter(&buf, slog.TextFormatter))
+	l.SetSync(true)
+
+	l.Info("logged in", userID{id: 1, name: "Mat"}, slog.Fields{"user": userID{id: 2, name: "David"}})
+	require.Contains(t, buf.String(), "logged in map[id:1] user=map[id:2]")
+	require.NotContains(t, buf.String(), "Mat")
+	require.NotContains(t, buf.String(), "David")
+
+}
+
+func TestExpandLogfmt(t *testing.T) {
+
+	b := slog.LogfmtFormatter.Format(&slog.Log{
+		Level:  slog.LevelInfo,
+		Source: slog.Source{"parent"},
+		Data:   []interface{}{"hi"},
+		Fields: slog.Fields{"user": user{Name: "Mat", Address: &address{City: "Boulder"}}, "empty": map[string]int{}},
+	})
+	require.Contains(t, string(b), " empty=map[] user.Address.Zip=0 user.Address.city=Boulder user.Meta=map[] user.Name=Mat user.Tags=[]\n")
+
+}
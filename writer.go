@@ -0,0 +1,236 @@
+package slog
+
+import (
+	"bytes"
+	"io"
+	"log"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Formatter formats logs for a Reporter made with
+// NewWriterReporter.
+type Formatter interface {
+	// Format formats the log, including any trailing newline.
+	// If it returns nil the log isn't written.
+	Format(l *Log) []byte
+}
+
+// ErrFormatter represents formatters that can tell why they
+// couldn't format a log.
+type ErrFormatter interface {
+	Formatter
+	// FormatErr does the same as Format, returning an error if it
+	// couldn't format the log.
+	FormatErr(l *Log) ([]byte, error)
+}
+
+// format formats the log with f, getting any error if f is an
+// ErrFormatter.
+func format(f Formatter, l *Log) ([]byte, error) {
+	if ef, ok := f.(ErrFormatter); ok {
+		return ef.FormatErr(l)
+	}
+	return f.Format(l), nil
+}
+
+// FormatterFunc is a func that can be used as a Formatter.
+type FormatterFunc func(l *Log) []byte
+
+// Format calls f(l).
+func (f FormatterFunc) Format(l *Log) []byte {
+	return f(l)
+}
+
+// TimeFormat represents how a Formatter writes the time of logs.
+type TimeFormat struct {
+	// Layout is the layout of the time, as time.Format takes,
+	// defaulting to the one the Formatter normally uses.
+	Layout string
+	// Location is where the time is written for, e.g. time.UTC,
+	// defaulting to the location of the time of the log, which is
+	// normally time.Local.
+	Location *time.Location
+}
+
+// format formats t, with layout if there is no Layout.
+func (f TimeFormat) format(t time.Time, layout string) string {
+	if f.Layout != "" {
+		layout = f.Layout
+	}
+	if f.Location != nil {
+		t = t.In(f.Location)
+	}
+	return t.Format(layout)
+}
+
+// TextFormatter formats logs as lines of text, like the Reporter
+// made by NewLogReporter.
+var TextFormatter = NewTextFormatter(TimeFormat{})
+
+// NewTextFormatter makes a Formatter like TextFormatter that
+// writes times as tf says.
+func NewTextFormatter(tf TimeFormat) Formatter {
+	return FormatterFunc(func(l *Log) []byte {
+		return formatText(l, tf)
+	})
+}
+
+func formatText(l *Log, tf TimeFormat) []byte {
+	args := []interface{}{tf.format(l.When, "2006/01/02 15:04:05"), l.SourceString() + ":"}
+	if l.Caller != nil {
+		args = append(args, l.Caller.Function)
+	}
+	args = append(args, l.text()...)
+	return []byte(lines(sprint(args), l.Stack) + "\n")
+}
+
+// lines indents the lines after the first of the text of a log,
+// and its stack if it has one, so values with more than one line,
+// such as stack traces, are clearly part of the log.
+func lines(text, stack string) string {
+	text = strings.TrimRight(text, "\n")
+	if stack != "" {
+		text += "\n" + strings.TrimRight(stack, "\n")
+	}
+	return strings.Replace(text, "\n", "\n\t", -1)
+}
+
+type writerReporter struct {
+	m sync.Mutex
+	w io.Writer
+	f Formatter
+}
+
+// NewWriterReporter gets a Reporter that writes each log to w,
+// formatted by f.
+// Each log is written with a single Write.
+// The Reporter is an ErrReporter, failing if the Write does.
+func NewWriterReporter(w io.Writer, f Formatter) Reporter {
+	return &writerReporter{w: w, f: f}
+}
+
+func (r *writerReporter) Log(l *Log) {
+	r.Report(l)
+}
+
+func (r *writerReporter) Report(l *Log) error {
+	b, err := format(r.f, l)
+	if b == nil {
+		return err
+	}
+	r.m.Lock()
+	defer r.m.Unlock()
+	_, err = r.w.Write(b)
+	return err
+}
+
+type logWriter struct {
+	m     sync.Mutex
+	l     Logger
+	level Level
+	buf   []byte
+}
+
+// maxWriterLine is the most of a line a Writer keeps before it logs
+// it, so a writer that never writes a newline doesn't grow it forever.
+const maxWriterLine = 64 << 10
+
+// Writer gets an io.Writer that logs each line written to it to l
+// at the level, so libraries that write to an io.Writer or a
+// log.Logger can log to slog:
+//
+//	server.ErrorLog = log.New(slog.Writer(l, slog.LevelErr), "", 0)
+//
+// A line that doesn't end with a newline is kept until the rest
+// of it is written, up to 64KiB, after which what's kept is logged
+// and the rest of the line is logged after it. Empty lines aren't
+// logged.
+// Lines are logged as made where the Write that finished them was
+// called from, outside of the packages that write for their
+// callers, such as log and fmt.
+func Writer(l Logger, level Level) io.Writer {
+	return &logWriter{l: l, level: level}
+}
+
+// writerPackages are the packages whose frames are skipped to find
+// the code that wrote to a Writer.
+var writerPackages = []string{"bufio.", "fmt.", "io.", "log.", "log/slog."}
+
+// writerPC gets the program counter of the code that wrote to a
+// Writer, outside of writerPackages, or of the caller of Write if
+// there isn't one.
+func writerPC() uintptr {
+	var pcs [32]uintptr
+	n := runtime.Callers(3, pcs[:])
+	if n == 0 {
+		return 0
+	}
+	frames := runtime.CallersFrames(pcs[:n])
+	for {
+		frame, more := frames.Next()
+		if !writerPackage(frame.Function) {
+			// the PC of a frame is the call, not the return address
+			// that callerPC gets
+			return frame.PC + 1
+		}
+		if !more {
+			return pcs[0]
+		}
+	}
+}
+
+// writerPackage gets whether the function is in one of
+// writerPackages.
+func writerPackage(function string) bool {
+	for _, pkg := range writerPackages {
+		if strings.HasPrefix(function, pkg) {
+			return true
+		}
+	}
+	return false
+}
+
+func (w *logWriter) Write(p []byte) (int, error) {
+	pc := writerPC()
+	w.m.Lock()
+	defer w.m.Unlock()
+	w.buf = append(w.buf, p...)
+	for {
+		var line string
+		if i := bytes.IndexByte(w.buf, '\n'); i >= 0 && i <= maxWriterLine {
+			line = strings.TrimSuffix(string(w.buf[:i]), "\r")
+			w.buf = w.buf[i+1:]
+		} else if len(w.buf) >= maxWriterLine {
+			line = string(w.buf[:maxWriterLine])
+			w.buf = w.buf[maxWriterLine:]
+		} else {
+			break
+		}
+		if line != "" {
+			logAt(w.l, w.level, pc, []interface{}{line})
+		}
+	}
+	if len(w.buf) == 0 {
+		w.buf = nil
+	}
+	return len(p), nil
+}
+
+// RedirectStdLog makes the standard log package log to l at the
+// level, so stray log.Printf calls from dependencies are logged
+// along with everything else.
+// The flags of the log package are cleared while it's redirected,
+// as logs have their own time. Its prefix is kept.
+// restore sets the output and flags back to what they were.
+func RedirectStdLog(l Logger, level Level) (restore func()) {
+	out, flags := log.Writer(), log.Flags()
+	log.SetOutput(Writer(l, level))
+	log.SetFlags(0)
+	return func() {
+		log.SetOutput(out)
+		log.SetFlags(flags)
+	}
+}
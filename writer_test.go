@@ -0,0 +1,164 @@
+package slog_test
+
+import (
+	"bytes"
+	"context"
+	"log"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/slog"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriterReporter(t *testing.T) {
+
+	var buf bytes.Buffer
+	r := slog.NewWriterReporter(&buf, slog.FormatterFunc(func(l *slog.Log) []byte {
+		if l.Level == slog.LevelDebug {
+			return nil
+		}
+		return []byte(l.Level.String() + "\n")
+	}))
+
+	r.Log(&slog.Log{Level: slog.LevelWarn})
+	r.Log(&slog.Log{Level: slog.LevelDebug})
+	r.Log(&slog.Log{Level: slog.LevelInfo})
+
+	require.Equal(t, "warning\ninfo\n", buf.String())
+
+}
+
+func TestTextFormatter(t *testing.T) {
+
+	when := time.Date(2015, 1, 2, 3, 4, 5, 0, time.UTC)
+	b := slog.TextFormatter.Format(&slog.Log{
+		Level:  slog.LevelInfo,
+		When:   when,
+		Source: []string{"parent", "child"},
+		Data:   []interface{}{"( main.go:12 )", "something", "happened"},
+		Fields: slog.Fields{"status": 200},
+	})
+
+	require.Equal(t, "2015/01/02 03:04:05 parent>child: ( main.go:12 ) something happened status=200\n", string(b))
+
+}
+
+func TestTimeFormat(t *testing.T) {
+
+	tokyo := time.FixedZone("Tokyo", 9*60*60)
+	item := &slog.Log{
+		Level:  slog.LevelInfo,
+		When:   time.Date(2015, 1, 2, 3, 4, 5, 600000000, time.UTC),
+		Source: []string{"parent"},
+		Data:   []interface{}{"( main.go:12 )", "hi"},
+	}
+
+	b := slog.NewTextFormatter(slog.TimeFormat{Layout: time.RFC3339Nano}).Format(item)
+	require.Equal(t, "2015-01-02T03:04:05.6Z parent: ( main.go:12 ) hi\n", string(b))
+	b = slog.NewTextFormatter(slog.TimeFormat{Location: tokyo}).Format(item)
+	require.Equal(t, "2015/01/02 12:04:05 parent: ( main.go:12 ) hi\n", string(b))
+
+	b = slog.NewJSONFormatter(slog.TimeFormat{Location: tokyo}).Format(item)
+	require.Contains(t, string(b), `"time":"2015-01-02T12:04:05.6+09:00"`)
+	b = slog.NewJSONFormatter(slog.TimeFormat{Layout: time.Kitchen}).Format(item)
+	require.Contains(t, string(b), `"time":"3:04AM"`)
+
+	b = slog.NewLogfmtFormatter(slog.TimeFormat{Layout: "2006-01-02", Location: tokyo}).Format(item)
+	require.Equal(t, "ts=2015-01-02 level=info source=parent msg=\"( main.go:12 ) hi\"\n", string(b))
+
+}
+
+func TestTextFormatterLines(t *testing.T) {
+
+	when := time.Date(2015, 1, 2, 3, 4, 5, 0, time.UTC)
+	b := slog.TextFormatter.Format(&slog.Log{
+		Level:  slog.LevelErr,
+		When:   when,
+		Source: []string{"parent"},
+		Data:   []interface{}{"config:\n  a: 1\n  b: 2\n"},
+		Stack:  "goroutine 1 [running]:\nmain.main()\n",
+	})
+
+	require.Equal(t, `2015/01/02 03:04:05 parent: config:
+	  a: 1
+	  b: 2
+	goroutine 1 [running]:
+	main.main()
+`, string(b))
+
+}
+
+func TestWriter(t *testing.T) {
+
+	l := slog.New("parent", slog.LevelInfo)
+	r := NewTestReporter()
+	l.SetReporter(r)
+
+	std := log.New(slog.Writer(l.New("std"), slog.LevelWarn), "prefix: ", 0)
+	std.Println("from the log package")
+
+	w := slog.Writer(l, slog.LevelInfo)
+	w.Write([]byte("first\r\nsec"))
+	w.Write([]byte("ond\n\n"))
+	w.Write([]byte("unfinished"))
+	slog.Writer(l, slog.LevelDebug).Write([]byte("skipped\n"))
+	require.NoError(t, l.StopContext(context.Background()))
+
+	require.Equal(t, 3, len(r.logs))
+	require.Equal(t, slog.LevelWarn, r.logs[0].Level)
+	require.Equal(t, slog.Source{"parent", "std"}, r.logs[0].Source)
+	require.Equal(t, "prefix: from the log package", r.logs[0].Data[1])
+	require.Equal(t, "first", r.logs[1].Data[1])
+	for _, log := range r.logs {
+		require.True(t, strings.HasPrefix(log.Location(), "writer_test.go:"), log.Location())
+	}
+	require.Equal(t, "second", r.logs[2].Data[1])
+
+}
+
+func TestWriterLongLine(t *testing.T) {
+
+	l := slog.New("parent", slog.LevelInfo)
+	r := NewTestReporter()
+	l.SetReporter(r)
+
+	w := slog.Writer(l, slog.LevelInfo)
+	long := strings.Repeat("x", 64<<10)
+	for i := 0; i < 3; i++ {
+		w.Write([]byte(long[:len(long)/2]))
+	}
+	w.Write([]byte("end\n"))
+	require.NoError(t, l.StopContext(context.Background()))
+
+	require.Equal(t, 2, len(r.logs))
+	require.Equal(t, long, r.logs[0].Data[1])
+	require.Equal(t, long[:len(long)/2]+"end", r.logs[1].Data[1])
+
+}
+
+func TestRedirectStdLog(t *testing.T) {
+
+	l := slog.New("parent", slog.LevelInfo)
+	r := NewTestReporter()
+	l.SetReporter(r)
+
+	var buf bytes.Buffer
+	log.SetOutput(&buf)
+	defer log.SetOutput(os.Stderr)
+
+	restore := slog.RedirectStdLog(l, slog.LevelInfo)
+	log.Printf("redirected %d", 1)
+	restore()
+	log.Print("not redirected")
+	require.NoError(t, l.StopContext(context.Background()))
+
+	require.Equal(t, 1, len(r.logs))
+	require.Equal(t, "redirected 1", r.logs[0].Data[1])
+	require.True(t, strings.HasPrefix(r.logs[0].Location(), "writer_test.go:"), r.logs[0].Location())
+	require.Contains(t, buf.String(), "not redirected")
+	require.Equal(t, log.LstdFlags, log.Flags())
+
+}